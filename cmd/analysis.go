@@ -14,9 +14,9 @@
 package cmd
 
 import (
-	"github.com/VirusTotal/vt-cli/utils"
 	"regexp"
 
+	"github.com/VirusTotal/vt-cli/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +34,9 @@ var analysisCmdExample = `  vt analysis f-e04b82f7f8afc6e599d4913bee5eb571921ec8
   vt analysis u-1db0ad7dbcec0676710ea0eaacd35d5e471d3e11944d53bcbd31f0cbd11bce31-1542292491
   cat list_of_analysis_ids | vt analysis -`
 
+var validateAnalysisID = utils.ValidateRegexp(
+	regexp.MustCompile(`^((f|u)-[[:xdigit:]]{64}-\d+|[\d\w=]{20,})$`), "malformed analysis ID")
+
 // NewAnalysisCmd returns a new instance of the 'analysis' command.
 func NewAnalysisCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -45,21 +48,27 @@ func NewAnalysisCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			re, _ := regexp.Compile(`(f|u)-[[:xdigit:]]{64}-\d+|[\d\w=]{20,}`)
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, validateAnalysisID)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"analyses/%s",
-				utils.StringReaderFromCmdArgs(args),
-				re)
+				r,
+				nil)
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 
 	return cmd
 }
@@ -0,0 +1,273 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/VirusTotal/vt-cli/coordinator"
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+var attackTechniqueRe = regexp.MustCompile(`^T\d{4}(\.\d{3})?$`)
+
+var validateAttackTechniqueID = utils.ValidateRegexp(
+	attackTechniqueRe, "malformed technique ID, expecting Tnnnn or Tnnnn.nnn")
+
+var attackTechniqueCmdHelp = `Get information about MITRE ATT&CK techniques.
+
+This command receives one or more technique identifiers (e.g. T1059 or
+T1059.001) and returns information about them. The information for each
+technique is returned in the same order as the techniques are passed to the
+command.
+
+If the command receives a single hypen (-) the techniques are read from the
+standard input, one per line.`
+
+var attackTechniqueCmdExample = `  vt attack technique T1059
+  vt attack technique T1059 T1059.001
+  vt attack technique subtechniques T1059`
+
+// NewAttackTechniqueCmd returns a new instance of the 'attack technique'
+// command.
+func NewAttackTechniqueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "technique [technique]...",
+		Short:   "Get information about MITRE ATT&CK techniques",
+		Long:    attackTechniqueCmdHelp,
+		Example: attackTechniqueCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := NewPrinter(cmd)
+			if err != nil {
+				return err
+			}
+			r, err := NewStringReader(cmd, args, nil, validateAttackTechniqueID)
+			if err != nil {
+				return err
+			}
+			return p.GetAndPrintObjects(
+				"attack_techniques/%s",
+				r,
+				nil)
+		},
+	}
+
+	addRelationshipCmds(cmd, "attack_techniques", "attack_technique", "[technique]", false)
+	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
+	addIncludeExcludeFlags(cmd.Flags())
+	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
+
+	return cmd
+}
+
+// attackMatrixSink is a coordinator sink that adds the MITRE trees retrieved
+// for each file to a matrix. Files for which the trees couldn't be retrieved
+// are reported in stderr.
+type attackMatrixSink struct {
+	matrix *utils.AttackMatrix
+	stderr io.Writer
+}
+
+func (s *attackMatrixSink) Put(r coordinator.Result[string, utils.MitreTrees]) error {
+	if r.Err != nil {
+		fmt.Fprintf(s.stderr, "%s: %v\n", r.Input, r.Err)
+		return nil
+	}
+	s.matrix.Add(r.Input, r.Output)
+	return nil
+}
+
+func (s *attackMatrixSink) Close() error {
+	s.matrix.Sort()
+	return nil
+}
+
+// buildAttackMatrix retrieves the MITRE trees for the sandbox behaviours of
+// the files read from r, and aggregates them in a matrix. It fails if the
+// trees couldn't be retrieved for any of the files.
+func buildAttackMatrix(client *utils.APIClient, r utils.StringReader, opts *utils.Options) (*utils.AttackMatrix, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hashes, readErr := utils.StringChannel(ctx, r)
+
+	m := &utils.AttackMatrix{}
+	err := coordinator.New[string, utils.MitreTrees](
+		opts.Threads, &attackMatrixSink{matrix: m, stderr: opts.Stderr}).Run(
+		ctx,
+		coordinator.DoerFunc[string, utils.MitreTrees](
+			func(_ context.Context, hash string, _ *coordinator.State) (utils.MitreTrees, error) {
+				var trees utils.MitreTrees
+				_, err := client.GetData(vt.URL("files/%s/behaviour_mitre_trees", hash), &trees)
+				return trees, err
+			}),
+		hashes)
+	if err != nil {
+		return nil, err
+	}
+	if err := readErr(); err != nil {
+		return nil, err
+	}
+	if m.Samples == 0 {
+		return nil, fmt.Errorf("couldn't retrieve the MITRE ATT&CK trees for any of the files")
+	}
+	return m, nil
+}
+
+// attackMatrixTable writes the matrix to w with one column per tactic, as it
+// is displayed by MITRE.
+func attackMatrixTable(w io.Writer, m *utils.AttackMatrix) {
+	var tactics []string
+	columns := make(map[string][]string)
+	for _, u := range m.Techniques {
+		if _, ok := columns[u.Tactic]; !ok {
+			tactics = append(tactics, u.Tactic)
+		}
+		columns[u.Tactic] = append(columns[u.Tactic],
+			fmt.Sprintf("%s %s (%d)", u.ID, u.Name, len(u.Samples)))
+	}
+	table := uitable.New()
+	table.MaxColWidth = 30
+	table.Wrap = true
+	header := make([]interface{}, len(tactics))
+	height := 0
+	for i, tactic := range tactics {
+		header[i] = strings.ToUpper(tactic)
+		if len(columns[tactic]) > height {
+			height = len(columns[tactic])
+		}
+	}
+	table.AddRow(header...)
+	for row := 0; row < height; row++ {
+		cells := make([]interface{}, len(tactics))
+		for i, tactic := range tactics {
+			if row < len(columns[tactic]) {
+				cells[i] = columns[tactic][row]
+			} else {
+				cells[i] = ""
+			}
+		}
+		table.AddRow(cells...)
+	}
+	fmt.Fprintln(w, table)
+}
+
+var attackMatrixCmdHelp = `Build a MITRE ATT&CK matrix for a set of files.
+
+This command receives one or more file hashes, retrieves the MITRE ATT&CK
+techniques observed in the sandbox behaviours of each file and aggregates them
+in a tactic-by-technique matrix. For each technique the number of samples where
+it was observed is reported.
+
+By default the matrix is printed as a list with one entry per technique, use
+--format csv for a CSV file. With --human the matrix is printed as a table with
+one column per tactic, and with --navigator it is printed as an ATT&CK
+Navigator layer where the score of each technique is the number of samples.
+
+If the command receives a single hypen (-) the hashes are read from the
+standard input, one per line.`
+
+var attackMatrixCmdExample = `  vt attack matrix 8739c76e681f900923b900c9df0ef75cf421d39cabb54650c4b9ad19b6a76d85
+  cat incident_hashes | vt attack matrix - --human
+  cat incident_hashes | vt attack matrix - --format csv > matrix.csv
+  cat incident_hashes | vt attack matrix - --navigator --layer-name "Incident 42" > layer.json`
+
+// NewAttackMatrixCmd returns a new instance of the 'attack matrix' command.
+func NewAttackMatrixCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "matrix [hash]...",
+		Short:   "Build a MITRE ATT&CK matrix for a set of files",
+		Long:    attackMatrixCmdHelp,
+		Example: attackMatrixCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			if opts.GetBool("human") && opts.GetBool("navigator") {
+				return fmt.Errorf("--human and --navigator can't be used together")
+			}
+			client, err := NewAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			p, err := NewPrinter(cmd)
+			if err != nil {
+				return err
+			}
+			r, err := NewStringReader(cmd, args, nil, nil)
+			if err != nil {
+				return err
+			}
+			m, err := buildAttackMatrix(client, r, opts)
+			if err != nil {
+				return err
+			}
+			switch {
+			case opts.GetBool("navigator"):
+				b, err := json.MarshalIndent(m.NavigatorLayer(opts.GetString("layer-name")), "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(opts.Stdout, string(b))
+				return nil
+			case opts.GetBool("human"):
+				attackMatrixTable(opts.Stdout, m)
+				return nil
+			}
+			return p.Print(m.Rows())
+		},
+	}
+
+	cmd.Flags().Bool(
+		"navigator", false,
+		"print the matrix as an ATT&CK Navigator layer")
+	cmd.Flags().String(
+		"layer-name", "vt-cli",
+		"name of the ATT&CK Navigator layer, used with --navigator")
+
+	addThreadsFlag(cmd.Flags())
+	addHumanFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+
+	return cmd
+}
+
+// NewAttackCmd returns a new instance of the 'attack' command.
+func NewAttackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attack",
+		Short: "Get information about MITRE ATT&CK techniques",
+	}
+
+	cmd.AddCommand(NewAttackTechniqueCmd())
+	cmd.AddCommand(NewAttackMatrixCmd())
+
+	return cmd
+}
@@ -0,0 +1,269 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+var certCmdHelp = `Get information about one or more SSL certificates.
+
+This command receives one or more SHA-256 thumbprints of SSL certificates and
+returns information about them. The data is returned in the same order as the
+thumbprints appear in the command line.
+
+If the command receives a single hypen (-) the thumbprints are read from the
+standard input, one per line.`
+
+var certCmdExample = `  vt cert 5a0c9d3c8f6e3b0e5b1c1f0ad33b1b2cc43b6b30dbbc2a0d6c1bd1d68b9fbd28
+  cat list_of_thumbprints | vt cert -`
+
+// NewCertCmd returns a new instance of the 'cert' command.
+func NewCertCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cert [thumbprint]...",
+		Short:   "Get information about SSL certificates",
+		Long:    certCmdHelp,
+		Example: certCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := NewPrinter(cmd)
+			if err != nil {
+				return err
+			}
+			r, err := NewStringReader(cmd, args, utils.NormalizeHash, utils.ValidateSHA256)
+			if err != nil {
+				return err
+			}
+			return p.GetAndPrintObjects("ssl_certificates/%s", r, nil)
+		},
+	}
+
+	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
+	addIncludeExcludeFlags(cmd.Flags())
+	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
+	addStrictFlag(cmd.Flags())
+
+	cmd.AddCommand(NewCertPivotCmd())
+
+	return cmd
+}
+
+// certPivotRow is a host found by pivoting on a certificate, with the last
+// HTTPS certificate of the host.
+type certPivotRow struct {
+	Source     string   `json:"source" yaml:"source" csv:"source"`
+	Host       string   `json:"host" yaml:"host" csv:"host"`
+	Type       string   `json:"type" yaml:"type" csv:"type"`
+	Methods    []string `json:"methods" yaml:"methods" csv:"methods"`
+	Thumbprint string   `json:"thumbprint" yaml:"thumbprint" csv:"thumbprint"`
+	Subject    string   `json:"subject" yaml:"subject" csv:"subject"`
+	Issuer     string   `json:"issuer" yaml:"issuer" csv:"issuer"`
+	NotBefore  string   `json:"not_before" yaml:"not_before" csv:"not_before"`
+	NotAfter   string   `json:"not_after" yaml:"not_after" csv:"not_after"`
+}
+
+func newCertPivotRow(source string, h *utils.CertHost) certPivotRow {
+	row := certPivotRow{Source: source, Host: h.Object.ID(), Type: h.Object.Type(), Methods: h.Methods}
+	get := func(attr string) string {
+		s, _ := h.Object.GetString("last_https_certificate." + attr)
+		return s
+	}
+	row.Thumbprint = get("thumbprint_sha256")
+	row.Subject = get("subject.CN")
+	if row.Issuer = get("issuer.O"); row.Issuer == "" {
+		row.Issuer = get("issuer.CN")
+	}
+	row.NotBefore = get("validity.not_before")
+	row.NotAfter = get("validity.not_after")
+	return row
+}
+
+// newCertSource returns the certificate pivots start from, which is the
+// certificate with the given thumbprint or the last HTTPS certificate of a
+// domain or IP address.
+func newCertSource(client *utils.APIClient, arg string) (*utils.CertSource, error) {
+	if utils.ValidateSHA256(arg) == nil {
+		obj, err := client.GetObject(vt.URL("ssl_certificates/%s", utils.NormalizeHash(arg)))
+		if err != nil {
+			return nil, err
+		}
+		return utils.NewCertSourceFromCert(obj), nil
+	}
+	path := "domains/" + utils.NormalizeDomain(arg)
+	if net.ParseIP(arg) != nil {
+		path = "ip_addresses/" + arg
+	}
+	obj, err := client.GetObject(vt.URL(path))
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewCertSourceFromHost(obj)
+}
+
+// findCertHosts searches the domains and IP addresses related to the source
+// by each of the given methods.
+func findCertHosts(client *utils.APIClient, source *utils.CertSource, queries map[string]string, limit int) ([]*utils.CertHost, error) {
+	set := utils.NewCertHostSet(source)
+	for _, m := range utils.CertPivotMethods {
+		q, ok := queries[m]
+		if !ok {
+			continue
+		}
+		for _, entity := range []string{"domain", "ip"} {
+			it, err := client.Search(fmt.Sprintf("entity:%s %s", entity, q),
+				vt.IteratorLimit(limit),
+				vt.IteratorBatchSize(min(limit, searchStatsBatchSize)))
+			if err != nil {
+				return nil, err
+			}
+			for it.Next() {
+				set.Add(m, it.Get())
+			}
+			err = it.Error()
+			it.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return set.Hosts(), nil
+}
+
+func runCertPivotCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	methods := opts.GetStringSlice("methods")
+	for _, m := range methods {
+		if !slices.Contains(utils.CertPivotMethods, m) {
+			return fmt.Errorf("unknown pivot method %q", m)
+		}
+	}
+
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	var rows []certPivotRow
+	for _, arg := range args {
+		source, err := newCertSource(client, arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		queries := source.Queries(methods)
+		if !opts.Silent {
+			var used []string
+			for _, m := range utils.CertPivotMethods {
+				if _, ok := queries[m]; ok {
+					used = append(used, m)
+				}
+			}
+			fmt.Fprintf(opts.Stderr, "%s: searching by %s\n", arg, strings.Join(used, ", "))
+		}
+		hosts, err := findCertHosts(client, source, queries, opts.Limit)
+		if err != nil {
+			return err
+		}
+		for _, h := range hosts {
+			rows = append(rows, newCertPivotRow(arg, h))
+		}
+	}
+
+	if opts.IdentifiersOnly {
+		for _, row := range rows {
+			fmt.Fprintln(opts.Stdout, row.Host)
+		}
+		return nil
+	}
+
+	if opts.GetBool("human") {
+		table := uitable.New()
+		table.MaxColWidth = 50
+		table.AddRow("SOURCE", "HOST", "METHODS", "SUBJECT", "ISSUER", "NOT BEFORE", "NOT AFTER")
+		for _, row := range rows {
+			table.AddRow(row.Source, row.Host, strings.Join(row.Methods, ","),
+				row.Subject, row.Issuer, row.NotBefore, row.NotAfter)
+		}
+		fmt.Fprintln(opts.Stdout, table)
+		return nil
+	}
+
+	p, err := NewPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.Print(rows)
+}
+
+var certPivotCmdHelp = `Find hosts related to a certificate.
+
+This command receives domains, IP addresses or SHA-256 thumbprints of SSL
+certificates, and searches VirusTotal Intelligence for other domains and IP
+addresses related to the last HTTPS certificate of the given hosts, or to the
+given certificates, by any of the methods given with --methods:
+
+  thumbprint      hosts presenting the same certificate.
+  issuer_subject  hosts presenting a certificate with the same issuer and
+                  subject common names.
+  jarm            hosts with the same JARM fingerprint, which identifies the
+                  configuration of the TLS server. Only available for domains
+                  and IP addresses.
+
+The hosts found by the different methods are merged and printed with their
+last HTTPS certificate and its validity dates, first the ones found by more
+methods. The --limit flag applies to each search, and each method searches
+domains and IP addresses separately.`
+
+var certPivotCmdExample = `  vt cert pivot example.com
+  vt cert pivot 1.2.3.4 --methods thumbprint,jarm --human
+  vt cert pivot 5a0c9d3c8f6e3b0e5b1c1f0ad33b1b2cc43b6b30dbbc2a0d6c1bd1d68b9fbd28 -I`
+
+// NewCertPivotCmd returns a new instance of the 'cert pivot' command.
+func NewCertPivotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pivot [domain|ip|thumbprint]...",
+		Short:   "Find hosts related to a certificate",
+		Long:    certPivotCmdHelp,
+		Example: certPivotCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runCertPivotCmd,
+	}
+
+	cmd.Flags().StringSlice(
+		"methods", utils.CertPivotMethods,
+		"pivot methods used")
+	cmd.Flags().IntP(
+		"limit", "n", 20,
+		"maximum number of hosts found by each search")
+
+	addHumanFlag(cmd.Flags())
+	addIDOnlyFlag(cmd.Flags())
+
+	return cmd
+}
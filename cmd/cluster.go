@@ -0,0 +1,191 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/VirusTotal/vt-cli/pkg/vtcli"
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+func runClusterCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if opts.GetBool("graph") && opts.GetBool("human") {
+		return errors.New("--graph and --human can't be used together")
+	}
+	c, err := utils.NewClusterer(opts.GetStringSlice("by"), opts.GetInt("tlsh-distance"))
+	if err != nil {
+		return err
+	}
+
+	r, err := NewStringReader(cmd, args, utils.NormalizeHash, utils.ValidateHash)
+	if err != nil {
+		return err
+	}
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hashes, readErr := utils.StringChannel(ctx, r)
+	var results <-chan vtcli.BatchResult
+	if opts.GetBool("batch") {
+		results = client.Wrapped().BatchFilesStream(ctx, hashes, false)
+	} else {
+		results = client.Wrapped().BatchStream(ctx, []string{"files/%s"}, hashes)
+	}
+	// Files can be given by different hashes, each one is clustered once.
+	seen := make(map[string]bool)
+	notFound := 0
+	for res := range results {
+		if vtcli.IsNotFound(res.Err) {
+			fmt.Fprintf(opts.Stderr, "%s: not found\n", res.ID)
+			notFound++
+			continue
+		} else if res.Err != nil {
+			return res.Err
+		}
+		if !seen[res.Object.ID()] {
+			seen[res.Object.ID()] = true
+			c.Add(res.Object)
+		}
+	}
+	if err := readErr(); err != nil {
+		return err
+	}
+
+	var clusters []*utils.Cluster
+	for _, cl := range c.Clusters() {
+		if cl.Size >= opts.GetInt("min-size") {
+			clusters = append(clusters, cl)
+		}
+	}
+	if !opts.Silent {
+		fmt.Fprintf(opts.Stderr, "%d files in %d clusters, %d not found\n",
+			len(seen), len(clusters), notFound)
+	}
+
+	switch {
+	case opts.GetBool("graph"):
+		return c.WriteDOT(opts.Stdout, clusters)
+	case opts.GetBool("human"):
+		// Each cluster takes as many lines as representatives or shared
+		// features it has.
+		table := uitable.New()
+		table.MaxColWidth = 80
+		table.AddRow("CLUSTER", "SIZE", "REPRESENTATIVES", "SHARED")
+		for _, cl := range clusters {
+			for i := 0; i < max(len(cl.Representatives), len(cl.Shared)); i++ {
+				var id, size, rep, shared string
+				if i == 0 {
+					id, size = fmt.Sprint(cl.ID), fmt.Sprint(cl.Size)
+				}
+				if i < len(cl.Representatives) {
+					rep = cl.Representatives[i]
+				}
+				if i < len(cl.Shared) {
+					s := cl.Shared[i]
+					shared = fmt.Sprintf("%s=%s (%d)", s.Feature, s.Value, s.Count)
+				}
+				table.AddRow(id, size, rep, shared)
+			}
+		}
+		fmt.Fprintln(opts.Stdout, table)
+		return nil
+	}
+
+	p, err := NewPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.Print(clusters)
+}
+
+var clusterCmdHelp = `Group files by the attributes they share.
+
+This command retrieves the given files from VirusTotal and groups them in
+clusters of files that share any of the features given with --by:
+
+  imphash              the import hash of PE files.
+  vhash                the VirusTotal structural similarity hash.
+  signer               the signer of the file's Authenticode signature.
+  rich_pe_header_hash  the hash of the Rich header of PE files.
+  icon_dhash           the difference hash of the file's main icon.
+  yara                 the names of the crowdsourced YARA rules that match
+                       the file.
+  tlsh                 the TLSH hash, files whose hashes have a distance lower
+                       or equal than --tlsh-distance are linked.
+
+Files are in the same cluster if they share a feature or if they are linked
+through other files in the cluster. For each cluster the command prints its
+members, the feature values shared by them with the number of files that have
+each one, and the representatives: the files linked to more files in the
+cluster. Clusters are sorted by size, from the largest.
+
+With --graph the clusters are printed as a Graphviz graph where files are
+connected to the feature values they share, which can be rendered with the
+dot command.
+
+If the command receives a single hyphen (-) the hashes are read from the
+standard input, one per line.`
+
+var clusterCmdExample = `  vt cluster 44d88612fea8a8f36de82e1278abb02f 3395856ce81f2b7382dee72602f798b642f14140
+  cat incident_hashes | vt cluster - --human --min-size 2
+  cat incident_hashes | vt cluster - --by imphash,signer,tlsh --tlsh-distance 30
+  cat incident_hashes | vt cluster - --graph | dot -Tsvg > clusters.svg`
+
+// NewClusterCmd returns a new instance of the 'cluster' command.
+func NewClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "cluster [hash]...",
+		Short:   "Group files by the attributes they share",
+		Long:    clusterCmdHelp,
+		Example: clusterCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runClusterCmd,
+	}
+
+	cmd.Flags().StringSlice(
+		"by", utils.ClusterFeatures,
+		"features used for grouping files")
+	cmd.Flags().Int(
+		"tlsh-distance", 50,
+		"maximum TLSH distance between linked files")
+	cmd.Flags().Int(
+		"min-size", 1,
+		"print only clusters with at least this number of files")
+	cmd.Flags().Bool(
+		"graph", false,
+		"print the clusters as a Graphviz DOT graph")
+	cmd.Flags().Bool(
+		"batch", false,
+		"retrieve many files per request using VirusTotal Intelligence searches")
+
+	addHumanFlag(cmd.Flags())
+	addThreadsFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
+	addStrictFlag(cmd.Flags())
+
+	return cmd
+}
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -41,7 +42,7 @@ func addAPIKeyFlag(flags *pflag.FlagSet) {
 func addFormatFlag(flags *pflag.FlagSet) {
 	flags.String(
 		"format", "yaml",
-		"Output format (yaml/json/csv)")
+		"Output format (yaml/json/csv/ecs/cef/leef)")
 }
 
 func addHostFlag(flags *pflag.FlagSet) {
@@ -74,6 +75,60 @@ func addThreadsFlag(flags *pflag.FlagSet) {
 		"number of threads working in parallel")
 }
 
+func addUnorderedFlag(flags *pflag.FlagSet) {
+	flags.Bool(
+		"unordered", false,
+		"print results as soon as they are retrieved, not in the input order")
+}
+
+func addInputFlags(flags *pflag.FlagSet) {
+	flags.Bool(
+		"dedupe", false,
+		"skip repeated arguments")
+	flags.Int(
+		"input-column", 0,
+		"read arguments from this column of a CSV input (starting at 1)")
+	flags.String(
+		"input-csv-field", "",
+		"read arguments from this field of a CSV input with header or JSON Lines input")
+}
+
+func addNormalizeFlag(flags *pflag.FlagSet) {
+	flags.Bool(
+		"normalize", false,
+		"normalize arguments before using them")
+}
+
+func addStrictFlag(flags *pflag.FlagSet) {
+	flags.Bool(
+		"strict", false,
+		"fail without making any request if some argument is invalid")
+}
+
+func addExportFlags(flags *pflag.FlagSet) {
+	flags.String(
+		"to-collection", "",
+		"put the results in a new collection with this name")
+	flags.String(
+		"append-to", "",
+		"add the results to the collection with this ID")
+}
+
+func addLintFlag(flags *pflag.FlagSet) {
+	flags.Bool(
+		"lint", false,
+		"check the query for errors and expand relative dates before searching")
+}
+
+func addDateRangeFlags(flags *pflag.FlagSet) {
+	flags.String(
+		"from", "",
+		"only since this date: YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS or relative like 7d")
+	flags.String(
+		"to", "",
+		"only until this date: YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS or relative like 7d")
+}
+
 func addRecursive(flags *pflag.FlagSet) {
 	flags.BoolP(
 		"recursive", "r", false,
@@ -98,6 +153,12 @@ func addLimitFlag(flags *pflag.FlagSet) {
 		"maximum number of results")
 }
 
+func addAllFlag(flags *pflag.FlagSet) {
+	flags.BoolP(
+		"all", "a", false,
+		"retrieve all results, ignoring --limit")
+}
+
 func addCursorFlag(flags *pflag.FlagSet) {
 	flags.StringP(
 		"cursor", "c", "",
@@ -149,16 +210,68 @@ func ReadFile(filename string) ([]byte, error) {
 	return os.ReadFile(filename)
 }
 
-// NewAPIClient returns a new utils.APIClient.
-func NewAPIClient() (*utils.APIClient, error) {
-	return utils.NewAPIClient(fmt.Sprintf("vt-cli %s", Version))
+// NewStringReader returns a utils.StringReader for the arguments passed to
+// cmd, which are read from stdin if they consist of a single hyphen. The
+// reader honours the flags added by addInputFlags, addNormalizeFlag and
+// addStrictFlag. The arguments are normalized with normalize and validated
+// with validate, both can be nil.
+func NewStringReader(cmd *cobra.Command, args []string, normalize func(string) string, validate utils.Validator) (utils.StringReader, error) {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewInputReader(opts, args, normalize, validate)
+}
+
+// dateRange returns the dates given with the flags added by
+// addDateRangeFlags, which are zero if not set. A --to date without time
+// includes the whole day.
+func dateRange(opts *utils.Options) (from, to time.Time, err error) {
+	now := time.Now()
+	if s := opts.GetString("from"); s != "" {
+		if from, err = utils.ParseTimeBound(s, now); err != nil {
+			return
+		}
+	}
+	if s := opts.GetString("to"); s != "" {
+		if to, err = utils.ParseTimeBound(s, now); err != nil {
+			return
+		}
+		if len(s) == len("2006-01-02") {
+			to = to.Add(24*time.Hour - time.Second)
+		}
+	}
+	return
+}
+
+// NewAPIClient returns a new utils.APIClient configured with the options for
+// the current invocation of cmd.
+func NewAPIClient(cmd *cobra.Command) (*utils.APIClient, error) {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewAPIClient(fmt.Sprintf("vt-cli %s", Version), opts)
 }
 
 // NewPrinter creates a new utils.Printer.
 func NewPrinter(cmd *cobra.Command) (*utils.Printer, error) {
-	client, err := NewAPIClient()
+	client, err := NewAPIClient(cmd)
 	if err != nil {
 		return nil, err
 	}
 	return utils.NewPrinter(client, cmd, &colorScheme)
 }
+
+// NewCoordinator creates a new utils.Coordinator configured with the options
+// for the current invocation of cmd.
+func NewCoordinator(cmd *cobra.Command) (*utils.Coordinator, error) {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+	c := utils.NewCoordinator(opts.Threads)
+	c.Silent = opts.Silent
+	c.Output = opts.Stdout
+	return c, nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -11,7 +12,6 @@ import (
 
 	"github.com/VirusTotal/vt-cli/utils"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 type objectDescriptor struct {
@@ -47,9 +47,13 @@ func NewCollectionCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, nil)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"collections/%s",
-				utils.StringReaderFromCmdArgs(args),
+				r,
 				nil)
 		},
 	}
@@ -62,8 +66,10 @@ func NewCollectionCmd() *cobra.Command {
 
 	addRelationshipCmds(cmd, "collections", "collection", "[collection]", false)
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
 
 	return cmd
 }
@@ -90,7 +96,11 @@ func NewCollectionCreateCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := NewAPIClient()
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			c, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -98,18 +108,24 @@ func NewCollectionCreateCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			reader := utils.StringReaderFromCmdArgs(args)
-
-			collection := vt.NewObject("collection")
-			collection.SetString("name", viper.GetString("name"))
-			collection.SetString("description", viper.GetString("description"))
-			collection.SetData("raw_items", rawFromReader(reader))
+			reader, err := NewStringReader(cmd, args, nil, nil)
+			if err != nil {
+				return err
+			}
 
-			if err := c.PostObject(vt.URL("collections"), collection); err != nil {
+			raw, err := rawFromReader(reader)
+			if err != nil {
+				return err
+			}
+			collection, err := createCollection(c,
+				opts.GetString("name"),
+				opts.GetString("description"),
+				raw)
+			if err != nil {
 				return err
 			}
 
-			if viper.GetBool("identifiers-only") {
+			if opts.IdentifiersOnly {
 				fmt.Printf("%s\n", collection.ID())
 			} else {
 				if err := p.PrintObject(collection); err != nil {
@@ -131,12 +147,13 @@ func NewCollectionCreateCmd() *cobra.Command {
 	_ = cmd.MarkFlagRequired("description")
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
 
 	return cmd
 }
 
-func patchCollection(id, attr string, value interface{}) error {
-	client, err := NewAPIClient()
+func patchCollection(cmd *cobra.Command, id, attr string, value interface{}) error {
+	client, err := NewAPIClient(cmd)
 	if err != nil {
 		return err
 	}
@@ -153,7 +170,7 @@ func NewCollectionRenameCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(2),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return patchCollection(args[0], "name", args[1])
+			return patchCollection(cmd, args[0], "name", args[1])
 		},
 	}
 }
@@ -172,7 +189,7 @@ var updateCollectionExample = `  vt collection update [collection id] www.exampl
 
 // NewCollectionUpdateCmd returns a command for adding new items to a collection.
 func NewCollectionUpdateCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "update [collection id] [ioc]...",
 		Short:   "Add new items to a collection.",
 		Args:    cobra.MinimumNArgs(2),
@@ -180,7 +197,11 @@ func NewCollectionUpdateCmd() *cobra.Command {
 		Example: updateCollectionExample,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := NewAPIClient()
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			c, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -189,15 +210,21 @@ func NewCollectionUpdateCmd() *cobra.Command {
 				return err
 			}
 
-			collection := vt.NewObjectWithID("collection", args[0])
-			reader := utils.StringReaderFromCmdArgs(args[1:])
-			collection.SetData("raw_items", rawFromReader(reader))
+			reader, err := NewStringReader(cmd, args[1:], nil, nil)
+			if err != nil {
+				return err
+			}
 
-			if err := c.PatchObject(vt.URL("collections/%s", args[0]), collection); err != nil {
+			raw, err := rawFromReader(reader)
+			if err != nil {
+				return err
+			}
+			collection, err := addToCollection(c, args[0], raw)
+			if err != nil {
 				return err
 			}
 
-			if viper.GetBool("identifiers-only") {
+			if opts.IdentifiersOnly {
 				fmt.Printf("%s\n", collection.ID())
 			} else {
 				if err := p.PrintObject(collection); err != nil {
@@ -208,6 +235,10 @@ func NewCollectionUpdateCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	addInputFlags(cmd.Flags())
+
+	return cmd
 }
 
 var removeCollectionItemsCmdHelp = `Remove items from a collection.
@@ -224,7 +255,7 @@ var removeCollectionItemsExample = `  vt collection remove [collection id] www.e
 
 // NewCollectionRemoveItemsCmd returns a command for removing items from a collection.
 func NewCollectionRemoveItemsCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:     "remove [collection id] [ioc]...",
 		Short:   "Remove items from a collection.",
 		Args:    cobra.MinimumNArgs(2),
@@ -232,12 +263,18 @@ func NewCollectionRemoveItemsCmd() *cobra.Command {
 		Example: removeCollectionItemsExample,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := NewAPIClient()
+			c, err := NewAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			reader, err := NewStringReader(cmd, args[1:], nil, nil)
+			if err != nil {
+				return err
+			}
+			relationshipDescriptors, err := descriptorsFromReader(reader)
 			if err != nil {
 				return err
 			}
-			relationshipDescriptors := descriptorsFromReader(
-				utils.StringReaderFromCmdArgs(args[1:]))
 			for relationshipName, descriptors := range relationshipDescriptors {
 				url := vt.URL("collections/%s/%s", args[0], relationshipName)
 				response, err := c.DeleteData(url, descriptors)
@@ -251,6 +288,10 @@ func NewCollectionRemoveItemsCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	addInputFlags(cmd.Flags())
+
+	return cmd
 }
 
 var deleteCollectionCmdHelp = `Delete a collection.
@@ -269,7 +310,7 @@ func NewCollectionDeleteCmd() *cobra.Command {
 		Example: deleteCollectionExample,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := NewAPIClient()
+			c, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -286,19 +327,164 @@ func NewCollectionDeleteCmd() *cobra.Command {
 	}
 }
 
-func rawFromReader(reader utils.StringReader) string {
+// createCollection creates a collection with the IoCs found in rawItems, which
+// is free-form text.
+func createCollection(client *utils.APIClient, name, description, rawItems string) (*vt.Object, error) {
+	collection := vt.NewObject("collection")
+	collection.SetString("name", name)
+	collection.SetString("description", description)
+	collection.SetData("raw_items", rawItems)
+	return collection, client.PostObject(vt.URL("collections"), collection)
+}
+
+// addToCollection adds the IoCs found in rawItems to an existing collection.
+func addToCollection(client *utils.APIClient, id, rawItems string) (*vt.Object, error) {
+	collection := vt.NewObjectWithID("collection", id)
+	collection.SetData("raw_items", rawItems)
+	return collection, client.PatchObject(vt.URL("collections/%s", id), collection)
+}
+
+// collectionBatchSize is the maximum number of items sent in each request by
+// exportToCollection.
+const collectionBatchSize = 500
+
+// exportRequested returns true if the command was told to put its results in
+// a collection with --to-collection or --append-to.
+func exportRequested(opts *utils.Options) bool {
+	return opts.GetString("to-collection") != "" || opts.GetString("append-to") != ""
+}
+
+// checkExportFlags checks that --to-collection and --append-to are not used
+// together, nor with any of the given flags.
+func checkExportFlags(cmd *cobra.Command, incompatible ...string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if !exportRequested(opts) {
+		return nil
+	}
+	if cmd.Flag("to-collection").Changed && cmd.Flag("append-to").Changed {
+		return errors.New("--to-collection and --append-to can't be used together")
+	}
+	for _, flag := range incompatible {
+		if cmd.Flag(flag).Changed {
+			return fmt.Errorf("--%s can't be used with --to-collection or --append-to", flag)
+		}
+	}
+	return nil
+}
+
+// exportToCollection adds the objects returned by it to the collection
+// specified with --append-to, or to a new collection named as specified with
+// --to-collection and with the given description. Objects are sent in batches
+// of collectionBatchSize, so any number of them can be exported. When done
+// the collection ID and the number of items added are printed.
+func exportToCollection(cmd *cobra.Command, it *vt.Iterator, description string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+	id := opts.GetString("append-to")
+	added := 0
+	batch := make([]string, 0, collectionBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		raw := strings.Join(batch, " ")
+		if id == "" {
+			collection, err := createCollection(client, opts.GetString("to-collection"), description, raw)
+			if err != nil {
+				return err
+			}
+			id = collection.ID()
+		} else if _, err := addToCollection(client, id, raw); err != nil {
+			return fmt.Errorf("adding items to collection %s after %d items: %w", id, added, err)
+		}
+		added += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	for it.Next() {
+		batch = append(batch, collectionItem(it.Get()))
+		if len(batch) == collectionBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if id == "" {
+		fmt.Fprintln(opts.Stderr, "No results, the collection was not created")
+		return nil
+	}
+	p, err := NewPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.Print(map[string]interface{}{
+		"collection":  id,
+		"items_added": added,
+	})
+}
+
+// exportItems adds items to the collection with the given ID or, if id is
+// empty, to a new collection with the given name and description. Items are
+// sent in batches of collectionBatchSize. It returns the collection's ID.
+func exportItems(client *utils.APIClient, id, name, description string, items []string) (string, error) {
+	for start := 0; start < len(items); start += collectionBatchSize {
+		end := min(start+collectionBatchSize, len(items))
+		raw := strings.Join(items[start:end], " ")
+		if id == "" {
+			collection, err := createCollection(client, name, description, raw)
+			if err != nil {
+				return "", err
+			}
+			id = collection.ID()
+		} else if _, err := addToCollection(client, id, raw); err != nil {
+			return id, fmt.Errorf("adding items to collection %s after %d items: %w", id, start, err)
+		}
+	}
+	return id, nil
+}
+
+// collectionItem returns the text that identifies obj in the raw items of a
+// collection. URLs are identified by the URL itself when available, as their
+// identifiers would be mistaken for file hashes.
+func collectionItem(obj *vt.Object) string {
+	if obj.Type() == "url" {
+		if u, err := obj.GetString("url"); err == nil {
+			return u
+		}
+	}
+	return obj.ID()
+}
+
+func rawFromReader(reader utils.StringReader) (string, error) {
 	var lines []string
 	for {
 		next, err := reader.ReadString()
 		if err == io.EOF {
 			break
+		} else if err != nil {
+			return "", err
 		}
 		lines = append(lines, next)
 	}
-	return strings.Join(lines, " ")
+	return strings.Join(lines, " "), nil
 }
 
-func descriptorsFromReader(reader utils.StringReader) map[string][]objectDescriptor {
+func descriptorsFromReader(reader utils.StringReader) (map[string][]objectDescriptor, error) {
 	descriptors := make(map[string][]objectDescriptor)
 	hashPattern := regexp.MustCompile("[0-9a-fA-F]{32,64}")
 	urlPattern := regexp.MustCompile("[hH][tTxX]{2}[pP][sS]?://.*")
@@ -308,6 +494,8 @@ func descriptorsFromReader(reader utils.StringReader) map[string][]objectDescrip
 		next, err := reader.ReadString()
 		if err == io.EOF {
 			break
+		} else if err != nil {
+			return nil, err
 		}
 		if match := hashPattern.MatchString(next); match {
 			files := descriptors["files"]
@@ -343,5 +531,5 @@ func descriptorsFromReader(reader utils.StringReader) map[string][]objectDescrip
 			descriptors["domains"] = domains
 		}
 	}
-	return descriptors
+	return descriptors, nil
 }
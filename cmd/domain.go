@@ -47,9 +47,13 @@ func NewDomainCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, utils.NormalizeDomain, nil)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"domains/%s",
-				utils.StringReaderFromCmdArgs(args),
+				r,
 				nil)
 		},
 	}
@@ -57,8 +61,11 @@ func NewDomainCmd() *cobra.Command {
 	addRelationshipCmds(cmd, "domains", "domain", "[domain]", false)
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
 
 	return cmd
 }
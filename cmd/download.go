@@ -17,9 +17,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"io"
 	"path"
-	"regexp"
 	"time"
 
 	"github.com/VirusTotal/vt-cli/utils"
@@ -28,7 +27,6 @@ import (
 	grab "github.com/cavaliergopher/grab/v3"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 type downloadCallback func(grabResp *grab.Response)
@@ -36,12 +34,21 @@ type downloadCallback func(grabResp *grab.Response)
 type fileDownloader struct {
 	grab   *grab.Client
 	client *utils.APIClient
+	// output is the directory where files are downloaded.
+	output string
 }
 
-func newFileDownloader(client *utils.APIClient) fileDownloader {
+func newFileDownloader(client *utils.APIClient, opts *utils.Options) fileDownloader {
+	g := grab.NewClient()
+	if opts.Proxy != "" {
+		if httpClient, err := opts.HTTPClient(); err == nil {
+			g.HTTPClient = httpClient
+		}
+	}
 	return fileDownloader{
-		grab:   grab.NewClient(),
-		client: client}
+		grab:   g,
+		client: client,
+		output: opts.Output}
 }
 
 func (d *fileDownloader) DownloadFile(downloadURL, dstPath string, callback downloadCallback) error {
@@ -88,18 +95,18 @@ func (d *downloader) Do(file interface{}, ds *utils.DoerState) string {
 		hash = file.(string)
 	}
 
-	ds.Progress = fmt.Sprintf("%s %4.1f%%", hash, 0.0)
+	ds.SetProgress("%s %4.1f%%", hash, 0.0)
 
 	// Get download URL
 	var downloadURL string
 	_, err := d.client.GetData(vt.URL("files/%s/download_url", hash), &downloadURL)
 
 	if err == nil {
-		dstPath := path.Join(viper.GetString("output"), hash)
+		dstPath := path.Join(d.output, hash)
 		err = d.DownloadFile(downloadURL, dstPath, func(resp *grab.Response) {
 			progress := 100 * resp.Progress()
 			if progress < 100 {
-				ds.Progress = fmt.Sprintf("%s %4.1f%% %6.1f KBi/s",
+				ds.SetProgress("%s %4.1f%% %6.1f KBi/s",
 					hash, progress, resp.BytesPerSecond()/1024)
 			}
 		})
@@ -131,7 +138,13 @@ func (z *zipDownloader) Download(hashes utils.StringReader, password string) err
 	defer spin.Stop()
 
 	hashList := make([]string, 0)
-	for hash, err := hashes.ReadString(); err == nil; hash, err = hashes.ReadString() {
+	for {
+		hash, err := hashes.ReadString()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
 		hashList = append(hashList, hash)
 	}
 
@@ -172,7 +185,7 @@ func (z *zipDownloader) Download(hashes utils.StringReader, password string) err
 	}
 
 	url := vt.URL("intelligence/zip_files/%s/download", obj.ID())
-	dstPath := viper.GetString("output")
+	dstPath := z.output
 
 	err = z.DownloadFile(url.String(), dstPath, func(resp *grab.Response) {
 		spin.Suffix = fmt.Sprintf(
@@ -208,25 +221,28 @@ func NewDownloadCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var argReader utils.StringReader
-			if len(args) == 1 && args[0] == "-" {
-				argReader = utils.NewStringIOReader(os.Stdin)
-			} else {
-				argReader = utils.NewStringArrayReader(args)
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			hashes, err := NewStringReader(cmd, args, utils.NormalizeHash, utils.ValidateHash)
+			if err != nil {
+				return err
 			}
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
-			re, _ := regexp.Compile(`^([[:xdigit:]]{64}|[[:xdigit:]]{40}|[[:xdigit:]]{32})$`)
-			hashes := utils.NewFilteredStringReader(argReader, re)
-			if viper.GetBool("zip") {
-				z := zipDownloader{newFileDownloader(client)}
-				err = z.Download(hashes, viper.GetString("zip-password"))
+			if opts.GetBool("zip") {
+				z := zipDownloader{newFileDownloader(client, opts)}
+				err = z.Download(hashes, opts.GetString("zip-password"))
 			} else {
-				c := utils.NewCoordinator(viper.GetInt("threads"))
-				c.DoWithStringsFromReader(
-					&downloader{newFileDownloader(client)},
+				var c *utils.Coordinator
+				if c, err = NewCoordinator(cmd); err != nil {
+					return err
+				}
+				err = c.DoWithStringsFromReader(
+					&downloader{newFileDownloader(client, opts)},
 					hashes)
 			}
 			return err
@@ -238,6 +254,9 @@ func NewDownloadCmd() *cobra.Command {
 
 	addThreadsFlag(cmd.Flags())
 	addOutputFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 
 	return cmd
 }
@@ -14,11 +14,13 @@
 package cmd
 
 import (
-	"regexp"
+	"context"
+	"errors"
+	"fmt"
 
+	"github.com/VirusTotal/vt-cli/pkg/vtcli"
 	"github.com/VirusTotal/vt-cli/utils"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var fileCmdHelp = `Get information about one or more files.
@@ -28,13 +30,27 @@ information about the corresponding files. The information for each file appears
 in the same order as the hashes are passed to the command.
 
 If the command receives a single hypen (-) the hashes are read from the standard
-input, one per line.
+input, one per line. Arguments that are not valid hashes are reported in the
+standard error and skipped, with --strict the command fails instead, without
+retrieving any file.
+
+With --batch files are retrieved with VirusTotal Intelligence searches that
+include many hashes each, instead of one request per file. This requires an
+API key with access to VirusTotal Intelligence, otherwise files are retrieved
+one by one as usual.
+
+With --exists-only the command only tells whether each file is known to
+VirusTotal or not, which is the cheapest way of triaging large sets of hashes.
+Combined with --identifiers-only it prints only the hashes of the known files.
+It can't be used with the ecs, cef and leef formats.
 `
 
 var fileCmdExample = `  vt file 8739c76e681f900923b900c9df0ef75cf421d39cabb54650c4b9ad19b6a76d85
   vt file 76cdb2bad9582d23c1f6f4d868218d6c
   vt file 76cdb2bad9582d23c1f6f4d868218d6c 44d88612fea8a8f36de82e1278abb02f
-  cat list_of_hashes | vt file -`
+  cat list_of_hashes | vt file -
+  cat list_of_hashes | vt file - --batch --include=_id,last_analysis_stats
+  cat list_of_hashes | vt file - --exists-only --format csv`
 
 // NewFileCmd returns a new instance of the 'file' command.
 func NewFileCmd() *cobra.Command {
@@ -46,31 +62,125 @@ func NewFileCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			re, _ := regexp.Compile("[[:xdigit:]]{64}|[[:xdigit:]]{40}|[[:xdigit:]]{32}")
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			batch := opts.GetBool("batch") || opts.GetBool("exists-only")
+			if batch && opts.GetBool("private") {
+				return errors.New("--batch and --exists-only can't be used with --private")
+			}
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
-			if viper.GetBool("private") {
+			r, err := NewStringReader(cmd, args, utils.NormalizeHash, utils.ValidateHash)
+			if err != nil {
+				return err
+			}
+			if opts.GetBool("exists-only") {
+				return printFilesExistence(cmd, p, r)
+			}
+			if batch {
+				return p.GetAndPrintFiles(r, nil)
+			}
+			if opts.GetBool("private") {
 				return p.GetAndPrintObjectsWithFallback(
 					[]string{"files/%s", "private/files/%s"},
-					utils.StringReaderFromCmdArgs(args),
-					re)
+					r,
+					nil)
 			} else {
 				return p.GetAndPrintObjects(
 					"files/%s",
-					utils.StringReaderFromCmdArgs(args),
-					re)
+					r,
+					nil)
 			}
 		},
 	}
 
+	cmd.AddCommand(NewFileDetectionsCmd())
+
 	addRelationshipCmds(cmd, "files", "file", "[hash]", true)
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
 	addPrivateFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
+	addStrictFlag(cmd.Flags())
+
+	cmd.Flags().Bool(
+		"batch", false,
+		"retrieve many files per request using VirusTotal Intelligence searches")
+	cmd.Flags().Bool(
+		"exists-only", false,
+		"only tell whether the files are known to VirusTotal")
 
 	return cmd
 }
+
+// printFilesExistence prints whether the files with the hashes read from r are
+// known to VirusTotal or not. If --identifiers-only is set only the hashes of
+// the known files are printed. With the YAML format each result is printed as
+// soon as it's received, as printing a list one item at a time produces the
+// same output as printing it at once. JSON and CSV need the whole list, so
+// results are printed at the end. The line-oriented SIEM formats describe
+// objects, so they can't be used for the results.
+func printFilesExistence(cmd *cobra.Command, p *utils.Printer, r utils.StringReader) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	format, err := vtcli.ParseFormat(opts.Format)
+	if err != nil {
+		return err
+	}
+	if format.IsStreaming() {
+		return fmt.Errorf("--exists-only can't be used with --format %s", format)
+	}
+	stream := format == vtcli.FormatYAML
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+	var batchOpts []vtcli.BatchOption
+	if opts.Unordered {
+		batchOpts = append(batchOpts, vtcli.Unordered())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hashes, readErr := utils.StringChannel(ctx, r)
+
+	var pending []interface{}
+	for res := range client.Wrapped().BatchFilesStream(ctx, hashes, true, batchOpts...) {
+		if res.Err != nil && !vtcli.IsNotFound(res.Err) {
+			return res.Err
+		}
+		switch {
+		case !opts.IdentifiersOnly:
+			pending = append(pending, map[string]interface{}{
+				"hash":  res.ID,
+				"known": res.Err == nil,
+			})
+		case res.Err == nil:
+			pending = append(pending, res.ID)
+		default:
+			continue
+		}
+		if stream {
+			if err := p.Print(pending); err != nil {
+				return err
+			}
+			pending = pending[:0]
+		}
+	}
+	if err := readErr(); err != nil {
+		return err
+	}
+	if stream {
+		return nil
+	}
+	return p.Print(pending)
+}
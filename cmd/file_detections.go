@@ -0,0 +1,199 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+func ruleHitsTable(w io.Writer, hits []*utils.RuleHit) {
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.AddRow("ENGINE", "SEVERITY", "SOURCE", "RULE", "FILES", "DESCRIPTION")
+	for _, h := range hits {
+		severity := h.Severity
+		if severity == "" {
+			severity = "-"
+		}
+		source := h.Source
+		if h.Ruleset != "" {
+			source = fmt.Sprintf("%s (%s)", h.Ruleset, h.Source)
+		}
+		table.AddRow(h.Engine, severity, source, h.Rule, len(h.Files), h.Description)
+	}
+	fmt.Fprintln(w, table)
+}
+
+var nonFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// fetchYARARulesets downloads the source of the YARA rulesets referenced by
+// the hits and saves them in dir, one file per ruleset. Rulesets that can't be
+// retrieved are reported to stderr. It returns the number of rulesets saved.
+func fetchYARARulesets(client *utils.APIClient, hits []*utils.RuleHit, dir string, stderr io.Writer) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	fetched := make(map[string]bool)
+	for _, h := range hits {
+		if h.Engine != "yara" || h.RulesetID == "" || fetched[h.RulesetID] {
+			continue
+		}
+		fetched[h.RulesetID] = true
+		ruleset, err := client.GetObject(vt.URL("yara_rulesets/%s", h.RulesetID))
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", h.RulesetID, err)
+			continue
+		}
+		rules, err := ruleset.GetString("rules")
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: ruleset without rules\n", h.RulesetID)
+			continue
+		}
+		name, _ := ruleset.GetString("name")
+		source, _ := ruleset.GetString("source")
+		header := fmt.Sprintf("// Ruleset: %s\n// Source: %s\n// VirusTotal ID: %s\n\n", name, source, h.RulesetID)
+		filename := h.RulesetID + ".yar"
+		if name != "" {
+			filename = nonFilenameChars.ReplaceAllString(name, "_") + "_" + filename
+		}
+		if err := os.WriteFile(filepath.Join(dir, filename), []byte(header+rules), 0644); err != nil {
+			return 0, err
+		}
+	}
+	return len(fetched), nil
+}
+
+var fileDetectionsCmdHelp = `Summarize the crowdsourced YARA, Sigma and IDS rules matching files.
+
+This command receives one or more hashes (SHA-256, SHA-1 or MD5) and lists the
+crowdsourced YARA rules, Sigma rules and IDS rules that matched the files. Hits
+of the same rule in different files are aggregated, and for each rule the list
+of matching files is shown. Rules are sorted by severity.
+
+With --fetch-rulesets the source of the YARA rulesets containing the matching
+rules is saved to the given directory, one .yar file per ruleset.
+
+If the command receives a single hypen (-) the hashes are read from the standard
+input, one per line.`
+
+var fileDetectionsCmdExample = `  vt file detections 8739c76e681f900923b900c9df0ef75cf421d39cabb54650c4b9ad19b6a76d85
+  cat list_of_hashes | vt file detections - --human
+  cat list_of_hashes | vt file detections - --engine sigma,ids --format csv
+  cat list_of_hashes | vt file detections - --fetch-rulesets ./rules`
+
+// NewFileDetectionsCmd returns a new instance of the 'file detections' command.
+func NewFileDetectionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "detections [hash]...",
+		Short:   "Summarize the YARA, Sigma and IDS rules matching files",
+		Long:    fileDetectionsCmdHelp,
+		Example: fileDetectionsCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			engines := opts.GetStringSlice("engine")
+			if err := utils.ValidateDetectionEngines(engines); err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			p, err := NewPrinter(cmd)
+			if err != nil {
+				return err
+			}
+
+			r, err := NewStringReader(cmd, args, utils.NormalizeHash, utils.ValidateHash)
+			if err != nil {
+				return err
+			}
+			hashes := make([]string, 0)
+			for {
+				s, err := r.ReadString()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					return err
+				}
+				hashes = append(hashes, s)
+			}
+
+			objectsCh := make(chan *vt.Object)
+			errorsCh := make(chan error, len(hashes))
+			retrieveErr := make(chan error, 1)
+			go func() {
+				retrieveErr <- client.RetrieveObjects("files/%s", hashes, objectsCh, errorsCh)
+			}()
+
+			hits := make([]*utils.RuleHit, 0)
+			for obj := range objectsCh {
+				hits = append(hits, utils.FileRuleHits(obj, engines)...)
+			}
+			for err := range errorsCh {
+				fmt.Fprintln(opts.Stderr, err)
+			}
+			if err := <-retrieveErr; err != nil {
+				return err
+			}
+			hits = utils.AggregateRuleHits(hits)
+
+			if dir := opts.GetString("fetch-rulesets"); dir != "" {
+				n, err := fetchYARARulesets(client, hits, dir, opts.Stderr)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(opts.Stderr, "%d YARA rulesets saved to %s\n", n, dir)
+			}
+
+			if opts.GetBool("human") {
+				ruleHitsTable(opts.Stdout, hits)
+				return nil
+			}
+			result := make([]map[string]interface{}, len(hits))
+			for i, h := range hits {
+				result[i] = h.ToMap()
+			}
+			return p.Print(result)
+		},
+	}
+
+	cmd.Flags().StringSlice(
+		"engine", []string{"yara", "sigma", "ids"},
+		"engines whose results are shown (yara, sigma, ids)")
+	cmd.Flags().String(
+		"fetch-rulesets", "",
+		"directory where the source of the matching YARA rulesets is saved")
+
+	addThreadsFlag(cmd.Flags())
+	addHumanFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
+	addStrictFlag(cmd.Flags())
+
+	return cmd
+}
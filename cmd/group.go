@@ -14,7 +14,6 @@
 package cmd
 
 import (
-	"github.com/VirusTotal/vt-cli/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -36,9 +35,13 @@ func NewGroupCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, nil)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"groups/%s",
-				utils.StringReaderFromCmdArgs(args),
+				r,
 				nil)
 		},
 	}
@@ -46,6 +49,8 @@ func NewGroupCmd() *cobra.Command {
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
 	addThreadsFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 
 	cmd.AddCommand(NewPrivilegeCmd("group"))
 
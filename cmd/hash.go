@@ -0,0 +1,207 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/VirusTotal/vt-cli/hashes"
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/spf13/cobra"
+)
+
+// hashPaths returns the paths of the files in args, which can contain
+// directories, or in the standard input if args is a single hyphen.
+func hashPaths(cmd *cobra.Command, args []string) ([]string, error) {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewStringReader(cmd, args, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for {
+		path, err := r.ReadString()
+		if err == io.EOF {
+			return paths, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if !utils.IsDir(path) {
+			paths = append(paths, path)
+			continue
+		}
+		dr, err := utils.NewFileDirReader(path, opts.GetBool("recursive"), opts.GetInt("maxDepth"))
+		if err != nil {
+			return nil, err
+		}
+		for {
+			p, err := dr.ReadString()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			paths = append(paths, p)
+		}
+	}
+}
+
+// hashFile returns the fingerprints of the file at path, and the file's
+// VirusTotal report if client is not nil. Hashes that are not defined for the
+// file are omitted. Files unknown to VirusTotal have a null report.
+func hashFile(client *utils.APIClient, path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := hashes.Compute(data)
+	m := map[string]interface{}{
+		"path":   path,
+		"size":   len(data),
+		"md5":    f.MD5,
+		"sha1":   f.SHA1,
+		"sha256": f.SHA256,
+		"ssdeep": f.SSDeep,
+	}
+	for k, v := range map[string]string{
+		"tlsh":         f.TLSH,
+		"imphash":      f.Imphash,
+		"authentihash": f.Authentihash,
+	} {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	if client == nil {
+		return m, nil
+	}
+	obj, err := client.GetObject(vt.URL("files/%s", f.SHA256))
+	if apiErr, ok := err.(vt.Error); ok && apiErr.Code == "NotFoundError" {
+		m["vt"] = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", f.SHA256, err)
+	} else {
+		m["vt"] = utils.ObjectToMap(obj)
+	}
+	return m, nil
+}
+
+func runHashCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	paths, err := hashPaths(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	var client *utils.APIClient
+	if opts.GetBool("lookup") {
+		if client, err = NewAPIClient(cmd); err != nil {
+			return err
+		}
+	}
+
+	// Files are hashed by opts.Threads goroutines, and printed in the order
+	// in which they were given.
+	results := make([]map[string]interface{}, len(paths))
+	errs := make([]error, len(paths))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < max(opts.Threads, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = hashFile(client, paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var hashed []map[string]interface{}
+	for i, m := range results {
+		if errs[i] != nil {
+			fmt.Fprintf(opts.Stderr, "%s: %v\n", paths[i], errs[i])
+			continue
+		}
+		if opts.Include != nil || opts.Exclude != nil {
+			m = utils.FilterMap(m, opts.Include, opts.Exclude)
+		}
+		hashed = append(hashed, m)
+	}
+
+	// Without --lookup the API isn't used, and no API key is needed.
+	p, err := utils.NewPrinter(client, cmd, &colorScheme)
+	if err != nil {
+		return err
+	}
+	return p.Print(hashed)
+}
+
+var hashCmdHelp = `Compute the hashes of local files.
+
+This command computes locally the same hashes that VirusTotal computes for
+files: MD5, SHA-1, SHA-256, ssdeep, TLSH and, for PE files, the import hash and
+the Authenticode hash. The TLSH hash is omitted for files that are too short or
+too uniform. The vhash can't be computed locally.
+
+The command receives file paths or directories, whose files are hashed too. If
+it receives a single hyphen (-) the paths are read from the standard input, one
+per line.
+
+With --lookup the VirusTotal report of each file is added to its hashes, under
+the "vt" key, which is null for files unknown to VirusTotal. This requires an
+API key, otherwise no request is made to VirusTotal.`
+
+var hashCmdExample = `  vt hash sample.exe
+  vt hash samples/ --recursive --maxDepth 3 --format csv
+  vt hash sample.exe --lookup --include path,sha256,vt.last_analysis_stats.**
+  find . -name "*.dll" | vt hash -`
+
+// NewHashCmd returns a new instance of the 'hash' command.
+func NewHashCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "hash [file|dir]...",
+		Short:   "Compute the hashes of local files",
+		Long:    hashCmdHelp,
+		Example: hashCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runHashCmd,
+	}
+
+	cmd.Flags().Bool(
+		"lookup", false,
+		"add the VirusTotal report of each file")
+
+	addRecursive(cmd.Flags())
+	addMaxDepth(cmd.Flags())
+	addThreadsFlag(cmd.Flags())
+	addIncludeExcludeFlags(cmd.Flags())
+	addInputFlags(cmd.Flags())
+
+	return cmd
+}
@@ -26,7 +26,6 @@ import (
 
 	vt "github.com/VirusTotal/vt-go"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var notificationsDeleteCmdHelp = `Delete hunting notifications.
@@ -42,12 +41,16 @@ func NewHuntingNotificationDeleteCmd() *cobra.Command {
 		Short: "Delete hunting notifications",
 		Long:  notificationsDeleteCmdHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			deleteAll := viper.GetBool("all")
-			deleteTag := viper.GetString("with-tag")
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			deleteAll := opts.All
+			deleteTag := opts.GetString("with-tag")
 			if len(args) == 0 && !deleteAll && deleteTag == "" {
 				return errors.New("Specify notification id or use --all or --with-tag")
 			}
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -112,6 +115,9 @@ func NewHuntingNotificationListCmd() *cobra.Command {
 	return cmd
 }
 
+var validateNumericID = utils.ValidateRegexp(
+	regexp.MustCompile(`^\d+$`), "malformed ID, expecting a number")
+
 // NewHuntingNotificationCmd returns a new instance of the 'notifications' command.
 func NewHuntingNotificationCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -121,20 +127,26 @@ func NewHuntingNotificationCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			re, _ := regexp.Compile("\\d+")
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, validateNumericID)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"intelligence/hunting_notifications/%s",
-				utils.StringReaderFromCmdArgs(args),
-				re)
+				r,
+				nil)
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 
 	cmd.AddCommand(NewHuntingNotificationListCmd())
@@ -174,8 +186,8 @@ func NewHuntingRulesetListCmd() *cobra.Command {
 	return cmd
 }
 
-func patchRuleset(id, attr string, value interface{}) error {
-	client, err := NewAPIClient()
+func patchRuleset(cmd *cobra.Command, id, attr string, value interface{}) error {
+	client, err := NewAPIClient(cmd)
 	if err != nil {
 		return err
 	}
@@ -192,7 +204,7 @@ func NewHuntingRulesetDisableCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return patchRuleset(args[0], "enabled", false)
+			return patchRuleset(cmd, args[0], "enabled", false)
 		},
 	}
 }
@@ -205,7 +217,7 @@ func NewHuntingRulesetEnableCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return patchRuleset(args[0], "enabled", true)
+			return patchRuleset(cmd, args[0], "enabled", true)
 		},
 	}
 }
@@ -218,7 +230,7 @@ func NewHuntingRulesetRenameCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(2),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return patchRuleset(args[0], "name", args[1])
+			return patchRuleset(cmd, args[0], "name", args[1])
 		},
 	}
 }
@@ -235,7 +247,7 @@ func NewHuntingRulesetSetLimitCmd() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("invalid limit: %s", args[1])
 			}
-			return patchRuleset(args[0], "limit", limit)
+			return patchRuleset(cmd, args[0], "limit", limit)
 		},
 	}
 }
@@ -252,7 +264,7 @@ func NewHuntingRulesetUpdateCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return patchRuleset(args[0], "rules", string(rules))
+			return patchRuleset(cmd, args[0], "rules", string(rules))
 		},
 	}
 }
@@ -265,11 +277,15 @@ func NewHuntingRulesetDeleteCmd() *cobra.Command {
 		Short:   "Delete rulesets",
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			opts, err := utils.CommandOptions(cmd)
 			if err != nil {
 				return err
 			}
-			deleteAll := viper.GetBool("all")
+			client, err := NewAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			deleteAll := opts.All
 			if len(args) == 0 && !deleteAll {
 				return errors.New("Specify ruleset id or use --all")
 			}
@@ -312,7 +328,7 @@ func NewHuntingRulesetAddCmd() *cobra.Command {
 		Args:  cobra.ExactArgs(2),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -351,7 +367,7 @@ func NewHuntingRulesetSetNotificationEmailsCmd() *cobra.Command {
 		Args:  cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return patchRuleset(args[0], "notification_emails", args[1:])
+			return patchRuleset(cmd, args[0], "notification_emails", args[1:])
 		},
 	}
 	return cmd
@@ -367,20 +383,26 @@ func NewHuntingRulesetCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			re, _ := regexp.Compile("\\d+")
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, validateNumericID)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"intelligence/hunting_rulesets/%s?relationships=owner,editors",
-				utils.StringReaderFromCmdArgs(args),
-				re)
+				r,
+				nil)
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 
 	cmd.AddCommand(NewHuntingRulesetAddCmd())
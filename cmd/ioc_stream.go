@@ -15,10 +15,8 @@ package cmd
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
-	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/VirusTotal/vt-go"
@@ -41,6 +39,8 @@ vt iocstream list -I –limit 9999999 > results.csv
 vt iocstream list -i "_id,last_analysis_stats,size,type_tag"
 # Check if a hash is in your IoC Stream matches
 vt iocstream list -f "entity_type:file entity_id:hash"
+# Stream ALL the IoC Stream notifications as Elastic Common Schema documents
+vt iocstream list --all --format ecs | filebeat -e
 
 ## Delete:
 # Delete all notifications matching a filter, e.g. all matches for a YARA rule/ruleset. This process is
@@ -60,7 +60,11 @@ vt iocstream list -I –limit 9999999 > results.csv
 # List the first IoC Stream notifications including the hash, last_analysis_stats, size and file type
 vt iocstream list -i "_id,last_analysis_stats,size,type_tag"
 # Check if a hash is in your IoC Stream matches
-vt iocstream list -f "entity_type:file entity_id:hash"`
+vt iocstream list -f "entity_type:file entity_id:hash"
+# Stream ALL the IoC Stream notifications as Elastic Common Schema documents
+vt iocstream list --all --format ecs | filebeat -e
+# Put the matches of a hunting rule in a new collection
+vt iocstream list -f "origin:hunting tag:my_rule" --to-collection "my_rule matches"`
 
 var iocStreamDeleteCmdExamples = `# Delete all notifications matching a filter, e.g. all matches for a YARA rule/ruleset
 vt iocstream delete -f "origin:hunting tag:my_rule"
@@ -77,20 +81,26 @@ func NewIOCStreamCmd() *cobra.Command {
 		Args:    cobra.ExactArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			re, _ := regexp.Compile("\\d+")
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, validateNumericID)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"ioc_stream_notifications/%s",
-				utils.StringReaderFromCmdArgs(args),
-				re)
+				r,
+				nil)
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 
 	cmd.AddCommand(NewIOCStreamListCmd())
@@ -108,6 +118,30 @@ func NewIOCStreamListCmd() *cobra.Command {
 		Example: iocStreamListCmdExamples,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkExportFlags(cmd); err != nil {
+				return err
+			}
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			if exportRequested(opts) {
+				client, err := NewAPIClient(cmd)
+				if err != nil {
+					return err
+				}
+				// Full objects are retrieved, instead of descriptors only,
+				// because URLs must be added to the collection by their URL.
+				it, err := client.Iterator(vt.URL("ioc_stream"), opts.IteratorOptions()...)
+				if err != nil {
+					return err
+				}
+				description := "IoCs from the IoC Stream"
+				if opts.Filter != "" {
+					description = fmt.Sprintf("IoCs from the IoC Stream matching: %s", opts.Filter)
+				}
+				return exportToCollection(cmd, it, description)
+			}
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
@@ -120,7 +154,9 @@ func NewIOCStreamListCmd() *cobra.Command {
 	addIDOnlyFlag(cmd.Flags())
 	addFilterFlag(cmd.Flags())
 	addLimitFlag(cmd.Flags())
+	addAllFlag(cmd.Flags())
 	addCursorFlag(cmd.Flags())
+	addExportFlags(cmd.Flags())
 
 	return cmd
 }
@@ -140,7 +176,11 @@ func NewIOCStreamDeleteCmd() *cobra.Command {
 		Example: iocStreamDeleteCmdExamples,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -156,7 +196,7 @@ func NewIOCStreamDeleteCmd() *cobra.Command {
 				}
 				return eg.Wait()
 			} else {
-				filterFlag := viper.GetString("filter")
+				filterFlag := opts.Filter
 				targetUrl := vt.URL("ioc_stream")
 				if strings.TrimSpace(filterFlag) == "" {
 					fmt.Println("This will delete all your IoC Stream notifications.")
@@ -14,10 +14,7 @@
 package cmd
 
 import (
-	"regexp"
-
 	"github.com/VirusTotal/vt-cli/utils"
-
 	"github.com/spf13/cobra"
 )
 
@@ -44,23 +41,29 @@ func NewIPCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			re, _ := regexp.Compile(`\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}`)
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, utils.ValidateIP)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"ip_addresses/%s",
-				utils.StringReaderFromCmdArgs(args),
-				re)
+				r,
+				nil)
 		},
 	}
 
 	addRelationshipCmds(cmd, "ip_addresses", "ip_address", "[ip]", false)
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 
 	return cmd
 }
@@ -35,7 +35,7 @@ func NewMetaCmd() *cobra.Command {
 		Long:  metaCmdHelp,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
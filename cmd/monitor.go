@@ -30,11 +30,13 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var base64RegExp = `^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$`
 
+var validateMonitorItemID = utils.ValidateRegexp(
+	regexp.MustCompile(base64RegExp), "malformed monitor item ID, expecting base64")
+
 var monitorItemsCmdExample = `  vt monitor list
   vt monitor list --filter "path:/myfolder/" --include path
   vt monitor list --filter "tag:detected" --include path,last_analysis_results.*.result,last_detections_count`
@@ -78,7 +80,7 @@ func (d *monitorDownloader) Do(file interface{}, ds *utils.DoerState) string {
 	}
 
 	// Resolve MonitorItemID to path
-	ds.Progress = fmt.Sprintf("%s [resolving path]", monitorItemID)
+	ds.SetProgress("%s [resolving path]", monitorItemID)
 	var obj *vt.Object
 	obj, err := d.client.GetObject(vt.URL("monitor/items/%s", monitorItemID))
 	if err != nil {
@@ -93,18 +95,18 @@ func (d *monitorDownloader) Do(file interface{}, ds *utils.DoerState) string {
 	monitorPath = strings.TrimPrefix(monitorPath, "/")
 
 	// From now progress shows the path instead of monitorItemID
-	ds.Progress = fmt.Sprintf("%s %4.1f%%", monitorPath, 0.0)
+	ds.SetProgress("%s %4.1f%%", monitorPath, 0.0)
 
 	// Get download URL
 	var downloadURL string
 	_, err = d.client.GetData(vt.URL("monitor/items/%s/download_url", monitorItemID), &downloadURL)
 
 	if err == nil {
-		dstPath := path.Join(viper.GetString("output"), monitorPath)
+		dstPath := path.Join(d.output, monitorPath)
 		err = d.DownloadFile(downloadURL, dstPath, func(resp *grab.Response) {
 			progress := 100 * resp.Progress()
 			if progress < 100 {
-				ds.Progress = fmt.Sprintf("%s %4.1f%% %6.1f KBi/s",
+				ds.SetProgress("%s %4.1f%% %6.1f KBi/s",
 					monitorPath, progress, resp.BytesPerSecond()/1024)
 			}
 		})
@@ -139,31 +141,36 @@ func NewMonitorItemsDownloadCmd() *cobra.Command {
 		Long:    monitorItemsDownloadCmdHelp,
 		Example: monitorItemsDownloadCmdExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var argReader utils.StringReader
 			if len(args) == 0 {
 				return errors.New("No item provided")
-			} else if len(args) == 1 && args[0] == "-" {
-				argReader = utils.NewStringIOReader(os.Stdin)
-			} else {
-				argReader = utils.NewStringArrayReader(args)
 			}
-			client, err := NewAPIClient()
+			monitorItemIDs, err := NewStringReader(cmd, args, nil, validateMonitorItemID)
+			if err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
-			re, _ := regexp.Compile(base64RegExp)
-			monitorItemIDs := utils.NewFilteredStringReader(argReader, re)
 
-			c := utils.NewCoordinator(viper.GetInt("threads"))
-			c.DoWithStringsFromReader(
-				&monitorDownloader{fileDownloader: newFileDownloader(client)},
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			c, err := NewCoordinator(cmd)
+			if err != nil {
+				return err
+			}
+			return c.DoWithStringsFromReader(
+				&monitorDownloader{fileDownloader: newFileDownloader(client, opts)},
 				monitorItemIDs)
-			return err
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
 	addOutputFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 	return cmd
 }
 
@@ -197,7 +204,7 @@ func NewMonitorItemsSetDetailsCmd() *cobra.Command {
 			}
 			monitorItemID = args[0]
 
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -232,7 +239,7 @@ func NewMonitorItemsDeleteDetailsCmd() *cobra.Command {
 				return errors.New("No item provided")
 			}
 
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -275,7 +282,7 @@ func NewMonitorItemsDeleteCmd() *cobra.Command {
 				return errors.New("No item provided")
 			}
 
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -312,15 +319,22 @@ type uploadParams struct {
 func (s *monitorFileUpload) Do(file interface{}, ds *utils.DoerState) string {
 	params := file.(uploadParams)
 
+	// Wait for the goroutine reporting the progress before returning, so it
+	// doesn't overwrite the progress of the next item handled by this doer.
 	progressCh := make(chan float32)
-	defer close(progressCh)
+	progressDone := make(chan struct{})
+	defer func() {
+		close(progressCh)
+		<-progressDone
+	}()
 
 	go func() {
+		defer close(progressDone)
 		for progress := range progressCh {
 			if progress < 100 {
-				ds.Progress = fmt.Sprintf("%s uploading... %4.1f%%", params.filePath, progress)
+				ds.SetProgress("%s uploading... %4.1f%%", params.filePath, progress)
 			} else {
-				ds.Progress = fmt.Sprintf("%s done.", params.filePath)
+				ds.SetProgress("%s done.", params.filePath)
 			}
 		}
 	}()
@@ -400,13 +414,16 @@ func runMonitorItemUpload(cmd *cobra.Command, args []string) error {
 		return errors.New("Not a regular file or folder")
 	}
 
-	client, err := NewAPIClient()
+	client, err := NewAPIClient(cmd)
 	if err != nil {
 		return err
 	}
 
 	s := &monitorFileUpload{uploader: client.NewMonitorUploader()}
-	c := utils.NewCoordinator(viper.GetInt("threads"))
+	c, err := NewCoordinator(cmd)
+	if err != nil {
+		return err
+	}
 	c.DoWithItemsFromChannel(s, ch)
 	return nil
 }
@@ -453,19 +470,25 @@ func NewMonitorCmd() *cobra.Command {
 		Args:  cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			re, _ := regexp.Compile(base64RegExp)
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, validateMonitorItemID)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects("monitor/items/%s",
-				utils.StringReaderFromCmdArgs(args),
-				re)
+				r,
+				nil)
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 
 	cmd.AddCommand(NewMonitorItemsListCmd())
 	cmd.AddCommand(NewMonitorItemsUploadCmd())
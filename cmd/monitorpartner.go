@@ -16,9 +16,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
 	"path"
-	"regexp"
 
 	"github.com/VirusTotal/vt-cli/utils"
 	vt "github.com/VirusTotal/vt-go"
@@ -26,7 +24,6 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var monitorPartnerItemsCmdExample = `  vt monitorpartner list
@@ -70,18 +67,18 @@ func (d *monitorPartnerDownloader) Do(file interface{}, ds *utils.DoerState) str
 		hash = file.(string)
 	}
 
-	ds.Progress = fmt.Sprintf("%s %4.1f%%", hash, 0.0)
+	ds.SetProgress("%s %4.1f%%", hash, 0.0)
 
 	// Get download URL
 	var downloadURL string
 	_, err := d.client.GetData(vt.URL("monitor_partner/files/%s/download_url", hash), &downloadURL)
 
 	if err == nil {
-		dstPath := path.Join(viper.GetString("output"), hash)
+		dstPath := path.Join(d.output, hash)
 		err = d.DownloadFile(downloadURL, dstPath, func(resp *grab.Response) {
 			progress := 100 * resp.Progress()
 			if progress < 100 {
-				ds.Progress = fmt.Sprintf("%s %4.1f%% %6.1f KBi/s",
+				ds.SetProgress("%s %4.1f%% %6.1f KBi/s",
 					hash, progress, resp.BytesPerSecond()/1024)
 			}
 		})
@@ -115,31 +112,37 @@ func NewMonitorPartnerHashDownloadCmd() *cobra.Command {
 		Long:    monitorPartnerHashDownloadCmdHelp,
 		Example: monitorPartnerHashDownloadCmdExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var argReader utils.StringReader
 			if len(args) == 0 {
 				return errors.New("No hash provided")
-			} else if len(args) == 1 && args[0] == "-" {
-				argReader = utils.NewStringIOReader(os.Stdin)
-			} else {
-				argReader = utils.NewStringArrayReader(args)
 			}
-			client, err := NewAPIClient()
+			monitorHashes, err := NewStringReader(cmd, args, utils.NormalizeHash, utils.ValidateSHA256)
+			if err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
-			re, _ := regexp.Compile("[[:xdigit:]]{64}")
-			monitorHashes := utils.NewFilteredStringReader(argReader, re)
 
-			c := utils.NewCoordinator(viper.GetInt("threads"))
-			c.DoWithStringsFromReader(
-				&monitorPartnerDownloader{fileDownloader: newFileDownloader(client)},
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			c, err := NewCoordinator(cmd)
+			if err != nil {
+				return err
+			}
+			return c.DoWithStringsFromReader(
+				&monitorPartnerDownloader{fileDownloader: newFileDownloader(client, opts)},
 				monitorHashes)
-			return err
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
 	addOutputFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
+	addStrictFlag(cmd.Flags())
 	return cmd
 }
 
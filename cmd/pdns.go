@@ -0,0 +1,222 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/spf13/cobra"
+)
+
+// pdnsCollector retrieves the resolutions of domains and IP addresses.
+type pdnsCollector struct {
+	client   *utils.APIClient
+	opts     *utils.Options
+	set      *utils.PDNSSet
+	from, to time.Time
+	// visited are the domains and IP addresses whose resolutions were
+	// already retrieved.
+	visited map[string]bool
+}
+
+// inRange returns true if the date is between the --from and --to dates.
+func (c *pdnsCollector) inRange(date time.Time) bool {
+	return (c.from.IsZero() || !date.Before(c.from)) && (c.to.IsZero() || !date.After(c.to))
+}
+
+// collect retrieves the resolutions of a domain or IP address, and returns
+// the IP addresses the domain resolved to, or the domains that resolved to
+// the IP address, within the --from and --to dates.
+func (c *pdnsCollector) collect(ioc string) ([]string, error) {
+	c.visited[ioc] = true
+	collection, other := "domains", "ip_address"
+	if net.ParseIP(ioc) != nil {
+		collection, other = "ip_addresses", "host_name"
+	}
+	it, err := c.client.Iterator(
+		vt.URL("%s/%s/resolutions", collection, ioc),
+		vt.IteratorLimit(c.opts.Limit),
+		vt.IteratorBatchSize(40))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	var related []string
+	for it.Next() {
+		obj := it.Get()
+		if !c.set.Add(obj) {
+			continue
+		}
+		if date, _ := obj.GetTime("date"); c.inRange(date) {
+			if s, _ := obj.GetString(other); s != "" {
+				related = append(related, s)
+			}
+		}
+	}
+	return related, it.Error()
+}
+
+func runPDNSCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	c := &pdnsCollector{
+		opts:    opts,
+		set:     utils.NewPDNSSet(),
+		visited: make(map[string]bool),
+	}
+	if c.from, c.to, err = dateRange(opts); err != nil {
+		return err
+	}
+
+	if c.client, err = NewAPIClient(cmd); err != nil {
+		return err
+	}
+	r, err := NewStringReader(cmd, args, nil, nil)
+	if err != nil {
+		return err
+	}
+	var pivots []string
+	for {
+		ioc, err := r.ReadString()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if c.visited[ioc] {
+			continue
+		}
+		related, err := c.collect(ioc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ioc, err)
+		}
+		pivots = append(pivots, related...)
+	}
+
+	if opts.GetBool("recurse") {
+		n := 0
+		for _, ioc := range pivots {
+			if c.visited[ioc] {
+				continue
+			}
+			if n == opts.GetInt("max-pivots") {
+				fmt.Fprintf(opts.Stderr, "reached --max-pivots, %d domains and IP addresses not visited\n",
+					countUnvisited(pivots, c.visited))
+				break
+			}
+			if _, err := c.collect(ioc); err != nil {
+				return fmt.Errorf("%s: %w", ioc, err)
+			}
+			n++
+		}
+	}
+
+	if !opts.Silent {
+		fmt.Fprintf(opts.Stderr, "%d resolutions of %d domains and IP addresses\n",
+			c.set.Len(), len(c.visited))
+	}
+
+	if opts.GetBool("cof") {
+		enc := json.NewEncoder(opts.Stdout)
+		for _, rec := range c.set.COFRecords(c.from, c.to) {
+			if err := enc.Encode(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	p, err := NewPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.Print(c.set.Records(c.from, c.to))
+}
+
+// countUnvisited returns the number of distinct items not in visited.
+func countUnvisited(items []string, visited map[string]bool) int {
+	unvisited := make(map[string]bool)
+	for _, s := range items {
+		if !visited[s] {
+			unvisited[s] = true
+		}
+	}
+	return len(unvisited)
+}
+
+var pdnsCmdHelp = `Export the passive DNS resolutions of domains and IP addresses.
+
+This command retrieves all the resolutions of the given domains and IP
+addresses, and prints them as passive DNS records with the fields rrname (the
+domain), rrtype (A or AAAA), rdata (the IP address), first_seen and last_seen.
+Records are deduplicated across inputs. VirusTotal keeps a single date for
+each pair of domain and IP address, so first_seen and last_seen differ only
+if the pair was found with different dates.
+
+With --cof the records are printed in the Passive DNS Common Output Format, one
+JSON object per line, with dates as Unix timestamps.
+
+With --recurse the command also retrieves the resolutions of the IP addresses
+the domains resolved to, and of the domains that resolved to the IP addresses,
+which finds co-hosted domains. Only one hop is followed, and at most
+--max-pivots additional domains and IP addresses are visited.
+
+--from and --to limit the output to the records seen between two dates, which
+can be given as YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS, or relative to the current
+time like 12h, 7d or 2w. A --to date without time includes the whole day.
+
+If the command receives a single hyphen (-) the domains and IP addresses are
+read from the standard input, one per line.`
+
+var pdnsCmdExample = `  vt pdns example.com
+  vt pdns example.com 1.2.3.4 --from 2024-01-01 --format csv
+  vt pdns example.com --recurse --max-pivots 50 --cof > pdns.json
+  cat domains | vt pdns - --from 90d --cof`
+
+// NewPDNSCmd returns a new instance of the 'pdns' command.
+func NewPDNSCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pdns [domain|ip]...",
+		Short:   "Export the passive DNS resolutions of domains and IP addresses",
+		Long:    pdnsCmdHelp,
+		Example: pdnsCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runPDNSCmd,
+	}
+
+	cmd.Flags().Bool(
+		"cof", false,
+		"print records in the Passive DNS Common Output Format")
+	cmd.Flags().Bool(
+		"recurse", false,
+		"retrieve also the resolutions of the related domains and IP addresses")
+	cmd.Flags().Int(
+		"max-pivots", 20,
+		"maximum number of related domains and IP addresses visited with --recurse")
+	cmd.Flags().IntP(
+		"limit", "n", 0,
+		"maximum number of resolutions retrieved for each domain or IP address, 0 for all")
+
+	addDateRangeFlags(cmd.Flags())
+	addInputFlags(cmd.Flags())
+
+	return cmd
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"github.com/VirusTotal/vt-go"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	"github.com/VirusTotal/vt-cli/utils"
 	"strconv"
 	"time"
 )
@@ -36,9 +36,12 @@ func NewPrivilegeGrantCmd(target string) *cobra.Command {
 		Example: fmt.Sprintf("  vt %s privileges grant my%s intelligence downloads-tier-2", target, target),
 		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
 			var expirationDate int64
-			if expiration:= viper.GetString("expiration"); expiration != "" {
-				var err error
+			if expiration:= opts.GetString("expiration"); expiration != "" {
 				expirationDate, err = strconv.ParseInt(expiration, 10, 64)
 				if err != nil {
 					if t, err := time.Parse("2006-01-02", expiration); err == nil {
@@ -58,7 +61,7 @@ func NewPrivilegeGrantCmd(target string) *cobra.Command {
 				}
 				privileges[arg] = p
 			}
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -85,7 +88,7 @@ func NewPrivilegeRevokeCmd(target string) *cobra.Command {
 			for _, arg := range args[1:] {
 				privileges[arg] = Privilege{Granted: false}
 			}
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -25,7 +25,6 @@ import (
 
 	vt "github.com/VirusTotal/vt-go"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var objectRelationshipsMap map[string][]vt.RelationshipMeta
@@ -49,16 +48,12 @@ func init() {
 	}
 }
 
-func getRelatedObjects(collection, objectID, relationship string, limit int) ([]map[string]interface{}, error) {
+func getRelatedObjects(client *utils.APIClient, collection, objectID, relationship string, limit int) ([]map[string]interface{}, error) {
 	if collection == "urls" {
 		// If collections is "urls" the objectID is the URL itself and
 		// it needs to be encoded in base64.
 		objectID = base64.RawURLEncoding.EncodeToString([]byte(objectID))
 	}
-	client, err := NewAPIClient()
-	if err != nil {
-		return nil, err
-	}
 	it, err := client.Iterator(
 		vt.URL("%s/%s/%s", collection, objectID, relationship),
 		vt.IteratorLimit(limit))
@@ -94,7 +89,12 @@ func NewRelationshipCmd(collection, relationship, use, description string, priva
 			if err != nil {
 				return err
 			}
-			if viper.GetBool("private") {
+			collection := collection
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			if opts.GetBool("private") {
 				collection = "private/" + collection
 			}
 			url := vt.URL("%s/%s/%s", collection, objectID, relationship)
@@ -123,7 +123,16 @@ func NewRelationshipsCmd(collection, objectType, use string, private_flag bool)
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var wg sync.WaitGroup
 			var sm sync.Map
-			if viper.GetBool("private") {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			collection, objectType := collection, objectType
+			if opts.GetBool("private") {
 				objectType = "private_" + objectType
 				collection = "private/" + collection
 			}
@@ -131,10 +140,11 @@ func NewRelationshipsCmd(collection, objectType, use string, private_flag bool)
 				wg.Add(1)
 				go func(relationshipName string) {
 					objs, err := getRelatedObjects(
+						client,
 						collection,
 						args[0],
 						relationshipName,
-						viper.GetInt("limit"))
+						opts.Limit)
 					if err != nil {
 						fmt.Println(err)
 					} else if len(objs) > 0 {
@@ -151,10 +161,8 @@ func NewRelationshipsCmd(collection, objectType, use string, private_flag bool)
 				return true
 			})
 
-			if viper.IsSet("include") || viper.IsSet("exclude") {
-				m = utils.FilterMap(m,
-					viper.GetStringSlice("include"),
-					viper.GetStringSlice("exclude"))
+			if opts.Include != nil || opts.Exclude != nil {
+				m = utils.FilterMap(m, opts.Include, opts.Exclude)
 			}
 
 			p, err := NewPrinter(cmd)
@@ -28,22 +28,25 @@ import (
 	vt "github.com/VirusTotal/vt-go"
 	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var rulesPattern = regexp.MustCompile(`rule\s+(\w+)\s*(:(\s*\w+\s*)+)?{`)
 
 func retrohuntListTable(cmd *cobra.Command) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
 
-	client, err := NewAPIClient()
+	client, err := NewAPIClient(cmd)
 	if err != nil {
 		return err
 	}
 
 	it, err := client.Iterator(
 		vt.URL("intelligence/retrohunt_jobs"),
-		vt.IteratorLimit(viper.GetInt("limit")),
-		vt.IteratorFilter(viper.GetString("filter")))
+		vt.IteratorLimit(opts.Limit),
+		vt.IteratorFilter(opts.Filter))
 
 	if err != nil {
 		return err
@@ -119,7 +122,11 @@ func NewRetrohuntListCmd() *cobra.Command {
 		Long:    `List retrohunt jobs.`,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if viper.GetBool("human") {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			if opts.GetBool("human") {
 				for _, flag := range []string{"cursor", "include", "exclude"} {
 					if cmd.Flag(flag).Changed {
 						return fmt.Errorf("--%s can't be used with --human", flag)
@@ -157,8 +164,12 @@ func NewRetrohuntStartCmd() *cobra.Command {
 		Long:  retrohuntStartCmdHelp,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
 
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -175,10 +186,10 @@ func NewRetrohuntStartCmd() *cobra.Command {
 
 			obj := vt.NewObject("retrohunt_job")
 			obj.SetString("rules", string(rules))
-			obj.SetString("corpus", viper.GetString("corpus"))
+			obj.SetString("corpus", opts.GetString("corpus"))
 
-			before := viper.GetString("before")
-			after := viper.GetString("after")
+			before := opts.GetString("before")
+			after := opts.GetString("after")
 
 			var timeRange map[string]int64
 
@@ -237,7 +248,7 @@ func NewRetrohuntAbortCmd() *cobra.Command {
 		Short: "Abort a retrohunt job",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -257,7 +268,7 @@ func NewRetrohuntDeleteCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -287,11 +298,32 @@ func NewRetrohuntMatchesCmd() *cobra.Command {
 		Short: "Get matches for a retrohunt job",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkExportFlags(cmd); err != nil {
+				return err
+			}
+			matches := vt.URL("intelligence/retrohunt_jobs/%s/matching_files", args[0])
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			if exportRequested(opts) {
+				client, err := NewAPIClient(cmd)
+				if err != nil {
+					return err
+				}
+				it, err := client.Iterator(matches,
+					append(opts.IteratorOptions(), vt.IteratorDescriptorsOnly(true))...)
+				if err != nil {
+					return err
+				}
+				return exportToCollection(cmd, it,
+					fmt.Sprintf("Files matching retrohunt job %s", args[0]))
+			}
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
-			return p.PrintCollection(vt.URL("intelligence/retrohunt_jobs/%s/matching_files", args[0]))
+			return p.PrintCollection(matches)
 		},
 	}
 
@@ -299,10 +331,14 @@ func NewRetrohuntMatchesCmd() *cobra.Command {
 	addIDOnlyFlag(cmd.Flags())
 	addLimitFlag(cmd.Flags())
 	addCursorFlag(cmd.Flags())
+	addExportFlags(cmd.Flags())
 
 	return cmd
 }
 
+var validateRetrohuntJobID = utils.ValidateRegexp(
+	regexp.MustCompile(`^\w+-\d+$`), "malformed retrohunt job ID")
+
 // NewRetrohuntCmd returns a new instance of the 'retrohunt' command.
 func NewRetrohuntCmd() *cobra.Command {
 
@@ -314,19 +350,25 @@ func NewRetrohuntCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			re, _ := regexp.Compile("\\w+-\\d+")
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, validateRetrohuntJobID)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"intelligence/retrohunt_jobs/%s",
-				utils.StringReaderFromCmdArgs(args),
-				re)
+				r,
+				nil)
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addStrictFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 
 	cmd.AddCommand(NewRetrohuntAbortCmd())
 	cmd.AddCommand(NewRetrohuntDeleteCmd())
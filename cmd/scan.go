@@ -15,16 +15,21 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/VirusTotal/vt-cli/coordinator"
+	"github.com/VirusTotal/vt-cli/pkg/vtcli"
 	"github.com/VirusTotal/vt-cli/utils"
 	vt "github.com/VirusTotal/vt-go"
+	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"github.com/spf13/viper"
 )
 
 const (
@@ -38,9 +43,9 @@ const (
 
 // waitForAnalysisResults calls every PollFrequency seconds to the VT API and
 // checks whether an analysis is completed or not. When the analysis is completed
-// it is returned.
-func waitForAnalysisResults(cli *utils.APIClient, analysisId string, ds *utils.DoerState) (*vt.Object, error) {
-	ds.Progress = "Waiting for analysis completion..."
+// it is returned. For private analyses prefix must be "private/".
+func waitForAnalysisResults(cli *utils.APIClient, prefix, analysisId string, ds *utils.DoerState) (*vt.Object, error) {
+	ds.SetProgress("Waiting for analysis completion...")
 	ticker := time.NewTicker(PollFrequency)
 	defer ticker.Stop()
 	ctx, cancel := context.WithTimeout(context.Background(), TimeoutLimit)
@@ -52,20 +57,20 @@ func waitForAnalysisResults(cli *utils.APIClient, analysisId string, ds *utils.D
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-ticker.C:
-			ds.Progress = fmt.Sprintf("Waiting for analysis completion...%s", strings.Repeat(".", i))
+			ds.SetProgress("Waiting for analysis completion...%s", strings.Repeat(".", i))
 			i++
-			if obj, err := cli.GetObject(vt.URL(fmt.Sprintf("analyses/%s", analysisId))); err != nil {
+			if obj, err := cli.GetObject(vt.URL("%sanalyses/%s", prefix, analysisId)); err != nil {
 				// If the API returned an error 503 (transient error) retry; otherwise just return
 				// the error to the user.
 				if e, ok := err.(*vt.Error); !ok || e.Code != "TransientError" {
-					ds.Progress = ""
+					ds.SetProgress("")
 					return nil, fmt.Errorf("error retrieving analysis result: %v", err)
 				}
 			} else if status, _ := obj.Get("status"); status == "completed" {
-				ds.Progress = ""
+				ds.SetProgress("")
 				// Request the full object report and return it instead of just
 				// the analysis results.
-				return cli.GetObject(vt.URL(fmt.Sprintf("analyses/%s/item", analysisId)))
+				return cli.GetObject(vt.URL("%sanalyses/%s/item", prefix, analysisId))
 			}
 		}
 	}
@@ -82,15 +87,22 @@ type fileScanner struct {
 
 func (s *fileScanner) Do(path interface{}, ds *utils.DoerState) string {
 
+	// Wait for the goroutine reporting the progress before returning, so it
+	// doesn't overwrite the progress of the next item handled by this doer.
 	progressCh := make(chan float32)
-	defer close(progressCh)
+	progressDone := make(chan struct{})
+	defer func() {
+		close(progressCh)
+		<-progressDone
+	}()
 
 	go func() {
+		defer close(progressDone)
 		for progress := range progressCh {
 			if progress < 100 {
-				ds.Progress = fmt.Sprintf("%s uploading... %4.1f%%", path, progress)
+				ds.SetProgress("%s uploading... %4.1f%%", path, progress)
 			} else {
-				ds.Progress = fmt.Sprintf("%s scanning...", path)
+				ds.SetProgress("%s scanning...", path)
 			}
 		}
 	}()
@@ -120,7 +132,7 @@ func (s *fileScanner) Do(path interface{}, ds *utils.DoerState) string {
 	}
 
 	if s.waitForCompletion {
-		analysisResult, err := waitForAnalysisResults(s.cli, analysis.ID(), ds)
+		analysisResult, err := waitForAnalysisResults(s.cli, "", analysis.ID(), ds)
 		if err != nil {
 			return err.Error()
 		}
@@ -159,18 +171,27 @@ func NewScanFileCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c := utils.NewCoordinator(viper.GetInt("threads"))
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			c, err := NewCoordinator(cmd)
+			if err != nil {
+				return err
+			}
 			var argReader utils.StringReader
-			if len(args) == 1 && args[0] == "-" {
-				argReader = utils.NewStringIOReader(os.Stdin)
-			} else if len(args) == 1 && utils.IsDir(args[0]) {
-				recursive := viper.GetBool("recursive")
-				maxDepth := viper.GetInt("maxDepth")
+			if len(args) == 1 && utils.IsDir(args[0]) {
+				recursive := opts.GetBool("recursive")
+				maxDepth := opts.GetInt("maxDepth")
 				argReader, _ = utils.NewFileDirReader(args[0], recursive, maxDepth)
 			} else {
-				argReader = utils.NewStringArrayReader(args)
+				r, err := NewStringReader(cmd, args, nil, nil)
+				if err != nil {
+					return err
+				}
+				argReader = r
 			}
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -180,13 +201,12 @@ func NewScanFileCmd() *cobra.Command {
 			}
 			s := &fileScanner{
 				scanner:           client.NewFileScanner(),
-				showInVT:          viper.GetBool("open"),
-				waitForCompletion: viper.GetBool("wait"),
-				password:          viper.GetString("password"),
+				showInVT:          opts.GetBool("open"),
+				waitForCompletion: opts.GetBool("wait"),
+				password:          opts.GetString("password"),
 				printer:           p,
 				cli:               client}
-			c.DoWithStringsFromReader(s, argReader)
-			return nil
+			return c.DoWithStringsFromReader(s, argReader)
 		},
 	}
 
@@ -197,21 +217,24 @@ func NewScanFileCmd() *cobra.Command {
 	addPasswordFlag(cmd.Flags())
 	addWaitForCompletionFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
+	addInputFlags(cmd.Flags())
 	cmd.MarkZshCompPositionalArgumentFile(1)
 
 	return cmd
 }
 
 type urlScanner struct {
-	scanner           *vt.URLScanner
 	cli               *utils.APIClient
 	printer           *utils.Printer
+	scanOpts          []vtcli.URLScanOption
+	private           bool
 	showInVT          bool
 	waitForCompletion bool
 }
 
 func (s *urlScanner) Do(url interface{}, ds *utils.DoerState) string {
-	analysis, err := s.scanner.Scan(url.(string))
+	ds.SetProgress("%s scanning...", url)
+	analysis, err := s.cli.Wrapped().ScanURL(context.Background(), url.(string), s.scanOpts...)
 	if err != nil {
 		return err.Error()
 	}
@@ -222,7 +245,7 @@ func (s *urlScanner) Do(url interface{}, ds *utils.DoerState) string {
 	}
 
 	if s.waitForCompletion {
-		analysisResult, err := waitForAnalysisResults(s.cli, analysis.ID(), ds)
+		analysisResult, err := waitForAnalysisResults(s.cli, s.prefix(), analysis.ID(), ds)
 		if err != nil {
 			return err.Error()
 		}
@@ -233,6 +256,106 @@ func (s *urlScanner) Do(url interface{}, ds *utils.DoerState) string {
 	return fmt.Sprintf("%s %s", url, analysis.ID())
 }
 
+// prefix returns the prefix of the API endpoints for the analyses.
+func (s *urlScanner) prefix() string {
+	if s.private {
+		return "private/"
+	}
+	return ""
+}
+
+// scanAndWait scans url and returns the URL object once the analysis is
+// completed.
+func (s *urlScanner) scanAndWait(ctx context.Context, url string, ds *coordinator.State) (*vt.Object, error) {
+	ds.SetProgress("%s scanning...", url)
+	analysis, err := s.cli.Wrapped().ScanURL(ctx, url, s.scanOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return waitForAnalysisResults(s.cli, s.prefix(), analysis.ID(), ds)
+}
+
+// urlScanSummary is a coordinator sink that collects the results of multiple
+// URL scans, indexed by the position of the URLs in the input, so the same
+// URL can appear more than once.
+type urlScanSummary struct {
+	rows map[int]map[string]interface{}
+}
+
+func newURLScanSummary() *urlScanSummary {
+	return &urlScanSummary{rows: make(map[int]map[string]interface{})}
+}
+
+// Put adds the result of scanning a URL, which is the URL object returned
+// after the analysis is completed, or the error that prevented it.
+func (s *urlScanSummary) Put(r coordinator.Result[string, *vt.Object]) error {
+	row := map[string]interface{}{"url": r.Input}
+	if r.Err != nil {
+		row["error"] = r.Err.Error()
+	} else {
+		obj := r.Output
+		row["final_url"], _ = obj.GetString("last_final_url")
+		row["http_status"], _ = obj.GetInt64("last_http_response_code")
+		row["title"], _ = obj.GetString("title")
+		row["malicious"], _ = obj.GetInt64("last_analysis_stats.malicious")
+		row["suspicious"], _ = obj.GetInt64("last_analysis_stats.suspicious")
+		row["categories"] = urlCategories(obj)
+	}
+	s.rows[r.Index] = row
+	return nil
+}
+
+func (s *urlScanSummary) Close() error {
+	return nil
+}
+
+// list returns the rows in the summary.
+func (s *urlScanSummary) list() []map[string]interface{} {
+	indexes := make([]int, 0, len(s.rows))
+	for i := range s.rows {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	list := make([]map[string]interface{}, len(indexes))
+	for i, index := range indexes {
+		list[i] = s.rows[index]
+	}
+	return list
+}
+
+// table prints the summary as a table.
+func (s *urlScanSummary) table(w io.Writer) {
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.AddRow("URL", "FINAL URL", "STATUS", "TITLE", "MALICIOUS", "SUSPICIOUS", "CATEGORIES")
+	for _, row := range s.list() {
+		if err, ok := row["error"]; ok {
+			table.AddRow(row["url"], "", "", fmt.Sprintf("error: %s", err), "", "", "")
+			continue
+		}
+		table.AddRow(row["url"], row["final_url"], row["http_status"], row["title"],
+			row["malicious"], row["suspicious"], row["categories"])
+	}
+	fmt.Fprintln(w, table)
+}
+
+// urlCategories returns the distinct categories assigned to a URL by the
+// different categorization services, sorted and separated by commas.
+func urlCategories(obj *vt.Object) string {
+	v, _ := obj.Get("categories")
+	m, _ := v.(map[string]interface{})
+	seen := make(map[string]bool)
+	var categories []string
+	for _, c := range m {
+		if c, ok := c.(string); ok && !seen[c] {
+			seen[c] = true
+			categories = append(categories, c)
+		}
+	}
+	sort.Strings(categories)
+	return strings.Join(categories, ", ")
+}
+
 var scanURLCmdHelp = `Scan one or more URLs.
 
 This command receives one or more URLs and scan them. It returns the URLs followed
@@ -241,11 +364,23 @@ retrieving information about the analyses or you can use the --wait
 flag to see the results when the analysis is completed.
 
 If the command receives a single hypen (-) the URLs are read from the standard
-input, one per line.`
+input, one per line.
+
+With --private the URLs are scanned privately, which also allows choosing the
+User-Agent used for fetching them and how the sandbox interacts with them.
+Custom request headers and POST bodies are not accepted by the API.
+
+With --summary the command waits for all the analyses to complete and prints a
+single list with the URL, the final URL after redirects, the HTTP status code,
+the page title, the number of engines that consider it malicious or suspicious,
+and its categories. Use --human for printing the list as a table, or
+--format csv for a spreadsheet.`
 
 var scanURLCmdExample = `  vt scan url http://foo.com
   vt scan url http://foo.com http://bar.com
-  cat list_of_urls | vt scan urls -`
+  cat list_of_urls | vt scan urls -
+  cat reported_urls | vt scan url - --summary --human
+  vt scan url http://foo.com --private --user-agent "Mozilla/5.0 (iPhone)" --wait`
 
 // NewScanURLCmd returns a new instance of the 'scan url' command.
 func NewScanURLCmd() *cobra.Command {
@@ -258,14 +393,19 @@ func NewScanURLCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c := utils.NewCoordinator(viper.GetInt("threads"))
-			var argReader utils.StringReader
-			if len(args) == 1 && args[0] == "-" {
-				argReader = utils.NewStringIOReader(os.Stdin)
-			} else {
-				argReader = utils.NewStringArrayReader(args)
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			c, err := NewCoordinator(cmd)
+			if err != nil {
+				return err
+			}
+			argReader, err := NewStringReader(cmd, args, utils.NormalizeURL, nil)
+			if err != nil {
+				return err
 			}
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -274,19 +414,76 @@ func NewScanURLCmd() *cobra.Command {
 				return err
 			}
 			s := &urlScanner{
-				scanner:           client.NewURLScanner(),
-				showInVT:          viper.GetBool("open"),
-				waitForCompletion: viper.GetBool("wait"),
+				showInVT:          opts.GetBool("open"),
+				waitForCompletion: opts.GetBool("wait"),
+				private:           opts.GetBool("private"),
 				printer:           p,
 				cli:               client}
-			c.DoWithStringsFromReader(s, argReader)
-			return nil
+			if s.private {
+				s.scanOpts = append(s.scanOpts, vtcli.PrivateScan())
+			}
+			for _, flag := range []string{"user-agent", "interaction-sandbox", "interaction-timeout"} {
+				if opts.IsSet(flag) && !s.private {
+					return fmt.Errorf("--%s can only be used with --private", flag)
+				}
+			}
+			if ua := opts.GetString("user-agent"); ua != "" {
+				s.scanOpts = append(s.scanOpts, vtcli.WithUserAgent(ua))
+			}
+			if sandbox := opts.GetString("interaction-sandbox"); sandbox != "" {
+				s.scanOpts = append(s.scanOpts, vtcli.WithInteractionSandbox(sandbox))
+			}
+			if timeout := opts.GetDuration("interaction-timeout"); timeout > 0 {
+				s.scanOpts = append(s.scanOpts, vtcli.WithInteractionTimeout(timeout))
+			}
+			if !opts.GetBool("summary") {
+				return c.DoWithStringsFromReader(s, argReader)
+			}
+			if s.showInVT {
+				return errors.New("--summary can't be used with --open")
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			urls, readErr := utils.StringChannel(ctx, argReader)
+			summary := newURLScanSummary()
+			err = coordinator.New[string, *vt.Object](opts.Threads, summary).Run(
+				ctx, coordinator.DoerFunc[string, *vt.Object](s.scanAndWait), urls)
+			if err != nil {
+				return err
+			}
+			if err := readErr(); err != nil {
+				return err
+			}
+			if opts.GetBool("human") {
+				summary.table(opts.Stdout)
+				return nil
+			}
+			return p.Print(summary.list())
 		},
 	}
 
 	addThreadsFlag(cmd.Flags())
 	addOpenInVTFlag(cmd.Flags())
 	addWaitForCompletionFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
+	addHumanFlag(cmd.Flags())
+
+	cmd.Flags().BoolP(
+		"private", "P", false,
+		"scan privately (requires private scanning privileges)")
+	cmd.Flags().String(
+		"user-agent", "",
+		"User-Agent used for fetching the URLs (requires --private)")
+	cmd.Flags().String(
+		"interaction-sandbox", "",
+		"sandbox used for interacting with the URLs (requires --private)")
+	cmd.Flags().Duration(
+		"interaction-timeout", 0,
+		"time the sandbox interacts with the URLs, like 60s (requires --private)")
+	cmd.Flags().Bool(
+		"summary", false,
+		"wait for all the analyses and print a summary of the verdicts")
 
 	return cmd
 }
@@ -14,19 +14,28 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
-	"github.com/spf13/viper"
-
+	"github.com/VirusTotal/vt-cli/query"
 	"github.com/VirusTotal/vt-cli/utils"
 	vt "github.com/VirusTotal/vt-go"
+	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 )
 
 func preRunSearchCmd(c *cobra.Command, args []string) error {
+	if err := checkExportFlags(c, "download"); err != nil {
+		return err
+	}
 
-	if viper.GetBool("download") {
+	opts, err := utils.CommandOptions(c)
+	if err != nil {
+		return err
+	}
+	if opts.GetBool("download") {
 		for _, flag := range []string{"include", "identifiers-only"} {
 			if c.Flag(flag).Changed {
 				return fmt.Errorf("--%s can't be used with --download", flag)
@@ -43,30 +52,72 @@ func preRunSearchCmd(c *cobra.Command, args []string) error {
 	return nil
 }
 
+// lintQuery returns q with relative dates expanded if --lint was used, after
+// checking that it doesn't have errors. The errors and warnings found are
+// printed to stderr.
+func lintQuery(cmd *cobra.Command, q string) (string, error) {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return "", err
+	}
+	if !opts.GetBool("lint") {
+		return q, nil
+	}
+	errs := query.Lint(q)
+	for _, err := range errs {
+		fmt.Fprintln(opts.Stderr, err)
+	}
+	if n := len(query.Errors(errs)); n > 0 {
+		return "", fmt.Errorf("%d errors found in the query", n)
+	}
+	return query.ExpandDates(q, time.Now())
+}
+
 func runSearchCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	q, err := lintQuery(cmd, args[0])
+	if err != nil {
+		return err
+	}
 
 	batchSize := 25
-	if viper.GetInt("limit") < batchSize {
-		batchSize = viper.GetInt("limit")
+	if opts.Limit < batchSize {
+		batchSize = opts.Limit
 	}
 
-	client, err := NewAPIClient()
+	client, err := NewAPIClient(cmd)
 	if err != nil {
 		return err
 	}
 
-	it, err := client.Search(args[0],
-		vt.IteratorLimit(viper.GetInt("limit")),
-		vt.IteratorCursor(viper.GetString("cursor")),
+	// Full objects are retrieved when exporting to a collection, instead of
+	// descriptors only, because searches for URLs return URL objects that
+	// must be added to the collection by their URL.
+	it, err := client.Search(q,
+		vt.IteratorLimit(opts.Limit),
+		vt.IteratorCursor(opts.Cursor),
 		vt.IteratorBatchSize(batchSize),
 		vt.IteratorDescriptorsOnly(
-			viper.GetBool("identifiers-only") || viper.GetBool("download")))
+			(opts.IdentifiersOnly || opts.GetBool("download")) && !exportRequested(opts)))
 
 	if err != nil {
 		return err
 	}
 
-	if viper.GetBool("download") {
+	if exportRequested(opts) {
+		return exportToCollection(cmd, it,
+			fmt.Sprintf("Files matching the VirusTotal Intelligence search: %s", q))
+	}
+
+	if opts.GetBool("download") {
+		c, err := NewCoordinator(cmd)
+		if err != nil {
+			return err
+		}
 		ch := make(chan interface{})
 		go func() {
 			for it.Next() {
@@ -75,8 +126,7 @@ func runSearchCmd(cmd *cobra.Command, args []string) error {
 			}
 			close(ch)
 		}()
-		c := utils.NewCoordinator(viper.GetInt("threads"))
-		c.DoWithItemsFromChannel(&downloader{newFileDownloader(client)}, ch)
+		c.DoWithItemsFromChannel(&downloader{newFileDownloader(client, opts)}, ch)
 		return it.Error()
 	}
 
@@ -88,10 +138,22 @@ func runSearchCmd(cmd *cobra.Command, args []string) error {
 	return p.PrintIterator(it)
 }
 
-var cmdSearchHelp = `Search for files using VirusTotal Intelligence's query language.`
+var cmdSearchHelp = `Search for files using VirusTotal Intelligence's query language.
+
+With --to-collection the matching files are put in a new collection with the
+given name, and with --append-to they are added to an existing collection. Use
+--limit for setting the maximum number of files added.
+
+With --lint the query is checked locally before searching, and the search is
+not done if it has values with the wrong syntax or unbalanced parentheses.
+Modifiers unknown to vt are reported as warnings, but don't prevent the
+search. Relative dates, like in fs:7d+ (first seen within the last 7 days), are
+replaced with absolute dates.`
 
 var cmdSearchExample = `  vt search eicar
-  vt search "foobar p:1+"`
+  vt search "foobar p:1+"
+  vt search "foobar p:1+" --limit 1000 --to-collection "Foobar campaign"
+  vt search "type:peexe fs:7d+" --lint`
 
 // NewSearchCmd returns a new instance of the 'search' command.
 func NewSearchCmd() *cobra.Command {
@@ -116,8 +178,13 @@ func NewSearchCmd() *cobra.Command {
 	addLimitFlag(cmd.Flags())
 	addCursorFlag(cmd.Flags())
 	addOutputFlag(cmd.Flags())
+	addExportFlags(cmd.Flags())
+	addLintFlag(cmd.Flags())
 
+	cmd.AddCommand(NewSearchBuildCmd())
 	cmd.AddCommand(NewContentSearchCmd())
+	cmd.AddCommand(NewSearchStatsCmd())
+	cmd.AddCommand(NewSearchWatchCmd())
 
 	return cmd
 }
@@ -166,13 +233,17 @@ func getIgnoredSubstrings(meta map[string]interface{}) []string {
 }
 
 func runContentSearchCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
 
 	batchSize := 10
-	if viper.GetInt("limit") < batchSize {
-		batchSize = viper.GetInt("limit")
+	if opts.Limit < batchSize {
+		batchSize = opts.Limit
 	}
 
-	client, err := NewAPIClient()
+	client, err := NewAPIClient(cmd)
 	if err != nil {
 		return err
 	}
@@ -183,23 +254,26 @@ func runContentSearchCmd(cmd *cobra.Command, args []string) error {
 	}
 
 	it, err := client.Search(strings.Join(terms, " "),
-		vt.IteratorLimit(viper.GetInt("limit")),
-		vt.IteratorCursor(viper.GetString("cursor")),
+		vt.IteratorLimit(opts.Limit),
+		vt.IteratorCursor(opts.Cursor),
 		vt.IteratorBatchSize(batchSize),
 		vt.IteratorDescriptorsOnly(
-			viper.GetBool("identifiers-only") || viper.GetBool("download")))
+			opts.IdentifiersOnly || opts.GetBool("download")))
 
 	if err != nil {
 		return err
 	}
 
-	c := utils.NewCoordinator(viper.GetInt("threads"))
+	c, err := NewCoordinator(cmd)
+	if err != nil {
+		return err
+	}
 
 	var doer utils.Doer
-	if viper.GetBool("download") {
-		doer = &downloader{newFileDownloader(client)}
+	if opts.GetBool("download") {
+		doer = &downloader{newFileDownloader(client, opts)}
 	} else {
-		doer = &matchPrinter{client, viper.GetBool("identifiers-only")}
+		doer = &matchPrinter{client, opts.IdentifiersOnly}
 		c.EnableSpinner()
 	}
 
@@ -254,3 +328,220 @@ func NewContentSearchCmd() *cobra.Command {
 
 	return cmd
 }
+
+// searchStatsBatchSize is the number of files retrieved in each request made
+// by 'search stats', which is the maximum allowed by the API.
+const searchStatsBatchSize = 300
+
+func runSearchStatsCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	var aggs []utils.Aggregation
+	for _, spec := range opts.GetStringSlice("by") {
+		agg, err := utils.ParseAggregation(spec)
+		if err != nil {
+			return err
+		}
+		aggs = append(aggs, agg)
+	}
+	if len(aggs) == 0 {
+		return errors.New("--by requires at least one field")
+	}
+
+	q, err := lintQuery(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	batchSize := searchStatsBatchSize
+	if opts.Limit < batchSize {
+		batchSize = opts.Limit
+	}
+
+	it, err := client.Search(q,
+		vt.IteratorLimit(opts.Limit),
+		vt.IteratorBatchSize(batchSize))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	stats := utils.NewStats(aggs)
+	for it.Next() {
+		stats.Add(it.Get())
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	// The total number of hits is not included in the response for every
+	// query, when missing it's unknown how many files were left out.
+	total := "unknown"
+	if n, ok := it.Meta()["total_hits"]; ok {
+		total = fmt.Sprint(n)
+	}
+	summary := fmt.Sprintf("%d files analyzed, total hits: %s", stats.Count, total)
+
+	if opts.GetBool("human") {
+		table := uitable.New()
+		table.MaxColWidth = 50
+		table.AddRow("FIELD", "VALUE", "COUNT", "PERCENT")
+		table.RightAlign(2)
+		table.RightAlign(3)
+		for _, row := range stats.Rows() {
+			table.AddRow(row.Field, row.Value, row.Count, fmt.Sprintf("%.1f%%", row.Percent))
+		}
+		fmt.Fprintln(opts.Stdout, summary)
+		fmt.Fprintln(opts.Stdout)
+		fmt.Fprintln(opts.Stdout, table)
+		return nil
+	}
+
+	if !opts.Silent {
+		fmt.Fprintln(opts.Stderr, summary)
+	}
+
+	p, err := NewPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.Print(stats.Rows())
+}
+
+var cmdSearchStatsHelp = `Compute statistics about the files matching a search.
+
+This command retrieves up to --limit files matching a VirusTotal Intelligence
+query and counts them locally, grouped by the fields given with --by. It helps
+knowing the shape of a result set before downloading the files or starting a
+retrohunt job.
+
+Each field in --by can be followed by a modifier:
+
+  field              counts the files for each distinct value of the field. If
+                     the field is a list, like tags, each element is counted.
+  field:bucket=N     counts the files in intervals of width N of a numeric field.
+  field:day|week|month|year
+                     counts the files by period of a date field.
+
+Files without the field are counted as (none). Percentages are relative to the
+number of files analyzed, which can be lower than the total number of hits.`
+
+var cmdSearchStatsExample = `  vt search stats "p:5+" --by type_tag,tags
+  vt search stats "tag:peexe" --limit 5000 --by last_analysis_stats.malicious:bucket=10 --human
+  vt search stats "foobar" --by first_submission_date:month --format csv`
+
+// NewSearchStatsCmd returns a new instance of the 'search stats' command.
+func NewSearchStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Args:    cobra.ExactArgs(1),
+		Use:     "stats [query]",
+		Short:   "Compute statistics about the files matching a search",
+		Long:    cmdSearchStatsHelp,
+		Example: cmdSearchStatsExample,
+		RunE:    runSearchStatsCmd,
+	}
+
+	cmd.Flags().StringSlice(
+		"by", []string{"type_tag"},
+		"fields by which files are grouped")
+	cmd.Flags().IntP(
+		"limit", "n", 1000,
+		"maximum number of files analyzed")
+
+	addHumanFlag(cmd.Flags())
+	addLintFlag(cmd.Flags())
+
+	return cmd
+}
+
+// searchBuildFlags are the flags accepted by 'search build' and the modifiers
+// they correspond to, in the order in which they appear in the query.
+var searchBuildFlags = []struct {
+	flag, modifier string
+	// since is true for dates that are the start of a period if they don't
+	// end with + or -.
+	since bool
+}{
+	{flag: "type", modifier: "type"},
+	{flag: "positives", modifier: "p"},
+	{flag: "size", modifier: "size"},
+	{flag: "first-seen", modifier: "fs", since: true},
+	{flag: "last-seen", modifier: "ls", since: true},
+	{flag: "tag", modifier: "tag"},
+	{flag: "engines", modifier: "engines"},
+	{flag: "name", modifier: "name"},
+	{flag: "submitter", modifier: "submitter"},
+}
+
+func runSearchBuildCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	b := query.NewBuilder(time.Now())
+	for _, f := range searchBuildFlags {
+		for _, value := range opts.GetStringSlice(f.flag) {
+			if f.since && !strings.HasSuffix(value, "+") && !strings.HasSuffix(value, "-") {
+				value += "+"
+			}
+			if err := b.Add(f.modifier, value); err != nil {
+				return fmt.Errorf("--%s: %v", f.flag, err)
+			}
+		}
+	}
+	for _, q := range args {
+		if err := b.AddQuery(q); err != nil {
+			return err
+		}
+	}
+	if b.String() == "" {
+		return errors.New("the query is empty, use at least one flag or argument")
+	}
+	fmt.Fprintln(opts.Stdout, b.String())
+	return nil
+}
+
+var cmdSearchBuildHelp = `Build a VirusTotal Intelligence query.
+
+This command prints a query composed of the modifiers given with flags, and
+the queries given as arguments, which are checked like with 'vt search --lint'.
+All of them must match. Dates can be absolute (2024-01-31 or
+2024-01-31T10:00:00) or relative (7d, meaning 7 days ago, also with units h
+and w), and relative dates are replaced with absolute ones. Dates given with
+--first-seen and --last-seen match files seen since that date, unless they end
+with - (before that date).`
+
+var cmdSearchBuildExample = `  vt search build --type peexe --positives 5+ --first-seen 7d
+  vt search build --tag signed --tag overlay --size 1mb- "NOT engines:emotet"
+  vt search "$(vt search build --type pdf --last-seen 24h)"`
+
+// NewSearchBuildCmd returns a new instance of the 'search build' command.
+func NewSearchBuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "build [query]...",
+		Short:   "Build a VirusTotal Intelligence query",
+		Long:    cmdSearchBuildHelp,
+		Example: cmdSearchBuildExample,
+		RunE:    runSearchBuildCmd,
+	}
+
+	cmd.Flags().StringArray("type", nil, "file type (type:)")
+	cmd.Flags().StringArray("positives", nil, "number of detections, like 5+ (p:)")
+	cmd.Flags().StringArray("size", nil, "file size, like 100kb+ (size:)")
+	cmd.Flags().StringArray("first-seen", nil, "first submission date, like 7d or 2024-01-31 (fs:)")
+	cmd.Flags().StringArray("last-seen", nil, "last submission date, like 7d or 2024-01-31 (ls:)")
+	cmd.Flags().StringArray("tag", nil, "tag (tag:)")
+	cmd.Flags().StringArray("engines", nil, "detection name given by any engine (engines:)")
+	cmd.Flags().StringArray("name", nil, "file name (name:)")
+	cmd.Flags().StringArray("submitter", nil, "submitter country (submitter:)")
+
+	return cmd
+}
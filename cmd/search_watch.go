@@ -0,0 +1,289 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+// searchWatcher runs a search periodically and reports the objects that
+// were not returned by previous runs.
+type searchWatcher struct {
+	cmd       *cobra.Command
+	opts      *utils.Options
+	client    *utils.APIClient
+	query     string
+	state     *utils.WatchState
+	statePath string
+}
+
+// run runs the search once. The state is updated only if the new objects
+// were reported successfully, otherwise they are reported again by the next
+// run.
+func (w *searchWatcher) run(now time.Time) error {
+	limit := w.opts.Limit
+	it, err := w.client.Search(w.query,
+		vt.IteratorLimit(limit),
+		vt.IteratorBatchSize(min(limit, searchStatsBatchSize)))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	var matches, fresh []*vt.Object
+	for it.Next() {
+		obj := it.Get()
+		matches = append(matches, obj)
+		if !w.state.Known(obj.ID()) {
+			fresh = append(fresh, obj)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	if w.opts.GetBool("download") {
+		ch := make(chan interface{}, len(fresh))
+		for _, obj := range fresh {
+			ch <- obj.ID()
+		}
+		close(ch)
+		c, err := NewCoordinator(w.cmd)
+		if err != nil {
+			return err
+		}
+		c.DoWithItemsFromChannel(
+			&downloader{newFileDownloader(w.client, w.opts)}, ch)
+	} else if err := w.print(fresh); err != nil {
+		return err
+	}
+
+	status := fmt.Sprintf("%d new results out of %d", len(fresh), len(matches))
+	if exportRequested(w.opts) && len(fresh) > 0 {
+		items := make([]string, len(fresh))
+		for i, obj := range fresh {
+			items[i] = collectionItem(obj)
+		}
+		id := w.state.Collection
+		if id == "" {
+			id = w.opts.GetString("append-to")
+		}
+		id, err = exportItems(w.client, id,
+			w.opts.GetString("to-collection"),
+			fmt.Sprintf("Files matching the VirusTotal Intelligence search: %s", w.query),
+			items)
+		if id != "" {
+			w.state.Collection = id
+		}
+		if err != nil {
+			return err
+		}
+		status += fmt.Sprintf(", added to collection %s", id)
+	}
+
+	for _, obj := range matches {
+		w.state.Mark(obj.ID(), now)
+	}
+	w.state.Forget(now.Add(-w.opts.GetDuration("window")))
+	if err := w.state.Save(w.statePath); err != nil {
+		return err
+	}
+
+	if !w.opts.Silent {
+		fmt.Fprintf(w.opts.Stderr, "%s: %s\n", now.Format(time.RFC3339), status)
+	}
+	return nil
+}
+
+// print prints the new objects as JSON Lines, as a table with --human, or
+// only their identifiers with --identifiers-only.
+func (w *searchWatcher) print(objs []*vt.Object) error {
+	if w.opts.IdentifiersOnly {
+		for _, obj := range objs {
+			fmt.Fprintln(w.opts.Stdout, obj.ID())
+		}
+		return nil
+	}
+	if w.opts.GetBool("human") {
+		if len(objs) == 0 {
+			return nil
+		}
+		table := uitable.New()
+		table.MaxColWidth = 50
+		table.AddRow("ID", "TYPE", "MALICIOUS", "FIRST SUBMISSION", "NAME")
+		for _, obj := range objs {
+			firstSubmission := ""
+			if t, err := obj.GetTime("first_submission_date"); err == nil {
+				firstSubmission = humanize.Time(t)
+			}
+			malicious, _ := obj.GetInt64("last_analysis_stats.malicious")
+			name, _ := obj.GetString("meaningful_name")
+			table.AddRow(obj.ID(), obj.Type(), malicious, firstSubmission, name)
+		}
+		fmt.Fprintln(w.opts.Stdout, table)
+		return nil
+	}
+	enc := json.NewEncoder(w.opts.Stdout)
+	for _, obj := range objs {
+		m := utils.ObjectToMap(obj)
+		if w.opts.Include != nil || w.opts.Exclude != nil {
+			m = utils.FilterMap(m, w.opts.Include, w.opts.Exclude)
+		}
+		if err := enc.Encode(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runSearchWatchCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := checkExportFlags(cmd); err != nil {
+		return err
+	}
+	if opts.GetBool("download") {
+		for _, flag := range []string{"human", "include", "exclude", "identifiers-only"} {
+			if cmd.Flag(flag).Changed {
+				return fmt.Errorf("--%s can't be used with --download", flag)
+			}
+		}
+	}
+
+	q, err := lintQuery(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	statePath := opts.GetString("state")
+	// The state belongs to the query as given by the user, relative dates
+	// expanded by --lint change in every run.
+	state, err := utils.LoadWatchState(statePath, args[0])
+	if err != nil {
+		return err
+	}
+
+	w := &searchWatcher{
+		cmd:       cmd,
+		opts:      opts,
+		client:    client,
+		query:     q,
+		state:     state,
+		statePath: statePath,
+	}
+
+	every := opts.GetDuration("every")
+	if every <= 0 {
+		return w.run(time.Now())
+	}
+
+	// Interrupting the watch lets the current run finish and save the state.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for {
+		// A failed run doesn't stop the watch, the same objects are retried
+		// in the next one.
+		if err := w.run(time.Now()); err != nil {
+			fmt.Fprintf(opts.Stderr, "%s: %v\n", time.Now().Format(time.RFC3339), err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(every):
+		}
+		if q, err = lintQuery(cmd, args[0]); err == nil {
+			w.query = q
+		}
+	}
+}
+
+var cmdSearchWatchHelp = `Run a search periodically and report only new results.
+
+This command runs a VirusTotal Intelligence search every --every, and prints
+the objects that were not returned by previous runs, one JSON object per line,
+or as a table with --human. It's like a Livehunt ruleset for searches that
+can't be expressed as YARA rules.
+
+The objects already reported are remembered in the file given with --state,
+so the watch can be stopped and resumed, or run from cron without --every.
+Objects that don't match the search for longer than --window are forgotten,
+and are reported again if they match later. Without --state nothing is
+remembered between invocations.
+
+With --download new files are downloaded instead of printed. With --append-to
+new results are added to an existing collection, and with --to-collection to
+a collection that is created the first time there are new results. The ID of
+that collection is kept in the state file, so next runs add to it.`
+
+var cmdSearchWatchExample = `  vt search watch "tag:cve-2026-* p:3+" --every 1h --state watch.json
+  vt search watch "p:5+ fs:1d+" --lint --every 30m --state watch.json --human
+  vt search watch "engines:emotet" --state emotet.json --to-collection "Emotet"`
+
+// NewSearchWatchCmd returns a new instance of the 'search watch' command.
+func NewSearchWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Args:    cobra.ExactArgs(1),
+		Use:     "watch [query]",
+		Short:   "Run a search periodically and report only new results",
+		Long:    cmdSearchWatchHelp,
+		Example: cmdSearchWatchExample,
+		RunE:    runSearchWatchCmd,
+	}
+
+	cmd.Flags().Duration(
+		"every", 0,
+		"time between runs, if not set the search runs once")
+	cmd.Flags().String(
+		"state", "",
+		"file where the results already reported are remembered")
+	cmd.Flags().Duration(
+		"window", 30*24*time.Hour,
+		"forget results that haven't matched the search for this long")
+	cmd.Flags().IntP(
+		"limit", "n", 300,
+		"maximum number of results retrieved in each run")
+	cmd.Flags().BoolP(
+		"download", "d", false,
+		"download new files instead of printing them")
+
+	addIncludeExcludeFlags(cmd.Flags())
+	addIDOnlyFlag(cmd.Flags())
+	addHumanFlag(cmd.Flags())
+	addThreadsFlag(cmd.Flags())
+	addOutputFlag(cmd.Flags())
+	addExportFlags(cmd.Flags())
+	addLintFlag(cmd.Flags())
+
+	return cmd
+}
@@ -0,0 +1,271 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/VirusTotal/vt-cli/hashes"
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+// similarSource is a file for which similar files are searched.
+type similarSource struct {
+	// name is the hash or path given by the user.
+	name string
+	// sha256 is excluded from the similar files.
+	sha256 string
+	// hashes are the hashes of the file indexed by similarity method.
+	hashes map[string]string
+}
+
+// sourceFromObject returns the source for a file known by VirusTotal.
+func sourceFromObject(name string, obj *vt.Object) *similarSource {
+	s := &similarSource{name: name, sha256: obj.ID(), hashes: make(map[string]string)}
+	for _, m := range utils.SimilarityMethods {
+		if h, err := obj.GetString(m.Attribute); err == nil && h != "" {
+			s.hashes[m.Name] = h
+		}
+	}
+	return s
+}
+
+// sourceFromData returns the source for a file unknown to VirusTotal, with the
+// hashes that can be computed locally. The vhash is not among them.
+func sourceFromData(name string, data []byte) *similarSource {
+	f := hashes.Compute(data)
+	s := &similarSource{name: name, sha256: f.SHA256, hashes: make(map[string]string)}
+	for method, h := range map[string]string{
+		"ssdeep":       f.SSDeep,
+		"tlsh":         f.TLSH,
+		"imphash":      f.Imphash,
+		"authentihash": f.Authentihash,
+	} {
+		if h != "" {
+			s.hashes[method] = h
+		}
+	}
+	return s
+}
+
+// newSimilarSource returns the source for an argument, which is either the
+// path of a local file or the hash of a file known by VirusTotal. Local files
+// known by VirusTotal use the hashes in their report.
+func newSimilarSource(client *utils.APIClient, arg string) (*similarSource, error) {
+	info, err := os.Stat(arg)
+	if err != nil || !info.Mode().IsRegular() {
+		obj, err := client.GetObject(vt.URL("files/%s", arg))
+		if err != nil {
+			return nil, err
+		}
+		return sourceFromObject(arg, obj), nil
+	}
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, err
+	}
+	local := sourceFromData(arg, data)
+	obj, err := client.GetObject(vt.URL("files/%s", local.sha256))
+	if apiErr, ok := err.(vt.Error); ok && apiErr.Code == "NotFoundError" {
+		return local, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return sourceFromObject(arg, obj), nil
+}
+
+// similarRow is a file in the output of the similar command.
+type similarRow struct {
+	Source       string   `json:"source" yaml:"source" csv:"source"`
+	SHA256       string   `json:"sha256" yaml:"sha256" csv:"sha256"`
+	Methods      []string `json:"methods" yaml:"methods" csv:"methods"`
+	SSDeepScore  *int     `json:"ssdeep_score" yaml:"ssdeep_score" csv:"ssdeep_score"`
+	TLSHDistance *int     `json:"tlsh_distance" yaml:"tlsh_distance" csv:"tlsh_distance"`
+	Malicious    int64    `json:"malicious" yaml:"malicious" csv:"malicious"`
+	TypeTag      string   `json:"type_tag" yaml:"type_tag" csv:"type_tag"`
+	Name         string   `json:"meaningful_name" yaml:"meaningful_name" csv:"meaningful_name"`
+}
+
+func newSimilarRow(source string, f *utils.SimilarFile) similarRow {
+	row := similarRow{Source: source, SHA256: f.Object.ID(), Methods: f.Methods}
+	if f.SSDeepScore >= 0 {
+		row.SSDeepScore = &f.SSDeepScore
+	}
+	if f.TLSHDistance >= 0 {
+		row.TLSHDistance = &f.TLSHDistance
+	}
+	row.Malicious, _ = f.Object.GetInt64("last_analysis_stats.malicious")
+	row.TypeTag, _ = f.Object.GetString("type_tag")
+	row.Name, _ = f.Object.GetString("meaningful_name")
+	return row
+}
+
+// findSimilar runs a search for each of the source's hashes and returns the
+// files found, ranked.
+func findSimilar(client *utils.APIClient, source *similarSource, methods []string, limit int) ([]*utils.SimilarFile, error) {
+	r := utils.NewSimilarityRanker(source.hashes)
+	for _, m := range utils.SimilarityMethods {
+		h, ok := source.hashes[m.Name]
+		if !ok || !slices.Contains(methods, m.Name) {
+			continue
+		}
+		it, err := client.Search(fmt.Sprintf(`%s:"%s"`, m.Modifier, h),
+			vt.IteratorLimit(limit),
+			vt.IteratorBatchSize(min(limit, searchStatsBatchSize)))
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			if obj := it.Get(); obj.ID() != source.sha256 {
+				r.Add(m.Name, obj)
+			}
+		}
+		err = it.Error()
+		it.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r.Ranked(), nil
+}
+
+func similarityMethodNames() []string {
+	names := make([]string, len(utils.SimilarityMethods))
+	for i, m := range utils.SimilarityMethods {
+		names[i] = m.Name
+	}
+	return names
+}
+
+func runSimilarCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	methods := opts.GetStringSlice("methods")
+	for _, m := range methods {
+		if !slices.Contains(similarityMethodNames(), m) {
+			return fmt.Errorf("unknown similarity method %q", m)
+		}
+	}
+
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	var rows []similarRow
+	for _, arg := range args {
+		source, err := newSimilarSource(client, arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		if !opts.Silent {
+			var used []string
+			for _, m := range utils.SimilarityMethods {
+				if _, ok := source.hashes[m.Name]; ok && slices.Contains(methods, m.Name) {
+					used = append(used, m.Name)
+				}
+			}
+			fmt.Fprintf(opts.Stderr, "%s: searching by %s\n", arg, strings.Join(used, ", "))
+		}
+		similar, err := findSimilar(client, source, methods, opts.Limit)
+		if err != nil {
+			return err
+		}
+		for _, f := range similar {
+			rows = append(rows, newSimilarRow(arg, f))
+		}
+	}
+
+	if opts.IdentifiersOnly {
+		for _, row := range rows {
+			fmt.Fprintln(opts.Stdout, row.SHA256)
+		}
+		return nil
+	}
+
+	if opts.GetBool("human") {
+		table := uitable.New()
+		table.MaxColWidth = 50
+		table.AddRow("SOURCE", "SHA256", "METHODS", "SSDEEP", "TLSH", "MALICIOUS", "TYPE", "NAME")
+		for _, row := range rows {
+			ssdeep, tlsh := "", ""
+			if row.SSDeepScore != nil {
+				ssdeep = fmt.Sprint(*row.SSDeepScore)
+			}
+			if row.TLSHDistance != nil {
+				tlsh = fmt.Sprint(*row.TLSHDistance)
+			}
+			table.AddRow(row.Source, row.SHA256, strings.Join(row.Methods, ","),
+				ssdeep, tlsh, row.Malicious, row.TypeTag, row.Name)
+		}
+		fmt.Fprintln(opts.Stdout, table)
+		return nil
+	}
+
+	p, err := NewPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.Print(rows)
+}
+
+var similarCmdHelp = `Find files similar to the given ones.
+
+This command searches VirusTotal Intelligence for files that share the
+authentihash, vhash or imphash of the given files, or that have a similar
+ssdeep or TLSH hash. Each argument is either the hash of a file known by
+VirusTotal or the path of a local file. The hashes of local files are taken
+from their VirusTotal report if they have one, otherwise they are computed
+locally, except the vhash, which is computed only by VirusTotal.
+
+The files found by the different methods are merged and ranked: first the ones
+found by more methods, then the ones with higher ssdeep scores (from 0 to 100)
+and then the ones with lower TLSH distances. The --limit flag applies to each
+method.`
+
+var similarCmdExample = `  vt similar 44d88612fea8a8f36de82e1278abb02f
+  vt similar ./sample.exe --human
+  vt similar 44d88612fea8a8f36de82e1278abb02f --methods ssdeep,tlsh --limit 50`
+
+// NewSimilarCmd returns a new instance of the 'similar' command.
+func NewSimilarCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "similar [hash|localfile]...",
+		Short:   "Find files similar to the given ones",
+		Long:    similarCmdHelp,
+		Example: similarCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runSimilarCmd,
+	}
+
+	cmd.Flags().StringSlice(
+		"methods", similarityMethodNames(),
+		"similarity methods used")
+	cmd.Flags().IntP(
+		"limit", "n", 20,
+		"maximum number of files found by each method")
+
+	addHumanFlag(cmd.Flags())
+	addIDOnlyFlag(cmd.Flags())
+
+	return cmd
+}
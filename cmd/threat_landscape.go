@@ -0,0 +1,284 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+// getCollectionTTPs returns the MITRE ATT&CK techniques associated to a
+// collection, sorted by technique ID.
+func getCollectionTTPs(client *utils.APIClient, collectionID string) ([]utils.TTP, error) {
+	it, err := client.Iterator(
+		vt.URL("collections/%s/attack_techniques?relationships=attack_tactics", collectionID),
+		vt.IteratorLimit(0))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	ttps := make([]utils.TTP, 0)
+	for it.Next() {
+		obj := it.Get()
+		t := utils.TTP{ID: obj.ID()}
+		t.Name, _ = obj.GetString("name")
+		if r, err := obj.GetRelationship("attack_tactics"); err == nil {
+			for _, tactic := range r.Objects() {
+				t.Tactics = append(t.Tactics, utils.AttackTacticName(tactic.ID()))
+			}
+		}
+		if len(t.Tactics) == 0 {
+			t.Tactics = []string{"Unknown"}
+		}
+		ttps = append(ttps, t)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	sort.Slice(ttps, func(i, j int) bool { return ttps[i].ID < ttps[j].ID })
+	return ttps, nil
+}
+
+func ttpTable(w io.Writer, collectionID string, ttps []utils.TTP) {
+	table := uitable.New()
+	table.MaxColWidth = 60
+	table.AddRow("COLLECTION", "TACTIC", "TECHNIQUE", "NAME")
+	for _, r := range utils.TTPRows(ttps) {
+		table.AddRow(collectionID, r.Tactic, r.ID, r.Name)
+	}
+	fmt.Fprintln(w, table)
+}
+
+// printTTPs prints the MITRE ATT&CK techniques associated to each of the
+// collections read from r.
+func printTTPs(cmd *cobra.Command, r utils.StringReader) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+	p, err := NewPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	result := make([]map[string]interface{}, 0)
+	for {
+		id, err := r.ReadString()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		ttps, err := getCollectionTTPs(client, id)
+		if err != nil {
+			return err
+		}
+		if opts.GetBool("human") {
+			ttpTable(opts.Stdout, id, ttps)
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"_id":        id,
+			"techniques": len(ttps),
+			"tactics":    utils.TTPSummary(ttps),
+		})
+	}
+	if opts.GetBool("human") {
+		return nil
+	}
+	return p.Print(result)
+}
+
+// threatLandscapeType describes a type of collection from the threat
+// landscape, like threat actors or campaigns.
+type threatLandscapeType struct {
+	// Name of the command.
+	Name string
+	// Value of the collection_type attribute for this type of collections.
+	CollectionType string
+	// Human-readable name of the type, in plural form.
+	Plural string
+	// Placeholder for an identifier, used in the command's help.
+	Placeholder string
+}
+
+var (
+	actorType = threatLandscapeType{
+		Name:           "actor",
+		CollectionType: "threat-actor",
+		Plural:         "threat actors",
+		Placeholder:    "<actor_id>",
+	}
+	campaignType = threatLandscapeType{
+		Name:           "campaign",
+		CollectionType: "campaign",
+		Plural:         "campaigns",
+		Placeholder:    "<campaign_id>",
+	}
+	reportType = threatLandscapeType{
+		Name:           "report-object",
+		CollectionType: "report",
+		Plural:         "reports",
+		Placeholder:    "<report_id>",
+	}
+	malwareFamilyType = threatLandscapeType{
+		Name:           "malware-family",
+		CollectionType: "malware-family",
+		Plural:         "malware families",
+		Placeholder:    "<malware_family_id>",
+	}
+)
+
+func newThreatLandscapeListCmd(t threatLandscapeType) *cobra.Command {
+	cmd := &cobra.Command{
+		Aliases: []string{"ls"},
+		Use:     "list",
+		Short:   fmt.Sprintf("List %s", t.Plural),
+		Long: fmt.Sprintf(`List %s.
+
+The --filter flag accepts the same modifiers supported by the collections
+search, like name, tag, source_region or targeted_industry. The filter is
+combined with collection_type:%s.`, t.Plural, t.CollectionType),
+		Example: fmt.Sprintf(`  vt %s list
+  vt %s list --filter "targeted_region:US" --limit 20`, t.Name, t.Name),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			p, err := NewPrinter(cmd)
+			if err != nil {
+				return err
+			}
+			limit := opts.Limit
+			if opts.All {
+				limit = 0
+			}
+			it, err := client.Iterator(vt.URL("collections"),
+				vt.IteratorLimit(limit),
+				vt.IteratorCursor(opts.Cursor),
+				vt.IteratorFilter(utils.CollectionTypeFilter(t.CollectionType, opts.Filter)))
+			if err != nil {
+				return err
+			}
+			defer it.Close()
+			return p.PrintIterator(it)
+		},
+	}
+
+	addIncludeExcludeFlags(cmd.Flags())
+	addIDOnlyFlag(cmd.Flags())
+	addFilterFlag(cmd.Flags())
+	addLimitFlag(cmd.Flags())
+	addAllFlag(cmd.Flags())
+	addCursorFlag(cmd.Flags())
+
+	return cmd
+}
+
+func newThreatLandscapeCmd(t threatLandscapeType) *cobra.Command {
+	use := fmt.Sprintf("[%s]", t.Name)
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s %s...", t.Name, use),
+		Short: fmt.Sprintf("Get information about %s", t.Plural),
+		Long: fmt.Sprintf(`Get information about one or more %s.
+
+This command receives one or more identifiers and returns information about
+them. The information is returned in the same order as the identifiers are
+passed to the command.
+
+With --ttps the MITRE ATT&CK techniques associated to each of them are
+printed instead, grouped by tactic.
+
+If the command receives a single hypen (-) the identifiers are read from the
+standard input, one per line.`, t.Plural),
+		Example: fmt.Sprintf(`  vt %s %s
+  vt %s %s --ttps --human
+  vt %s relationships %s`,
+			t.Name, t.Placeholder, t.Name, t.Placeholder, t.Name, t.Placeholder),
+		Args: cobra.MinimumNArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			r, err := NewStringReader(cmd, args, nil, nil)
+			if err != nil {
+				return err
+			}
+			if opts.GetBool("ttps") {
+				return printTTPs(cmd, r)
+			}
+			p, err := NewPrinter(cmd)
+			if err != nil {
+				return err
+			}
+			return p.GetAndPrintObjects(
+				"collections/%s",
+				r,
+				nil)
+		},
+	}
+
+	cmd.Flags().Bool(
+		"ttps", false,
+		"print a summary of the associated MITRE ATT&CK techniques")
+
+	cmd.AddCommand(newThreatLandscapeListCmd(t))
+
+	addRelationshipCmds(cmd, "collections", "collection", use, false)
+	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
+	addIncludeExcludeFlags(cmd.Flags())
+	addIDOnlyFlag(cmd.Flags())
+	addHumanFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+
+	return cmd
+}
+
+// NewActorCmd returns a new instance of the 'actor' command.
+func NewActorCmd() *cobra.Command {
+	return newThreatLandscapeCmd(actorType)
+}
+
+// NewCampaignCmd returns a new instance of the 'campaign' command.
+func NewCampaignCmd() *cobra.Command {
+	return newThreatLandscapeCmd(campaignType)
+}
+
+// NewReportObjectCmd returns a new instance of the 'report-object' command.
+func NewReportObjectCmd() *cobra.Command {
+	return newThreatLandscapeCmd(reportType)
+}
+
+// NewMalwareFamilyCmd returns a new instance of the 'malware-family' command.
+func NewMalwareFamilyCmd() *cobra.Command {
+	return newThreatLandscapeCmd(malwareFamilyType)
+}
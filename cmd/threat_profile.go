@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/VirusTotal/vt-go"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+	yamlv3 "gopkg.in/yaml.v3"
 
 	"github.com/VirusTotal/vt-cli/utils"
 )
@@ -36,22 +38,30 @@ func NewThreatProfileCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, nil)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"threat_profiles/%s",
-				utils.StringReaderFromCmdArgs(args),
+				r,
 				nil) // No specific regexp for ID needed
 		},
 	}
 
 	addRelationshipCmds(cmd, "threat_profiles", "threat_profile", "[id]", false)
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
+	addInputFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
 
 	cmd.AddCommand(NewThreatProfileListCmd())
 	cmd.AddCommand(NewThreatProfileCreateCmd())
 	cmd.AddCommand(NewThreatProfileUpdateCmd())
 	cmd.AddCommand(NewThreatProfileDeleteCmd())
+	cmd.AddCommand(NewThreatProfileRecommendationsCmd())
+	cmd.AddCommand(NewThreatProfileExportCmd())
 
 	return cmd
 }
@@ -89,10 +99,15 @@ func NewThreatProfileListCmd() *cobra.Command {
 var threatProfileUpdateCmdHelp = `Update a Threat Profile.
 
 This command updates an existing Threat Profile with the specified ID.
-You can update attributes like name, interests, and recommendation configuration.`
+You can update attributes like name, interests, and recommendation configuration.
+
+The attributes can also be read from a YAML or JSON spec file with --from-file,
+like the ones produced by "vt threatprofile export". Flags take precedence over
+the values in the file.`
 
 var threatProfileUpdateCmdExample = `  vt threatprofile update <profile_id> --name "Updated Name"
-  vt threatprofile update <profile_id> --targeted-region "US,CA" --actor-motivation "cybercrime"`
+  vt threatprofile update <profile_id> --targeted-region "US,CA" --actor-motivation "cybercrime"
+  vt threatprofile update <profile_id> --from-file profile.yaml`
 
 // NewThreatProfileUpdateCmd returns a command for updating a Threat Profile.
 func NewThreatProfileUpdateCmd() *cobra.Command {
@@ -104,81 +119,34 @@ func NewThreatProfileUpdateCmd() *cobra.Command {
 		Args:    cobra.ExactArgs(1), // Threat Profile ID is required
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			opts, err := utils.CommandOptions(cmd)
 			if err != nil {
 				return err
 			}
-			printer, err := NewPrinter(cmd)
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
-
-			profileID := args[0]
-			threatProfile := vt.NewObjectWithID("threat_profile", profileID)
-
-			// Check and set flags for attributes
-			if cmd.Flags().Changed("name") {
-				threatProfile.SetString("name", viper.GetString("name"))
-			}
-
-			// Check and set flags for interests
-			interestsData := make(map[string]interface{})
-			interestsChanged := false
-			if cmd.Flags().Changed("targeted-industry") {
-				interestsData["INTEREST_TYPE_TARGETED_INDUSTRY"] = viper.GetStringSlice("targeted-industry")
-				interestsChanged = true
-			}
-			if cmd.Flags().Changed("targeted-region") {
-				interestsData["INTEREST_TYPE_TARGETED_REGION"] = viper.GetStringSlice("targeted-region")
-				interestsChanged = true
-			}
-			if cmd.Flags().Changed("source-region") {
-				interestsData["INTEREST_TYPE_SOURCE_REGION"] = viper.GetStringSlice("source-region")
-				interestsChanged = true
-			}
-			if cmd.Flags().Changed("malware-role") {
-				interestsData["INTEREST_TYPE_MALWARE_ROLE"] = viper.GetStringSlice("malware-role")
-				interestsChanged = true
-			}
-			if cmd.Flags().Changed("actor-motivation") {
-				interestsData["INTEREST_TYPE_ACTOR_MOTIVATION"] = viper.GetStringSlice("actor-motivation")
-				interestsChanged = true
-			}
-			if interestsChanged {
-				threatProfile.Set("interests", interestsData)
+			printer, err := NewPrinter(cmd)
+			if err != nil {
+				return err
 			}
 
-			// Check and set flags for recommendation_config
-			recommendationConfigData := make(map[string]interface{})
-			recommendationConfigChanged := false
-			if cmd.Flags().Changed("max-recs-per-type") {
-				recommendationConfigData["max_recs_per_type"] = viper.GetInt("max-recs-per-type")
-				recommendationConfigChanged = true
-			}
-			if cmd.Flags().Changed("min-categories-matched") {
-				recommendationConfigData["min_categories_matched"] = viper.GetInt("min-categories-matched")
-				recommendationConfigChanged = true
-			}
-			if cmd.Flags().Changed("max-days-since-last-seen") {
-				recommendationConfigData["max_days_since_last_seen"] = viper.GetInt("max-days-since-last-seen")
-				recommendationConfigChanged = true
-			}
-			if recommendationConfigChanged {
-				threatProfile.Set("recommendation_config", recommendationConfigData)
+			spec, err := threatProfileSpecFromCmd(cmd)
+			if err != nil {
+				return err
 			}
 
-			// Need to check if *any* flag was changed besides the default ones
-			// (like --format, --apikey, etc.). If only the ID is provided
-			// without any update flags, it should probably error or do nothing.
-			// Let's check if any of the specific update flags were changed.
-			updateFlagsChanged := cmd.Flags().Changed("name") ||
-				interestsChanged ||
-				recommendationConfigChanged
-
-			if !updateFlagsChanged {
+			// If only the ID is provided without any update flag or spec file
+			// there's nothing to update.
+			if spec.IsEmpty() {
 				return fmt.Errorf("no update flags provided. Use --help for available flags")
 			}
 
+			profileID := args[0]
+			threatProfile := vt.NewObjectWithID("threat_profile", profileID)
+			spec.Apply(threatProfile)
+
 			if err := client.PatchObject(vt.URL("threat_profiles/%s", profileID), threatProfile); err != nil {
 				return err
 			}
@@ -187,13 +155,13 @@ func NewThreatProfileUpdateCmd() *cobra.Command {
 			updatedThreatProfile, err := client.GetObject(vt.URL("threat_profiles/%s", profileID))
 			if err != nil {
 				// If fetching the updated object fails, at least report the patch was successful
-				fmt.Fprintf(os.Stderr, "Warning: Failed to fetch updated threat profile details: %v\n", err)
-				fmt.Printf("Threat profile %s updated successfully.\n", profileID)
+				fmt.Fprintf(opts.Stderr, "Warning: Failed to fetch updated threat profile details: %v\n", err)
+				fmt.Fprintf(opts.Stdout, "Threat profile %s updated successfully.\n", profileID)
 				return nil
 			}
 
-			if viper.GetBool("identifiers-only") {
-				fmt.Printf("%s\n", updatedThreatProfile.ID())
+			if opts.IdentifiersOnly {
+				fmt.Fprintf(opts.Stdout, "%s\n", updatedThreatProfile.ID())
 			} else {
 				return printer.PrintObject(updatedThreatProfile)
 			}
@@ -217,12 +185,67 @@ func NewThreatProfileUpdateCmd() *cobra.Command {
 	cmd.Flags().Int("min-categories-matched", 0, "Min matching categories for recommendation (1-5)")
 	cmd.Flags().Int("max-days-since-last-seen", 0, "Max lookback period in days for recommendations (1-365)")
 
+	addThreatProfileSpecFlags(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
 
 	return cmd
 }
 
+var threatProfileExportCmdHelp = `Export a Threat Profile as a spec file.
+
+This command writes the name, interests and recommendation configuration of a
+Threat Profile in the same format accepted by the --from-file flag of the
+"create" and "update" commands. The output is YAML, unless --format json is
+used.`
+
+var threatProfileExportCmdExample = `  vt threatprofile export <profile_id> > profile.yaml
+  vt threatprofile export <profile_id> --format json > profile.json`
+
+// NewThreatProfileExportCmd returns a command for exporting a Threat Profile
+// as a spec file.
+func NewThreatProfileExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export [id]",
+		Short:   "Export a Threat Profile as a spec file",
+		Long:    threatProfileExportCmdHelp,
+		Example: threatProfileExportCmdExample,
+		Args:    cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			obj, err := client.GetObject(vt.URL("threat_profiles/%s", args[0]))
+			if err != nil {
+				return err
+			}
+			spec := utils.ThreatProfileSpecFromObject(obj)
+			// The output must be read back by --from-file, so it's written with
+			// standard encoders instead of the printer, which adds colors and
+			// comments to the YAML output.
+			switch format := strings.ToLower(opts.Format); format {
+			case "", "yaml":
+				enc := yamlv3.NewEncoder(opts.Stdout)
+				enc.SetIndent(2)
+				return enc.Encode(spec)
+			case "json":
+				enc := json.NewEncoder(opts.Stdout)
+				enc.SetIndent("", "  ")
+				return enc.Encode(spec)
+			default:
+				return fmt.Errorf("unsupported format for export: %s", format)
+			}
+		},
+	}
+	return cmd
+}
+
 var threatProfileDeleteCmdHelp = `Delete one or more Threat Profiles.
 
 This command receives one or more Threat Profile IDs and deletes them.
@@ -242,7 +265,7 @@ func NewThreatProfileDeleteCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -261,9 +284,30 @@ var createThreatProfileCmdHelp = `Creates a Threat Profile.
 
 This command creates a new Threat Profile with the specified name, description,
 interests, and recommendation configuration.
-For interest types, provide comma-separated values if multiple values are needed for a single interest type flag.`
-
-var createThreatProfileCmdExample = `  vt threatprofile create --name "My New Threat Profile" --targeted-region "US,ES"`
+For interest types, provide comma-separated values if multiple values are needed for a single interest type flag.
+
+The Threat Profile can also be read from a YAML or JSON spec file with
+--from-file, like the ones produced by "vt threatprofile export". Flags take
+precedence over the values in the file. Interest values that match known ones
+regardless of case are sent with the known spelling. With --validate they are
+checked locally before creating the profile, and typos are reported with
+suggestions.
+
+Spec file example:
+
+  name: My New Threat Profile
+  interests:
+    targeted_industry: [Financial Services, Insurance]
+    targeted_region: [US, ES]
+    actor_motivation: [Financial Gain]
+  recommendation_config:
+    max_recs_per_type: 10
+    min_categories_matched: 1
+    max_days_since_last_seen: 180`
+
+var createThreatProfileCmdExample = `  vt threatprofile create --name "My New Threat Profile" --targeted-region "US,ES"
+  vt threatprofile create --from-file profile.yaml
+  vt threatprofile create --from-file profile.yaml --name "Copy of my profile"`
 
 // NewThreatProfileCreateCmd returns a command for creating a Threat Profile.
 func NewThreatProfileCreateCmd() *cobra.Command {
@@ -273,7 +317,11 @@ func NewThreatProfileCreateCmd() *cobra.Command {
 		Long:    createThreatProfileCmdHelp,
 		Example: createThreatProfileCmdExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := NewAPIClient()
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -282,52 +330,23 @@ func NewThreatProfileCreateCmd() *cobra.Command {
 				return err
 			}
 
-			threatProfile := vt.NewObject("threat_profile")
-			threatProfile.SetString("name", viper.GetString("name"))
-
-			// Optional interests
-			interestsData := make(map[string]interface{})
-			if viper.IsSet("targeted-industry") {
-				interestsData["INTEREST_TYPE_TARGETED_INDUSTRY"] = viper.GetStringSlice("targeted-industry")
-			}
-			if viper.IsSet("targeted-region") {
-				interestsData["INTEREST_TYPE_TARGETED_REGION"] = viper.GetStringSlice("targeted-region")
-			}
-			if viper.IsSet("source-region") {
-				interestsData["INTEREST_TYPE_SOURCE_REGION"] = viper.GetStringSlice("source-region")
-			}
-			if viper.IsSet("malware-role") {
-				interestsData["INTEREST_TYPE_MALWARE_ROLE"] = viper.GetStringSlice("malware-role")
-			}
-			if viper.IsSet("actor-motivation") {
-				interestsData["INTEREST_TYPE_ACTOR_MOTIVATION"] = viper.GetStringSlice("actor-motivation")
+			spec, err := threatProfileSpecFromCmd(cmd)
+			if err != nil {
+				return err
 			}
-
-			if len(interestsData) > 0 {
-				threatProfile.Set("interests", interestsData)
+			if spec.Name == "" {
+				return errors.New("a name is required, use --name or set it in the spec file")
 			}
 
-			// Optional recommendation_config
-			recommendationConfigData := make(map[string]interface{})
-			if viper.IsSet("max-recs-per-type") {
-				recommendationConfigData["max_recs_per_type"] = viper.GetInt("max-recs-per-type")
-			}
-			if viper.IsSet("min-categories-matched") {
-				recommendationConfigData["min_categories_matched"] = viper.GetInt("min-categories-matched")
-			}
-			if viper.IsSet("max-days-since-last-seen") {
-				recommendationConfigData["max_days_since_last_seen"] = viper.GetInt("max-days-since-last-seen")
-			}
-			if len(recommendationConfigData) > 0 {
-				threatProfile.Set("recommendation_config", recommendationConfigData)
-			}
+			threatProfile := vt.NewObject("threat_profile")
+			spec.Apply(threatProfile)
 
 			if err := client.PostObject(vt.URL("threat_profiles"), threatProfile); err != nil {
 				return err
 			}
 
-			if viper.GetBool("identifiers-only") {
-				fmt.Printf("%s\n", threatProfile.ID())
+			if opts.IdentifiersOnly {
+				fmt.Fprintf(opts.Stdout, "%s\n", threatProfile.ID())
 			} else {
 				return printer.PrintObject(threatProfile)
 			}
@@ -335,8 +354,7 @@ func NewThreatProfileCreateCmd() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringP("name", "n", "", "Threat Profile's name (required)")
-	_ = cmd.MarkFlagRequired("name")
+	cmd.Flags().StringP("name", "n", "", "Threat Profile's name (required, unless set with --from-file)")
 
 	// Flags for interests
 	cmd.Flags().StringSlice("targeted-industry", []string{}, "List of targeted industries (comma-separated)")
@@ -350,6 +368,7 @@ func NewThreatProfileCreateCmd() *cobra.Command {
 	cmd.Flags().Int("min-categories-matched", 1, "Min matching categories for recommendation (1-5, default 1 if not set by API)")
 	cmd.Flags().Int("max-days-since-last-seen", 180, "Max lookback period in days for recommendations (1-365, default 180 if not set by API)")
 
+	addThreatProfileSpecFlags(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
 
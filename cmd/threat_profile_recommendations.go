@@ -0,0 +1,210 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+// recommendationRelationships returns the names of the relationships of the
+// threat_profile object that contain recommendations. If the relationships
+// are not known yet the generic "recommendations" relationship is used.
+func recommendationRelationships() []string {
+	var names []string
+	for _, r := range objectRelationshipsMap["threat_profile"] {
+		if strings.HasPrefix(r.Name, "recommendations") {
+			names = append(names, r.Name)
+		}
+	}
+	if len(names) == 0 {
+		names = []string{"recommendations"}
+	}
+	return names
+}
+
+// getRecommendations retrieves the objects recommended by a Threat Profile
+// from all the recommendation relationships, up to limit objects from each
+// one, or all of them if limit is 0. Objects appearing in more than one
+// relationship are returned only once.
+func getRecommendations(client *utils.APIClient, profileID string, limit int) ([]utils.Recommendation, error) {
+	profile, err := client.GetObject(vt.URL("threat_profiles/%s", profileID))
+	if err != nil {
+		return nil, err
+	}
+	set := utils.NewRecommendationSet(profile)
+	for _, relationship := range recommendationRelationships() {
+		it, err := client.Iterator(
+			vt.URL("threat_profiles/%s/%s", profileID, relationship),
+			vt.IteratorLimit(limit))
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			set.Add(it.Get())
+		}
+		err = it.Error()
+		it.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return set.Recommendations(), nil
+}
+
+func recommendationsTable(w io.Writer, recs []utils.Recommendation) {
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.AddRow("TYPE", "ID", "NAME", "MATCHED CATEGORIES", "LAST SEEN")
+	for _, rec := range recs {
+		lastSeen := "-"
+		if rec.LastSeen > 0 {
+			lastSeen = humanize.Time(time.Unix(rec.LastSeen, 0))
+		}
+		table.AddRow(
+			rec.Type,
+			rec.ID,
+			rec.Name,
+			strings.Join(rec.MatchedCategories, ", "),
+			lastSeen)
+	}
+	fmt.Fprintln(w, table)
+}
+
+// exportRecommendedIOCs retrieves the IoCs contained in the recommended
+// collections and returns them as a single list without duplicates.
+func exportRecommendedIOCs(client *utils.APIClient, recs []utils.Recommendation, limit int) ([]string, error) {
+	seen := make(map[string]bool)
+	iocs := make([]string, 0)
+	for _, rec := range recs {
+		for _, relationship := range []string{"files", "domains", "ip_addresses", "urls"} {
+			it, err := client.Iterator(
+				vt.URL("collections/%s/%s", rec.ID, relationship),
+				vt.IteratorLimit(limit),
+				// Full URL objects are needed for knowing the actual URL, for
+				// the remaining IoCs the identifier is enough.
+				vt.IteratorDescriptorsOnly(relationship != "urls"))
+			if err != nil {
+				return nil, err
+			}
+			for it.Next() {
+				ioc := it.Get().ID()
+				if relationship == "urls" {
+					if u, err := it.Get().GetString("url"); err == nil && u != "" {
+						ioc = u
+					}
+				}
+				if !seen[ioc] {
+					seen[ioc] = true
+					iocs = append(iocs, ioc)
+				}
+			}
+			err = it.Error()
+			it.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return iocs, nil
+}
+
+var threatProfileRecommendationsCmdHelp = `Get the recommendations for a Threat Profile.
+
+This command retrieves the objects recommended by a Threat Profile (threat
+actors, campaigns, malware families, reports and other collections) and groups
+them by type. For each recommendation it shows the interest categories of the
+profile that it matches and the last time it was seen. Up to --limit objects
+are retrieved from each recommendation relationship, use --all for retrieving
+all of them.
+
+With --export-iocs the IoCs contained in the recommended collections are
+retrieved instead, and printed as a single list without duplicates.`
+
+var threatProfileRecommendationsCmdExample = `  vt threatprofile recommendations <profile_id>
+  vt threatprofile recommendations <profile_id> --human --all
+  vt threatprofile recommendations <profile_id> --export-iocs > iocs.txt`
+
+// NewThreatProfileRecommendationsCmd returns a command for showing the
+// recommendations of a Threat Profile.
+func NewThreatProfileRecommendationsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "recommendations [id]",
+		Short:   "Get the recommendations for a Threat Profile",
+		Long:    threatProfileRecommendationsCmdHelp,
+		Example: threatProfileRecommendationsCmdExample,
+		Args:    cobra.ExactArgs(1),
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := NewAPIClient(cmd)
+			if err != nil {
+				return err
+			}
+			limit := opts.Limit
+			if opts.All {
+				limit = 0
+			}
+			recs, err := getRecommendations(client, args[0], limit)
+			if err != nil {
+				return err
+			}
+			p, err := NewPrinter(cmd)
+			if err != nil {
+				return err
+			}
+			if opts.GetBool("export-iocs") {
+				iocs, err := exportRecommendedIOCs(client, recs, opts.GetInt("ioc-limit"))
+				if err != nil {
+					return err
+				}
+				return p.Print(iocs)
+			}
+			if opts.GetBool("human") {
+				recommendationsTable(opts.Stdout, recs)
+				return nil
+			}
+			grouped := make(map[string]interface{})
+			for _, rec := range recs {
+				l, _ := grouped[rec.Type].([]map[string]interface{})
+				grouped[rec.Type] = append(l, rec.ToMap())
+			}
+			return p.Print(grouped)
+		},
+	}
+
+	cmd.Flags().Bool(
+		"export-iocs", false,
+		"print the IoCs contained in the recommended collections")
+	cmd.Flags().Int(
+		"ioc-limit", 1000,
+		"maximum number of IoCs of each type retrieved from each collection, used with --export-iocs")
+
+	addLimitFlag(cmd.Flags())
+	addAllFlag(cmd.Flags())
+	addHumanFlag(cmd.Flags())
+
+	return cmd
+}
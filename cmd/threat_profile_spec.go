@@ -0,0 +1,68 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// threatProfileSpecFromCmd builds the spec for "threatprofile create" and
+// "threatprofile update" from the --from-file spec file, if any, and the
+// command-line flags, which take precedence over the file. Interest values are
+// sent with the spelling of the accepted ones they match regardless of case,
+// and the result is validated only if --validate is used.
+func threatProfileSpecFromCmd(cmd *cobra.Command) (*utils.ThreatProfileSpec, error) {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+	spec := &utils.ThreatProfileSpec{}
+	if filename := opts.GetString("from-file"); filename != "" {
+		data, err := ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		if spec, err = utils.ParseThreatProfileSpec(data); err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", filename, err)
+		}
+	}
+	spec.MergeFlags(cmd.Flags())
+	accepted, err := utils.LoadInterestValues(opts.GetString("interest-values"), opts)
+	if err != nil {
+		return nil, err
+	}
+	spec.Canonicalize(accepted)
+	if opts.GetBool("validate") {
+		if err := spec.Validate(accepted); err != nil {
+			return nil, err
+		}
+	}
+	return spec, nil
+}
+
+func addThreatProfileSpecFlags(flags *pflag.FlagSet) {
+	flags.String(
+		"from-file", "",
+		"read the Threat Profile from a YAML or JSON file, flags take precedence over the file")
+	flags.String(
+		"interest-values", "",
+		"file or URL with the accepted interest values, overrides the bundled ones")
+	flags.Bool(
+		"validate", false,
+		"check interest values and recommendation settings locally before sending them")
+}
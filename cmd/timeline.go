@@ -0,0 +1,186 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+var hashRe = regexp.MustCompile(`^[0-9a-fA-F]{32}$|^[0-9a-fA-F]{40}$|^[0-9a-fA-F]{64}$`)
+
+// iocPath returns the API path of the object for an IOC, which can be a file
+// hash, a URL, an IP address or a domain.
+func iocPath(ioc string) string {
+	switch {
+	case hashRe.MatchString(ioc):
+		return "files/" + ioc
+	case net.ParseIP(ioc) != nil:
+		return "ip_addresses/" + ioc
+	case strings.Contains(ioc, "://"):
+		// URLs are identified by their base64 encoding.
+		return "urls/" + base64.RawURLEncoding.EncodeToString([]byte(ioc))
+	}
+	return "domains/" + ioc
+}
+
+// iocTimeline returns the events of an IOC, including the ones of its related
+// objects. Relationships that can't be retrieved, because they require
+// privileges the user doesn't have for example, are reported to stderr and
+// skipped.
+func iocTimeline(client *utils.APIClient, opts *utils.Options, ioc string) ([]utils.TimelineEvent, error) {
+	path := iocPath(ioc)
+	obj, err := client.GetObject(vt.URL(path))
+	if err != nil {
+		return nil, err
+	}
+	events := utils.ObjectEvents(ioc, obj)
+	for _, rel := range utils.TimelineRelationships[obj.Type()] {
+		it, err := client.Iterator(vt.URL("%s/%s", path, rel), vt.IteratorLimit(opts.Limit))
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			events = append(events, utils.RelatedEvents(ioc, it.Get())...)
+		}
+		if err := it.Error(); err != nil {
+			fmt.Fprintf(opts.Stderr, "%s: can't retrieve %s: %v\n", ioc, rel, err)
+		}
+		it.Close()
+	}
+	return events, nil
+}
+
+func runTimelineCmd(cmd *cobra.Command, args []string) error {
+	opts, err := utils.CommandOptions(cmd)
+	if err != nil {
+		return err
+	}
+	if opts.GetBool("human") && opts.GetBool("markdown") {
+		return errors.New("--human and --markdown can't be used together")
+	}
+	from, to, err := dateRange(opts)
+	if err != nil {
+		return err
+	}
+
+	client, err := NewAPIClient(cmd)
+	if err != nil {
+		return err
+	}
+	r, err := NewStringReader(cmd, args, nil, nil)
+	if err != nil {
+		return err
+	}
+	var events []utils.TimelineEvent
+	for {
+		ioc, err := r.ReadString()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		e, err := iocTimeline(client, opts, ioc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ioc, err)
+		}
+		events = append(events, e...)
+	}
+	utils.SortTimeline(events)
+	events = utils.FilterTimeline(events, from, to)
+
+	switch {
+	case opts.GetBool("markdown"):
+		return utils.WriteMarkdownTimeline(opts.Stdout, events)
+	case opts.GetBool("human"):
+		table := uitable.New()
+		table.MaxColWidth = 60
+		table.AddRow("DATE", "IOC", "EVENT", "DETAIL")
+		for _, e := range events {
+			table.AddRow(e.Date, e.IOC, e.Event, e.Detail)
+		}
+		fmt.Fprintln(opts.Stdout, table)
+		return nil
+	}
+
+	p, err := NewPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.Print(events)
+}
+
+var timelineCmdHelp = `Show the events of one or more IOCs in chronological order.
+
+This command receives file hashes, URLs, domains or IP addresses and merges the
+dated events found in their VirusTotal reports and in some of their
+relationships in a single timeline:
+
+  files and URLs       first and last submission, last analysis and last
+                       modification, plus every submission and analysis.
+  domains and IPs      creation, WHOIS updates, last analysis, the validity of
+                       the last HTTPS certificate, plus the DNS resolutions,
+                       WHOIS changes and historical SSL certificates.
+
+Relationships are retrieved up to --limit objects each. Some of them require
+a premium API key, relationships that can't be retrieved are reported and
+skipped.
+
+Dates are in UTC. --from and --to limit the timeline to the events between two
+dates, which can be given as YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS, or relative to
+the current time like 12h, 7d or 2w. A --to date without time includes the
+whole day.
+
+If the command receives a single hyphen (-) the IOCs are read from the standard
+input, one per line.`
+
+var timelineCmdExample = `  vt timeline example.com --human
+  vt timeline 44d88612fea8a8f36de82e1278abb02f 1.2.3.4 --from 2024-01-01 --to 2024-03-31
+  cat incident_iocs | vt timeline - --from 30d --markdown > timeline.md
+  vt timeline http://example.com/payload --format csv`
+
+// NewTimelineCmd returns a new instance of the 'timeline' command.
+func NewTimelineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "timeline [ioc]...",
+		Short:   "Show the events of IOCs in chronological order",
+		Long:    timelineCmdHelp,
+		Example: timelineCmdExample,
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runTimelineCmd,
+	}
+
+	cmd.Flags().Bool(
+		"markdown", false,
+		"output a Markdown table")
+	cmd.Flags().IntP(
+		"limit", "n", 40,
+		"maximum number of objects retrieved for each relationship")
+
+	addDateRangeFlags(cmd.Flags())
+	addHumanFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+
+	return cmd
+}
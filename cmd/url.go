@@ -19,7 +19,6 @@ import (
 
 	"github.com/VirusTotal/vt-cli/utils"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var urlCmdHelp = `Get information about one or more URLs.
@@ -31,6 +30,10 @@ command.
 
 If the command receives a single hypen (-) the URLs are read from the standard
 input, one per line.
+
+With --normalize the scheme and host of each URL are converted to lowercase,
+and the port removed if it's the default one, before computing the URL's
+identifier. This way different spellings of the same URL are found.
 `
 
 var urlCmdExample = `  vt url https://www.virustotal.com
@@ -50,12 +53,20 @@ func NewURLCmd() *cobra.Command {
 		Args:    cobra.MinimumNArgs(1),
 
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
 			p, err := NewPrinter(cmd)
 			if err != nil {
 				return err
 			}
-			r := utils.NewMappedStringReader(
-				utils.StringReaderFromCmdArgs(args),
+			r, err := NewStringReader(cmd, args, utils.NormalizeURL, nil)
+			if err != nil {
+				return err
+			}
+			r = utils.NewMappedStringReader(
+				r,
 				func(url string) string {
 					if urlID.MatchString(url) {
 						// The user provided a URL identifier as returned by
@@ -68,7 +79,7 @@ func NewURLCmd() *cobra.Command {
 					return base64.RawURLEncoding.EncodeToString([]byte(url))
 				})
 
-			if viper.GetBool("private") {
+			if opts.GetBool("private") {
 				return p.GetAndPrintObjectsWithFallback(
 					[]string{"urls/%s", "private/urls/%s"},
 					r,
@@ -85,9 +96,12 @@ func NewURLCmd() *cobra.Command {
 	addRelationshipCmds(cmd, "urls", "url", "[url]", true)
 
 	addThreadsFlag(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
 	addPrivateFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addNormalizeFlag(cmd.Flags())
 
 	return cmd
 }
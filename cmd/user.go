@@ -16,7 +16,6 @@ package cmd
 import (
 	"strings"
 
-	"github.com/VirusTotal/vt-cli/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -40,6 +39,10 @@ func NewUserCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			r, err := NewStringReader(cmd, args, nil, nil)
+			if err != nil {
+				return err
+			}
 			return p.GetAndPrintObjects(
 				"users/%s?relationships="+strings.Join([]string{
 					"groups",
@@ -47,7 +50,7 @@ func NewUserCmd() *cobra.Command {
 					"intelligence_quota_group",
 					"monitor_quota_group",
 				}, ","),
-				utils.StringReaderFromCmdArgs(args),
+				r,
 				nil)
 
 		},
@@ -56,6 +59,8 @@ func NewUserCmd() *cobra.Command {
 	addIncludeExcludeFlags(cmd.Flags())
 	addIDOnlyFlag(cmd.Flags())
 	addThreadsFlag(cmd.Flags())
+	addInputFlags(cmd.Flags())
+	addUnorderedFlag(cmd.Flags())
 
 	cmd.AddCommand(NewPrivilegeCmd("user"))
 
@@ -16,12 +16,31 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sync"
 
+	"github.com/VirusTotal/vt-cli/utils"
 	vt "github.com/VirusTotal/vt-go"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+var (
+	hostMu      sync.Mutex
+	currentHost string
+)
+
+// setHost sets the host used by the vt package for building API URLs. The host
+// is global to the process, so it's changed only when a command asks for a
+// different one. Commands running concurrently must use the same host.
+func setHost(host string) {
+	hostMu.Lock()
+	defer hostMu.Unlock()
+	if host != currentHost {
+		vt.SetHost(host)
+		currentHost = host
+	}
+}
+
 // NewVTCommand creates the `vt` command and its nested children.
 func NewVTCommand() *cobra.Command {
 
@@ -31,29 +50,25 @@ func NewVTCommand() *cobra.Command {
 		Long:  `A command-line tool for interacting with VirusTotal.`,
 
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if err := viper.BindPFlags(cmd.PersistentFlags()); err != nil {
-				return err
-			}
-			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			// Settings are read once per invocation and passed down to the
+			// command in its context, see utils.CommandOptions.
+			opts, err := utils.NewOptions(cmd, viper.GetViper())
+			if err != nil {
 				return err
 			}
-			host := viper.GetString("host")
-			if host != "" {
-				vt.SetHost(host)
-			}
-			proxy := viper.GetString("proxy")
-			if proxy != "" {
-				os.Setenv("http_proxy", proxy)
+			if opts.Host != "" {
+				setHost(opts.Host)
 			}
-			if viper.GetBool("verbose") {
+			if opts.Verbose {
 				if configFile := viper.ConfigFileUsed(); configFile != "" {
 					fmt.Fprintf(os.Stderr, "* Config file: %s\n", configFile)
 				}
-				if apiKey := viper.GetString("apikey"); apiKey != "" {
-					fmt.Fprintf(os.Stderr, "* API key: %s\n", apiKey)
+				if opts.APIKey != "" {
+					fmt.Fprintf(os.Stderr, "* API key: %s\n", opts.APIKey)
 				}
-				fmt.Fprintf(os.Stderr, "* API host: %s\n", host)
+				fmt.Fprintf(os.Stderr, "* API host: %s\n", opts.Host)
 			}
+			cmd.SetContext(utils.WithOptions(cmd.Context(), opts))
 			return nil
 		},
 	}
@@ -66,6 +81,9 @@ func NewVTCommand() *cobra.Command {
 	addVerboseFlag(cmd.PersistentFlags())
 
 	cmd.AddCommand(NewAnalysisCmd())
+	cmd.AddCommand(NewAttackCmd())
+	cmd.AddCommand(NewCertCmd())
+	cmd.AddCommand(NewClusterCmd())
 	cmd.AddCommand(NewCollectionCmd())
 	cmd.AddCommand(NewCompletionCmd())
 	cmd.AddCommand(NewDomainCmd())
@@ -73,20 +91,28 @@ func NewVTCommand() *cobra.Command {
 	cmd.AddCommand(NewFileCmd())
 	cmd.AddCommand(NewGenDocCmd())
 	cmd.AddCommand(NewGroupCmd())
+	cmd.AddCommand(NewHashCmd())
 	cmd.AddCommand(NewHuntingCmd())
 	cmd.AddCommand(NewIOCStreamCmd())
 	cmd.AddCommand(NewInitCmd())
 	cmd.AddCommand(NewIPCmd())
 	cmd.AddCommand(NewMetaCmd())
+	cmd.AddCommand(NewPDNSCmd())
 	cmd.AddCommand(NewRetrohuntCmd())
 	cmd.AddCommand(NewScanCmd())
 	cmd.AddCommand(NewSearchCmd())
+	cmd.AddCommand(NewSimilarCmd())
+	cmd.AddCommand(NewTimelineCmd())
 	cmd.AddCommand(NewURLCmd())
 	cmd.AddCommand(NewUserCmd())
 	cmd.AddCommand(NewVersionCmd())
 	cmd.AddCommand(NewMonitorCmd())
 	cmd.AddCommand(NewMonitorPartnerCmd())
 	cmd.AddCommand(NewThreatProfileCmd())
+	cmd.AddCommand(NewActorCmd())
+	cmd.AddCommand(NewCampaignCmd())
+	cmd.AddCommand(NewReportObjectCmd())
+	cmd.AddCommand(NewMalwareFamilyCmd())
 
 	return cmd
 }
@@ -0,0 +1,307 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coordinator runs a function over a stream of items using a fixed
+// number of workers, and delivers the results to one or more sinks.
+//
+// Doers receive typed items and return typed results, instead of strings
+// ready to be printed. How results are presented is up to the sinks: a
+// terminal renderer that shows the progress of each worker, newline-delimited
+// JSON, a journal of processed items, or anything implementing Sink.
+//
+//	c := coordinator.New[string, *vt.Object](5, coordinator.NewNDJSONSink[string, *vt.Object](os.Stdout))
+//	err := c.RunSlice(ctx, coordinator.DoerFunc[string, *vt.Object](
+//		func(ctx context.Context, hash string, s *coordinator.State) (*vt.Object, error) {
+//			s.SetProgress("retrieving %s", hash)
+//			return client.GetObject(vt.URL("files/%s", hash))
+//		}), hashes)
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultProgressInterval is the interval at which sinks implementing
+// ProgressSink are notified about the progress of the workers.
+const DefaultProgressInterval = 250 * time.Millisecond
+
+// State is the state of a worker, it is passed to the doer together with each
+// item. Doers can use it for reporting progress while they process the item,
+// from any goroutine. Readers get a snapshot of the last progress message, so
+// State is safe for concurrent use.
+type State struct {
+	worker   int
+	index    atomic.Int64
+	progress atomic.Pointer[string]
+}
+
+// Worker returns the number of the worker this state belongs to, between 0
+// and the number of threads minus one.
+func (s *State) Worker() int {
+	return s.worker
+}
+
+// Index returns the position in the input of the item being processed by the
+// worker, or -1 if the worker is idle.
+func (s *State) Index() int {
+	return int(s.index.Load())
+}
+
+// SetProgress sets a message describing the progress of the worker. An empty
+// message means that there's nothing to report.
+func (s *State) SetProgress(format string, a ...interface{}) {
+	p := fmt.Sprintf(format, a...)
+	s.progress.Store(&p)
+}
+
+// Progress returns the last progress message set by the worker, or an empty
+// string if the worker is idle.
+func (s *State) Progress() string {
+	if p := s.progress.Load(); p != nil {
+		return *p
+	}
+	return ""
+}
+
+func (s *State) reset(index int) {
+	s.index.Store(int64(index))
+	s.progress.Store(nil)
+}
+
+// Doer processes items of type In producing results of type Out.
+type Doer[In, Out any] interface {
+	Do(ctx context.Context, item In, state *State) (Out, error)
+}
+
+// DoerFunc is an adapter that allows using ordinary functions as doers.
+type DoerFunc[In, Out any] func(ctx context.Context, item In, state *State) (Out, error)
+
+// Do calls f(ctx, item, state).
+func (f DoerFunc[In, Out]) Do(ctx context.Context, item In, state *State) (Out, error) {
+	return f(ctx, item, state)
+}
+
+// Result is the outcome of processing an item.
+type Result[In, Out any] struct {
+	// Index is the position of the item in the input.
+	Index int
+	// Worker is the number of the worker that processed the item.
+	Worker int
+	Input  In
+	Output Out
+	// Err is the error returned by the doer, if any.
+	Err      error
+	Duration time.Duration
+}
+
+// Sink receives the results produced by a coordinator. The methods of a sink
+// are always called from the same goroutine, so implementations don't need
+// to be safe for concurrent use. Results are delivered as soon as they are
+// ready, use Result.Index for knowing their position in the input.
+type Sink[In, Out any] interface {
+	Put(Result[In, Out]) error
+	// Close is called once all the results have been delivered.
+	Close() error
+}
+
+// ProgressSink is implemented by sinks that display the progress of the
+// workers while they run.
+type ProgressSink interface {
+	Progress(states []*State)
+}
+
+// Coordinator coordinates the work of multiple instances of a Doer that run
+// in parallel.
+type Coordinator[In, Out any] struct {
+	Threads          int
+	Sinks            []Sink[In, Out]
+	ProgressInterval time.Duration
+}
+
+// New creates a new coordinator that uses the given number of threads and
+// delivers the results to the given sinks.
+func New[In, Out any](threads int, sinks ...Sink[In, Out]) *Coordinator[In, Out] {
+	return &Coordinator[In, Out]{
+		Threads:          threads,
+		Sinks:            sinks,
+		ProgressInterval: DefaultProgressInterval,
+	}
+}
+
+type indexedItem[In any] struct {
+	index int
+	item  In
+}
+
+// Run calls the doer with every item read from the items channel, until the
+// channel is closed or the context is cancelled. If a sink returns an error
+// no more items are processed and Run returns the error once the ongoing
+// items are finished. Errors returned by the doer don't stop the process,
+// they are delivered to the sinks as part of the result.
+//
+// When Run returns early the items channel is not drained, so whoever is
+// writing to it must stop when ctx is done.
+func (c *Coordinator[In, Out]) Run(ctx context.Context, doer Doer[In, Out], items <-chan In) error {
+	if c.Threads < 1 {
+		return fmt.Errorf("coordinator: invalid number of threads: %d", c.Threads)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	work := make(chan indexedItem[In])
+	results := make(chan Result[In, Out], c.Threads)
+
+	// Number the items as they are read, and stop reading when the context
+	// is cancelled.
+	go func() {
+		defer close(work)
+		index := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-items:
+				if !ok {
+					return
+				}
+				select {
+				case work <- indexedItem[In]{index, item}:
+					index++
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	states := make([]*State, c.Threads)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < c.Threads; i++ {
+		states[i] = &State{worker: i}
+		states[i].reset(-1)
+		wg.Add(1)
+		go func(state *State) {
+			defer wg.Done()
+			for w := range work {
+				state.reset(w.index)
+				start := time.Now()
+				out, err := doer.Do(ctx, w.item, state)
+				results <- Result[In, Out]{
+					Index:    w.index,
+					Worker:   state.worker,
+					Input:    w.item,
+					Output:   out,
+					Err:      err,
+					Duration: time.Since(start),
+				}
+				state.reset(-1)
+			}
+		}(states[i])
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var sinkErr error
+	var tick <-chan time.Time
+	if c.hasProgressSinks() && c.ProgressInterval > 0 {
+		ticker := time.NewTicker(c.ProgressInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+Loop:
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				break Loop
+			}
+			if sinkErr != nil {
+				continue
+			}
+			for _, s := range c.Sinks {
+				if err := s.Put(res); err != nil {
+					sinkErr = err
+					cancel()
+					break
+				}
+			}
+		case <-tick:
+			for _, s := range c.Sinks {
+				if ps, ok := s.(ProgressSink); ok {
+					ps.Progress(states)
+				}
+			}
+		}
+	}
+
+	for _, s := range c.Sinks {
+		if err := s.Close(); err != nil && sinkErr == nil {
+			sinkErr = err
+		}
+	}
+
+	return sinkErr
+}
+
+// RunSlice is like Run, but the items are taken from a slice.
+func (c *Coordinator[In, Out]) RunSlice(ctx context.Context, doer Doer[In, Out], items []In) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan In)
+	go func() {
+		defer close(ch)
+		for _, item := range items {
+			select {
+			case ch <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return c.Run(ctx, doer, ch)
+}
+
+func (c *Coordinator[In, Out]) hasProgressSinks() bool {
+	for _, s := range c.Sinks {
+		if _, ok := s.(ProgressSink); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SliceSink is a sink that keeps all the results in memory, in the order they
+// were delivered.
+type SliceSink[In, Out any] struct {
+	Results []Result[In, Out]
+}
+
+// Put appends the result to the slice.
+func (s *SliceSink[In, Out]) Put(r Result[In, Out]) error {
+	s.Results = append(s.Results, r)
+	return nil
+}
+
+// Close does nothing.
+func (s *SliceSink[In, Out]) Close() error {
+	return nil
+}
@@ -0,0 +1,199 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// lengthDoer returns the length of the input strings, and fails for empty
+// strings.
+var lengthDoer = DoerFunc[string, int](func(_ context.Context, s string, _ *State) (int, error) {
+	if s == "" {
+		return 0, errors.New("empty string")
+	}
+	return len(s), nil
+})
+
+func TestRunSlice(t *testing.T) {
+	sink := &SliceSink[string, int]{}
+	c := New[string, int](3, sink)
+	items := []string{"a", "bb", "", "dddd", "eeeee"}
+
+	assert.NoError(t, c.RunSlice(context.Background(), lengthDoer, items))
+	assert.Len(t, sink.Results, len(items))
+
+	sort.Slice(sink.Results, func(i, j int) bool {
+		return sink.Results[i].Index < sink.Results[j].Index
+	})
+	for i, r := range sink.Results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, items[i], r.Input)
+		assert.True(t, r.Worker >= 0 && r.Worker < 3)
+		if items[i] == "" {
+			assert.EqualError(t, r.Err, "empty string")
+		} else {
+			assert.NoError(t, r.Err)
+			assert.Equal(t, len(items[i]), r.Output)
+		}
+	}
+}
+
+func TestRunInvalidThreads(t *testing.T) {
+	c := New[string, int](0)
+	assert.Error(t, c.RunSlice(context.Background(), lengthDoer, []string{"a"}))
+}
+
+type failingSink struct {
+	puts int
+}
+
+func (s *failingSink) Put(Result[string, int]) error {
+	s.puts++
+	return errors.New("disk full")
+}
+
+func (s *failingSink) Close() error {
+	return nil
+}
+
+func TestRunSinkError(t *testing.T) {
+	items := make([]string, 1000)
+	for i := range items {
+		items[i] = "item"
+	}
+	sink := &failingSink{}
+	c := New[string, int](2, sink)
+	err := c.RunSlice(context.Background(), lengthDoer, items)
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, 1, sink.puts)
+}
+
+type progressSink struct {
+	SliceSink[string, int]
+	mu       sync.Mutex
+	progress []string
+}
+
+func (s *progressSink) Progress(states []*State) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range states {
+		if p := st.Progress(); p != "" {
+			s.progress = append(s.progress, p)
+		}
+	}
+}
+
+func TestRunProgress(t *testing.T) {
+	sink := &progressSink{}
+	c := New[string, int](1, sink)
+	c.ProgressInterval = time.Millisecond
+
+	doer := DoerFunc[string, int](func(_ context.Context, s string, st *State) (int, error) {
+		st.SetProgress("working on %s", s)
+		time.Sleep(20 * time.Millisecond)
+		return len(s), nil
+	})
+
+	assert.NoError(t, c.RunSlice(context.Background(), doer, []string{"foo"}))
+	assert.Contains(t, sink.progress, "working on foo")
+}
+
+func TestNDJSONSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewNDJSONSink[string, int](&buf)
+	assert.NoError(t, sink.Put(Result[string, int]{Index: 0, Input: "foo", Output: 3}))
+	assert.NoError(t, sink.Put(Result[string, int]{Index: 1, Input: "", Err: errors.New("empty string")}))
+	assert.Equal(t,
+		`{"index":0,"input":"foo","output":3,"duration_ms":0}`+"\n"+
+			`{"index":1,"input":"","error":"empty string","duration_ms":0}`+"\n",
+		buf.String())
+}
+
+func TestJournal(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJournalSink[string, int](&buf, strings.ToUpper)
+	c := New[string, int](2, sink)
+	assert.NoError(t, c.RunSlice(context.Background(), lengthDoer, []string{"a", "", "c"}))
+
+	done, err := LoadJournal(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"A": true, "C": true}, done)
+}
+
+func TestLoadJournalLastRecordWins(t *testing.T) {
+	journal := `{"time":"2026-01-01T00:00:00Z","item":"a","ok":true}
+{"time":"2026-01-01T00:00:01Z","item":"b","ok":false,"error":"boom"}
+
+{"time":"2026-01-01T00:00:02Z","item":"b","ok":true}
+{"time":"2026-01-01T00:00:03Z","item":"a","ok":false,"error":"boom"}
+`
+	done, err := LoadJournal(strings.NewReader(journal))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"b": true}, done)
+}
+
+func TestStateConcurrentProgress(t *testing.T) {
+	s := &State{}
+	s.reset(-1)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				s.SetProgress("writer %d: %d", i, j)
+			}
+		}(i)
+	}
+	for j := 0; j < 1000; j++ {
+		p := s.Progress()
+		assert.True(t, p == "" || strings.HasPrefix(p, "writer "))
+	}
+	wg.Wait()
+	assert.Regexp(t, `^writer \d: 999$`, s.Progress())
+	assert.Equal(t, -1, s.Index())
+}
+
+func TestTerminalSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewTerminalSink(func(r Result[string, int]) string {
+		return fmt.Sprintf("%s=%d", r.Input, r.Output)
+	}, true)
+	sink.Out = &buf
+
+	busy, idle := &State{}, &State{}
+	busy.SetProgress("foo 50%%")
+	sink.Progress([]*State{busy, idle})
+	assert.NoError(t, sink.Put(Result[string, int]{Input: "foo", Output: 3}))
+	assert.NoError(t, sink.Close())
+	assert.Equal(t, "foo 50%\x1b[0K\n\x1b[1Ffoo=3\x1b[0K\n", buf.String())
+
+	buf.Reset()
+	sink.Interactive = false
+	sink.Progress([]*State{busy})
+	assert.NoError(t, sink.Put(Result[string, int]{Input: "bar", Output: 3}))
+	assert.Equal(t, "bar=3\n", buf.String())
+}
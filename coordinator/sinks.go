@@ -0,0 +1,204 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coordinator
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/plusvic/go-ansi"
+)
+
+// Escape sequences used by TerminalSink. They are interpreted by terminals in
+// Unix, and converted to console API calls in Windows by the writer returned
+// by ansi.NewAnsiStdout.
+const (
+	eraseToEndOfLine = "\x1b[0K"
+	cursorUpFormat   = "\x1b[%dF"
+)
+
+// TerminalSink prints results as text. When Interactive is true the progress
+// of the busy workers is displayed below the results and updated in place, so
+// it must be false when the output is not a terminal.
+type TerminalSink[In, Out any] struct {
+	// Out is where the results are printed, the standard output by default.
+	Out io.Writer
+	// Render returns the text printed for a result.
+	Render func(Result[In, Out]) string
+	// Interactive enables the progress display.
+	Interactive bool
+	// Spinner, if not nil, is animated while waiting for results.
+	Spinner *spinner.Spinner
+}
+
+// NewTerminalSink returns a TerminalSink that prints to the standard output
+// and uses render for converting the results to text.
+func NewTerminalSink[In, Out any](render func(Result[In, Out]) string, interactive bool) *TerminalSink[In, Out] {
+	return &TerminalSink[In, Out]{
+		Out:         ansi.NewAnsiStdout(),
+		Render:      render,
+		Interactive: interactive,
+	}
+}
+
+// Put prints a result.
+func (s *TerminalSink[In, Out]) Put(r Result[In, Out]) error {
+	text := s.Render(r)
+	if !s.Interactive {
+		_, err := fmt.Fprintln(s.Out, text)
+		return err
+	}
+	if s.Spinner != nil {
+		s.Spinner.Stop()
+	}
+	// Overwrite whatever progress was printed in this line.
+	_, err := fmt.Fprint(s.Out, text, eraseToEndOfLine, "\n")
+	return err
+}
+
+// Progress prints the progress of the workers and moves the cursor back to
+// where it was, so the next result overwrites them. States are read only once,
+// the progress displayed is a snapshot of the progress at that moment.
+func (s *TerminalSink[In, Out]) Progress(states []*State) {
+	if !s.Interactive {
+		return
+	}
+	if s.Spinner != nil {
+		s.Spinner.Start()
+	}
+	lines := 0
+	for _, st := range states {
+		if p := st.Progress(); p != "" {
+			fmt.Fprint(s.Out, p, eraseToEndOfLine, "\n")
+			lines++
+		}
+	}
+	if lines > 0 {
+		fmt.Fprintf(s.Out, cursorUpFormat, lines)
+	}
+}
+
+// Close stops the spinner, if any.
+func (s *TerminalSink[In, Out]) Close() error {
+	if s.Spinner != nil {
+		s.Spinner.Stop()
+	}
+	return nil
+}
+
+type ndjsonRecord struct {
+	Index    int         `json:"index"`
+	Input    interface{} `json:"input"`
+	Output   interface{} `json:"output,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	Duration float64     `json:"duration_ms"`
+}
+
+// NDJSONSink writes each result as a JSON object in a separate line. Inputs
+// and outputs are encoded with encoding/json.
+type NDJSONSink[In, Out any] struct {
+	enc *json.Encoder
+}
+
+// NewNDJSONSink returns a sink that writes results to w.
+func NewNDJSONSink[In, Out any](w io.Writer) *NDJSONSink[In, Out] {
+	return &NDJSONSink[In, Out]{enc: json.NewEncoder(w)}
+}
+
+// Put writes a result.
+func (s *NDJSONSink[In, Out]) Put(r Result[In, Out]) error {
+	rec := ndjsonRecord{
+		Index:    r.Index,
+		Input:    r.Input,
+		Duration: float64(r.Duration.Microseconds()) / 1000,
+	}
+	if r.Err != nil {
+		rec.Error = r.Err.Error()
+	} else {
+		rec.Output = r.Output
+	}
+	return s.enc.Encode(rec)
+}
+
+// Close does nothing, the underlying writer is not closed.
+func (s *NDJSONSink[In, Out]) Close() error {
+	return nil
+}
+
+type journalRecord struct {
+	Time  string `json:"time"`
+	Item  string `json:"item"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// JournalSink keeps a record of the processed items, one JSON object per line.
+// Items are identified by the string returned by the key function. The
+// journal can be read with LoadJournal for resuming an interrupted run
+// without processing the same items again.
+type JournalSink[In, Out any] struct {
+	key func(In) string
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// NewJournalSink returns a sink that appends records to w.
+func NewJournalSink[In, Out any](w io.Writer, key func(In) string) *JournalSink[In, Out] {
+	return &JournalSink[In, Out]{key: key, enc: json.NewEncoder(w), now: time.Now}
+}
+
+// Put writes a record for the result.
+func (s *JournalSink[In, Out]) Put(r Result[In, Out]) error {
+	rec := journalRecord{
+		Time: s.now().UTC().Format(time.RFC3339),
+		Item: s.key(r.Input),
+		OK:   r.Err == nil,
+	}
+	if r.Err != nil {
+		rec.Error = r.Err.Error()
+	}
+	return s.enc.Encode(rec)
+}
+
+// Close does nothing, the underlying writer is not closed.
+func (s *JournalSink[In, Out]) Close() error {
+	return nil
+}
+
+// LoadJournal reads a journal written by JournalSink and returns the items
+// that were processed successfully. If the same item appears more than once
+// the last record wins.
+func LoadJournal(r io.Reader) (map[string]bool, error) {
+	done := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec journalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, err
+		}
+		if rec.OK {
+			done[rec.Item] = true
+		} else {
+			delete(done, rec.Item)
+		}
+	}
+	return done, scanner.Err()
+}
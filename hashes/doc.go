@@ -0,0 +1,21 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hashes computes locally the hashes that VirusTotal computes for
+// files, which can be used for searching files similar to a local one
+// without uploading it: the ssdeep and TLSH fuzzy hashes, and the import hash
+// and Authenticode hash of Portable Executable files. It also compares fuzzy hashes, with the same
+// results as the reference implementations.
+//
+// The vhash of a file can't be computed locally, its algorithm is not public.
+package hashes
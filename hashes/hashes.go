@@ -0,0 +1,53 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashes
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Fingerprints are all the hashes that can be computed locally for a file.
+// The hashes that are not defined for the file are empty: TLSH for short
+// files, and imphash and authentihash for files that are not PE files.
+type Fingerprints struct {
+	MD5          string
+	SHA1         string
+	SHA256       string
+	SSDeep       string
+	TLSH         string
+	Imphash      string
+	Authentihash string
+}
+
+// Compute returns the fingerprints of data.
+func Compute(data []byte) *Fingerprints {
+	md5Sum := md5.Sum(data)
+	sha1Sum := sha1.Sum(data)
+	sha256Sum := sha256.Sum256(data)
+	f := &Fingerprints{
+		MD5:    hex.EncodeToString(md5Sum[:]),
+		SHA1:   hex.EncodeToString(sha1Sum[:]),
+		SHA256: hex.EncodeToString(sha256Sum[:]),
+		SSDeep: SSDeep(data),
+	}
+	f.TLSH, _ = TLSH(data)
+	if _, err := parsePE(data); err == nil {
+		f.Imphash, _ = Imphash(data)
+		f.Authentihash, _ = Authentihash(data)
+	}
+	return f
+}
@@ -0,0 +1,36 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompute(t *testing.T) {
+	f := Compute([]byte("The quick brown fox jumps over the lazy dog"))
+	assert.Equal(t, "9e107d9d372bb6826bd81d3542a419d6", f.MD5)
+	assert.Equal(t, "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12", f.SHA1)
+	assert.Equal(t, "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592", f.SHA256)
+	assert.Equal(t, "3:FJKKIUKact:FHIGi", f.SSDeep)
+	// Too short for TLSH, and not a PE file.
+	assert.Empty(t, f.TLSH)
+	assert.Empty(t, f.Imphash)
+	assert.Empty(t, f.Authentihash)
+
+	pe := Compute(buildPE([]testImport{{dll: "kernel32.dll", names: []string{"ExitProcess"}}}))
+	assert.NotEmpty(t, pe.Imphash)
+	assert.NotEmpty(t, pe.Authentihash)
+}
@@ -0,0 +1,250 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashes
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"debug/pe"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotPE is returned by functions that only work with Portable Executable
+// files when they receive something else.
+var ErrNotPE = errors.New("not a PE file")
+
+// peFile is a parsed Portable Executable file together with its raw data.
+type peFile struct {
+	*pe.File
+	data []byte
+}
+
+func parsePE(data []byte) (*peFile, error) {
+	if len(data) < 2 || data[0] != 'M' || data[1] != 'Z' {
+		return nil, ErrNotPE
+	}
+	f, err := pe.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotPE, err)
+	}
+	if f.OptionalHeader == nil {
+		return nil, ErrNotPE
+	}
+	return &peFile{File: f, data: data}, nil
+}
+
+// dataDirectory returns an entry of the optional header's data directory,
+// like pe.IMAGE_DIRECTORY_ENTRY_IMPORT.
+func (f *peFile) dataDirectory(entry int) pe.DataDirectory {
+	var dirs []pe.DataDirectory
+	switch h := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dirs = h.DataDirectory[:min(h.NumberOfRvaAndSizes, uint32(len(h.DataDirectory)))]
+	case *pe.OptionalHeader64:
+		dirs = h.DataDirectory[:min(h.NumberOfRvaAndSizes, uint32(len(h.DataDirectory)))]
+	}
+	if entry >= len(dirs) {
+		return pe.DataDirectory{}
+	}
+	return dirs[entry]
+}
+
+// at returns the data at a relative virtual address, up to the end of the
+// section that contains it.
+func (f *peFile) at(rva uint32) ([]byte, error) {
+	for _, s := range f.Sections {
+		size := max(s.VirtualSize, s.Size)
+		if rva < s.VirtualAddress || rva >= s.VirtualAddress+size {
+			continue
+		}
+		start := uint64(s.Offset) + uint64(rva-s.VirtualAddress)
+		end := min(uint64(s.Offset)+uint64(s.Size), uint64(len(f.data)))
+		if start >= end {
+			return nil, fmt.Errorf("RVA %#x is not backed by file data", rva)
+		}
+		return f.data[start:end], nil
+	}
+	return nil, fmt.Errorf("RVA %#x is outside of any section", rva)
+}
+
+func (f *peFile) cString(rva uint32) (string, error) {
+	b, err := f.at(rva)
+	if err != nil {
+		return "", err
+	}
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b), nil
+}
+
+// peImport is a function imported by a PE file. Functions imported by
+// ordinal don't have a name.
+type peImport struct {
+	dll     string
+	name    string
+	ordinal uint16
+}
+
+// imports returns the functions imported by the file, in the order in which
+// they appear in the import directory.
+func (f *peFile) imports() ([]peImport, error) {
+	dir := f.dataDirectory(pe.IMAGE_DIRECTORY_ENTRY_IMPORT)
+	if dir.VirtualAddress == 0 {
+		return nil, nil
+	}
+	descriptors, err := f.at(dir.VirtualAddress)
+	if err != nil {
+		return nil, err
+	}
+	thunkSize := uint32(4)
+	if _, ok := f.OptionalHeader.(*pe.OptionalHeader64); ok {
+		thunkSize = 8
+	}
+	var imports []peImport
+	// Each descriptor has 20 bytes: OriginalFirstThunk, TimeDateStamp,
+	// ForwarderChain, Name and FirstThunk. The last one is all zeros.
+	for ; len(descriptors) >= 20; descriptors = descriptors[20:] {
+		lookup := binary.LittleEndian.Uint32(descriptors[0:])
+		nameRVA := binary.LittleEndian.Uint32(descriptors[12:])
+		firstThunk := binary.LittleEndian.Uint32(descriptors[16:])
+		if lookup == 0 && nameRVA == 0 && firstThunk == 0 {
+			break
+		}
+		dll, err := f.cString(nameRVA)
+		if err != nil {
+			return nil, err
+		}
+		if lookup == 0 {
+			lookup = firstThunk
+		}
+		thunks, err := f.at(lookup)
+		if err != nil {
+			return nil, err
+		}
+		for ; uint32(len(thunks)) >= thunkSize; thunks = thunks[thunkSize:] {
+			var thunk uint64
+			var byOrdinal bool
+			if thunkSize == 8 {
+				thunk = binary.LittleEndian.Uint64(thunks)
+				byOrdinal = thunk&(1<<63) != 0
+			} else {
+				thunk = uint64(binary.LittleEndian.Uint32(thunks))
+				byOrdinal = thunk&(1<<31) != 0
+			}
+			if thunk == 0 {
+				break
+			}
+			if byOrdinal {
+				imports = append(imports, peImport{dll: dll, ordinal: uint16(thunk)})
+				continue
+			}
+			// The name is preceded by a 2-byte hint.
+			name, err := f.cString(uint32(thunk&0x7fffffff) + 2)
+			if err != nil {
+				return nil, err
+			}
+			imports = append(imports, peImport{dll: dll, name: name})
+		}
+	}
+	return imports, nil
+}
+
+// Imphash returns the import hash of a PE file, as computed by the pefile
+// Python module and VirusTotal: the MD5 of the list of imported functions.
+// Functions imported by ordinal are named ordN, except those of ws2_32,
+// wsock32 and oleaut32, which are resolved to their names like pefile does.
+func Imphash(data []byte) (string, error) {
+	f, err := parsePE(data)
+	if err != nil {
+		return "", err
+	}
+	imports, err := f.imports()
+	if err != nil {
+		return "", err
+	}
+	if len(imports) == 0 {
+		return "", errors.New("the PE file doesn't import any function")
+	}
+	entries := make([]string, len(imports))
+	for i, imp := range imports {
+		dll := strings.ToLower(imp.dll)
+		for _, ext := range []string{".dll", ".ocx", ".sys"} {
+			if strings.HasSuffix(dll, ext) {
+				dll = strings.TrimSuffix(dll, ext)
+				break
+			}
+		}
+		name := imp.name
+		if name == "" {
+			// Ordinals are resolved only if the DLL name has the extension.
+			name = ordinalNames[strings.ToLower(imp.dll)][imp.ordinal]
+		}
+		if name == "" {
+			name = fmt.Sprintf("ord%d", imp.ordinal)
+		}
+		name = strings.ToLower(name)
+		entries[i] = dll + "." + name
+	}
+	sum := md5.Sum([]byte(strings.Join(entries, ",")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Authentihash returns the Authenticode hash of a PE file, which is the
+// SHA-256 of the file excluding its checksum and its signature, so it doesn't
+// change when the file is signed. The excluded regions are the checksum field
+// of the optional header, the certificate table entry of the data directory
+// and the certificate table itself.
+func Authentihash(data []byte) (string, error) {
+	f, err := parsePE(data)
+	if err != nil {
+		return "", err
+	}
+	optOffset := int(binary.LittleEndian.Uint32(data[0x3c:])) + 4 + 20
+	var dirOffset int
+	var dirs uint32
+	switch h := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dirOffset, dirs = optOffset+96, h.NumberOfRvaAndSizes
+	case *pe.OptionalHeader64:
+		dirOffset, dirs = optOffset+112, h.NumberOfRvaAndSizes
+	}
+	type region struct{ start, end int }
+	excluded := []region{{optOffset + 64, optOffset + 68}}
+	if dirs > pe.IMAGE_DIRECTORY_ENTRY_SECURITY {
+		entry := dirOffset + 8*pe.IMAGE_DIRECTORY_ENTRY_SECURITY
+		excluded = append(excluded, region{entry, entry + 8})
+		// The address of the certificate table is a file offset, not an RVA.
+		dir := f.dataDirectory(pe.IMAGE_DIRECTORY_ENTRY_SECURITY)
+		if start := int(dir.VirtualAddress); dir.Size > 0 && start > entry+8 && start < len(data) {
+			excluded = append(excluded, region{start, min(start+int(dir.Size), len(data))})
+		}
+	}
+	h := sha256.New()
+	pos := 0
+	for _, r := range excluded {
+		if r.end > len(data) {
+			return "", fmt.Errorf("%w: truncated headers", ErrNotPE)
+		}
+		h.Write(data[pos:r.start])
+		pos = r.end
+	}
+	h.Write(data[pos:])
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,187 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashes
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testImport struct {
+	dll   string
+	names []string
+	// ordinals are imported after names.
+	ordinals []uint16
+}
+
+const (
+	testSectionRVA    = 0x1000
+	testSectionOffset = 0x200
+)
+
+// buildPE returns a minimal 32-bit PE file with a single section that
+// contains the import directory for the given imports.
+func buildPE(imports []testImport) []byte {
+	// Section layout: descriptors, then for each DLL its name, its lookup
+	// table and the hint/name entries of its functions.
+	var section bytes.Buffer
+	descriptors := make([]byte, 20*(len(imports)+1))
+	section.Write(descriptors)
+	le := binary.LittleEndian
+	for i, imp := range imports {
+		nameRVA := testSectionRVA + uint32(section.Len())
+		section.WriteString(imp.dll + "\x00")
+		lookupRVA := testSectionRVA + uint32(section.Len())
+		thunks := make([]byte, 4*(len(imp.names)+len(imp.ordinals)+1))
+		section.Write(thunks)
+		for j, name := range imp.names {
+			le.PutUint32(thunks[4*j:], testSectionRVA+uint32(section.Len()))
+			section.Write([]byte{0, 0})
+			section.WriteString(name + "\x00")
+		}
+		for j, ord := range imp.ordinals {
+			le.PutUint32(thunks[4*(len(imp.names)+j):], 1<<31|uint32(ord))
+		}
+		b := section.Bytes()
+		copy(b[lookupRVA-testSectionRVA:], thunks)
+		le.PutUint32(b[20*i:], lookupRVA)
+		le.PutUint32(b[20*i+12:], nameRVA)
+		le.PutUint32(b[20*i+16:], lookupRVA)
+	}
+	for section.Len()%0x200 != 0 {
+		section.WriteByte(0)
+	}
+
+	var f bytes.Buffer
+	dos := make([]byte, 0x40)
+	copy(dos, "MZ")
+	le.PutUint32(dos[0x3c:], 0x40)
+	f.Write(dos)
+	f.WriteString("PE\x00\x00")
+	// COFF header.
+	binary.Write(&f, le, []uint16{0x14c, 1})
+	binary.Write(&f, le, []uint32{0, 0, 0})
+	binary.Write(&f, le, []uint16{224, 0x102})
+	// Optional header, with the import directory at index 1 of the data
+	// directory, which starts at offset 96.
+	opt := make([]byte, 224)
+	le.PutUint16(opt[0:], 0x10b)
+	le.PutUint32(opt[32:], 0x1000)
+	le.PutUint32(opt[36:], 0x200)
+	le.PutUint32(opt[56:], testSectionRVA+uint32(section.Len()))
+	le.PutUint32(opt[60:], testSectionOffset)
+	le.PutUint32(opt[92:], 16)
+	le.PutUint32(opt[104:], testSectionRVA)
+	le.PutUint32(opt[108:], uint32(len(descriptors)))
+	f.Write(opt)
+	// Section table.
+	sh := make([]byte, 40)
+	copy(sh, ".idata")
+	le.PutUint32(sh[8:], uint32(section.Len()))
+	le.PutUint32(sh[12:], testSectionRVA)
+	le.PutUint32(sh[16:], uint32(section.Len()))
+	le.PutUint32(sh[20:], testSectionOffset)
+	f.Write(sh)
+	for f.Len() < testSectionOffset {
+		f.WriteByte(0)
+	}
+	f.Write(section.Bytes())
+	return f.Bytes()
+}
+
+func TestImphash(t *testing.T) {
+	data := buildPE([]testImport{
+		{dll: "KERNEL32.dll", names: []string{"CreateFileA", "ExitProcess"}},
+		{dll: "user32.DLL", names: []string{"MessageBoxA"}, ordinals: []uint16{5}},
+		{dll: "ntoskrnl.exe", names: []string{"IoCreateDevice"}},
+	})
+	h, err := Imphash(data)
+	assert.NoError(t, err)
+	sum := md5.Sum([]byte("kernel32.createfilea,kernel32.exitprocess," +
+		"user32.messageboxa,user32.ord5,ntoskrnl.exe.iocreatedevice"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), h)
+
+	_, err = Imphash([]byte("not a PE file"))
+	assert.ErrorIs(t, err, ErrNotPE)
+}
+
+func TestImphashOrdinals(t *testing.T) {
+	data := buildPE([]testImport{
+		{dll: "WS2_32.dll", ordinals: []uint16{115, 500, 200}},
+		{dll: "wsock32.DLL", ordinals: []uint16{3}},
+		{dll: "OLEAUT32.dll", ordinals: []uint16{2, 6}},
+		{dll: "ws2_32", ordinals: []uint16{1}},
+	})
+	h, err := Imphash(data)
+	assert.NoError(t, err)
+	sum := md5.Sum([]byte("ws2_32.wsastartup,ws2_32.wep,ws2_32.ord200," +
+		"wsock32.closesocket,oleaut32.sysallocstring,oleaut32.sysfreestring," +
+		"ws2_32.ord1"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), h)
+}
+
+func TestImphashKnownAnswer(t *testing.T) {
+	// A hello world program built with MinGW, from the tests of the debug/pe
+	// package of the Go distribution.
+	data, err := os.ReadFile("testdata/gcc-386-mingw-no-symbols-exec")
+	assert.NoError(t, err)
+	h, err := Imphash(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "bde39f2e060d2d61d7ad85402a68ebda", h)
+}
+
+func TestAuthentihash(t *testing.T) {
+	data := buildPE([]testImport{{dll: "kernel32.dll", names: []string{"ExitProcess"}}})
+	h, err := Authentihash(data)
+	assert.NoError(t, err)
+	// The checksum is at offset 64 of the optional header, which starts
+	// after the DOS header, the signature and the COFF header. The
+	// certificate table entry is the fifth one of the data directory, which
+	// starts at offset 96.
+	const checksum = 0x40 + 4 + 20 + 64
+	const certEntry = 0x40 + 4 + 20 + 96 + 8*4
+	var hashed []byte
+	hashed = append(hashed, data[:checksum]...)
+	hashed = append(hashed, data[checksum+4:certEntry]...)
+	hashed = append(hashed, data[certEntry+8:]...)
+	sum := sha256.Sum256(hashed)
+	assert.Equal(t, hex.EncodeToString(sum[:]), h)
+
+	// Signing the file changes the checksum, the certificate table entry of
+	// the data directory and appends the certificate table.
+	signed := append([]byte{}, data...)
+	binary.LittleEndian.PutUint32(signed[checksum:], 0x1234)
+	binary.LittleEndian.PutUint32(signed[certEntry:], uint32(len(signed)))
+	binary.LittleEndian.PutUint32(signed[certEntry+4:], 16)
+	signed = append(signed, bytes.Repeat([]byte{0xcc}, 16)...)
+	signedHash, err := Authentihash(signed)
+	assert.NoError(t, err)
+	assert.Equal(t, h, signedHash)
+
+	// Anything else is hashed.
+	signed[testSectionOffset] ^= 1
+	signedHash, err = Authentihash(signed)
+	assert.NoError(t, err)
+	assert.NotEqual(t, h, signedHash)
+
+	_, err = Authentihash([]byte("MZ"))
+	assert.ErrorIs(t, err, ErrNotPE)
+}
@@ -0,0 +1,218 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	ssdeepRollingWindow = 7
+	ssdeepMinBlockSize  = 3
+	ssdeepLength        = 64
+	ssdeepHashPrime     = 0x01000193
+	ssdeepHashInit      = 0x28021967
+	ssdeepB64           = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+)
+
+type rollingHash struct {
+	window     [ssdeepRollingWindow]byte
+	h1, h2, h3 uint32
+	n          int
+}
+
+func (r *rollingHash) roll(c byte) uint32 {
+	r.h2 -= r.h1
+	r.h2 += ssdeepRollingWindow * uint32(c)
+	r.h1 += uint32(c)
+	r.h1 -= uint32(r.window[r.n])
+	r.window[r.n] = c
+	r.n = (r.n + 1) % ssdeepRollingWindow
+	r.h3 <<= 5
+	r.h3 ^= uint32(c)
+	return r.h1 + r.h2 + r.h3
+}
+
+// ssdeepPart is one of the two parts of a ssdeep digest. Once the part has
+// max characters, the last one covers the remainder of the input.
+type ssdeepPart struct {
+	max    int
+	digest []byte
+	h      uint32
+	// tail is the last character, which is replaced while the part is full.
+	tail byte
+}
+
+func (p *ssdeepPart) reset() {
+	if len(p.digest) < p.max-1 {
+		p.digest = append(p.digest, ssdeepB64[p.h%64])
+		p.h = ssdeepHashInit
+	} else {
+		p.tail = ssdeepB64[p.h%64]
+	}
+}
+
+func (p *ssdeepPart) String(sum uint32) string {
+	s := string(p.digest)
+	if sum != 0 {
+		s += string(ssdeepB64[p.h%64])
+	} else if p.tail != 0 {
+		s += string(p.tail)
+	}
+	return s
+}
+
+// ssdeepDigest returns the two parts of the digest of data for a block size,
+// and the number of complete characters in the first one.
+func ssdeepDigest(data []byte, bs uint32) (string, string, int) {
+	p1 := &ssdeepPart{max: ssdeepLength, h: ssdeepHashInit}
+	p2 := &ssdeepPart{max: ssdeepLength / 2, h: ssdeepHashInit}
+	var r rollingHash
+	var sum uint32
+	for _, c := range data {
+		sum = r.roll(c)
+		p1.h = p1.h*ssdeepHashPrime ^ uint32(c)
+		p2.h = p2.h*ssdeepHashPrime ^ uint32(c)
+		if sum%bs == bs-1 {
+			p1.reset()
+		}
+		if sum%(2*bs) == 2*bs-1 {
+			p2.reset()
+		}
+	}
+	return p1.String(sum), p2.String(sum), len(p1.digest)
+}
+
+// SSDeep returns the ssdeep (context triggered piecewise hash) of data, as
+// computed by the ssdeep tool.
+func SSDeep(data []byte) string {
+	bs := uint32(ssdeepMinBlockSize)
+	for uint64(bs)*ssdeepLength < uint64(len(data)) {
+		bs *= 2
+	}
+	for {
+		p1, p2, n := ssdeepDigest(data, bs)
+		if bs > ssdeepMinBlockSize && n < ssdeepLength/2 {
+			bs /= 2
+			continue
+		}
+		return fmt.Sprintf("%d:%s:%s", bs, p1, p2)
+	}
+}
+
+// eliminateSequences shortens sequences of more than three identical
+// characters to three.
+func eliminateSequences(s string) string {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if i >= 3 && c == s[i-1] && c == s[i-2] && c == s[i-3] {
+			continue
+		}
+		out = append(out, c)
+	}
+	return string(out)
+}
+
+func hasCommonSubstring(s1, s2 string) bool {
+	if len(s1) < ssdeepRollingWindow || len(s2) < ssdeepRollingWindow {
+		return false
+	}
+	for i := 0; i+ssdeepRollingWindow <= len(s1); i++ {
+		if strings.Contains(s2, s1[i:i+ssdeepRollingWindow]) {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance returns the edit distance between s1 and s2, where insertions
+// and deletions cost 1 and replacements cost 2.
+func editDistance(s1, s2 string) int {
+	prev := make([]int, len(s2)+1)
+	cur := make([]int, len(s2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s1); i++ {
+		cur[0] = i
+		for j := 1; j <= len(s2); j++ {
+			cost := 2
+			if s1[i-1] == s2[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(s2)]
+}
+
+func scoreStrings(s1, s2 string, bs uint64) int {
+	if len(s1) > ssdeepLength || len(s2) > ssdeepLength || !hasCommonSubstring(s1, s2) {
+		return 0
+	}
+	score := editDistance(s1, s2) * ssdeepLength / (len(s1) + len(s2))
+	score = 100 * score / ssdeepLength
+	if score >= 100 {
+		return 0
+	}
+	score = 100 - score
+	if bs >= (99+ssdeepRollingWindow)/ssdeepRollingWindow*ssdeepMinBlockSize {
+		return score
+	}
+	if limit := int(bs/ssdeepMinBlockSize) * min(len(s1), len(s2)); score > limit {
+		score = limit
+	}
+	return score
+}
+
+func parseSSDeep(h string) (uint64, string, string, error) {
+	parts := strings.SplitN(h, ":", 3)
+	if len(parts) != 3 {
+		return 0, "", "", fmt.Errorf("invalid ssdeep hash %q", h)
+	}
+	bs, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("invalid ssdeep hash %q", h)
+	}
+	return bs, eliminateSequences(parts[1]), eliminateSequences(parts[2]), nil
+}
+
+// CompareSSDeep returns a score between 0 and 100 that indicates how similar
+// are the inputs that produced two ssdeep hashes, 100 meaning identical. The
+// score is the same returned by the ssdeep tool.
+func CompareSSDeep(h1, h2 string) (int, error) {
+	bs1, a1, b1, err := parseSSDeep(h1)
+	if err != nil {
+		return 0, err
+	}
+	bs2, a2, b2, err := parseSSDeep(h2)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case bs1 == bs2 && a1 == a2:
+		return 100, nil
+	case bs1 == bs2:
+		return max(scoreStrings(a1, a2, bs1), scoreStrings(b1, b2, bs1*2)), nil
+	case bs1*2 == bs2:
+		return scoreStrings(b1, a2, bs2), nil
+	case bs2*2 == bs1:
+		return scoreStrings(a1, b2, bs1), nil
+	}
+	return 0, nil
+}
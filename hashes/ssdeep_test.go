@@ -0,0 +1,101 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashes
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomData returns n pseudo-random bytes, always the same for a seed.
+func randomData(seed int64, n int) []byte {
+	b := make([]byte, n)
+	rand.New(rand.NewSource(seed)).Read(b)
+	return b
+}
+
+// modified returns a copy of data with a few bytes changed.
+func modified(data []byte) []byte {
+	b := append([]byte(nil), data...)
+	for i := len(b) / 3; i < len(b)/3+20; i++ {
+		b[i] ^= 0xff
+	}
+	return b
+}
+
+// eicar is the EICAR anti-virus test file, whose hashes are widely published.
+var eicar = []byte(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`)
+
+// TestSSDeepKnownAnswers checks hashes computed by the reference ssdeep
+// implementation.
+func TestSSDeepKnownAnswers(t *testing.T) {
+	tests := []struct {
+		data []byte
+		hash string
+	}{
+		{[]byte(""), "3::"},
+		{[]byte("The quick brown fox jumps over the lazy dog"), "3:FJKKIUKact:FHIGi"},
+		{eicar, "3:a+JraNvsgzsVqSwHq9:tJuOgzsko"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.hash, SSDeep(test.data))
+	}
+}
+
+var ssdeepRe = regexp.MustCompile(`^\d+:[A-Za-z0-9+/]{1,64}:[A-Za-z0-9+/]{0,32}$`)
+
+func TestSSDeep(t *testing.T) {
+	assert.Equal(t, "3::", SSDeep(nil))
+
+	data := randomData(1, 100000)
+	h := SSDeep(data)
+	assert.Regexp(t, ssdeepRe, h)
+	assert.Equal(t, h, SSDeep(data))
+
+	score, err := CompareSSDeep(h, h)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, score)
+
+	score, err = CompareSSDeep(h, SSDeep(modified(data)))
+	assert.NoError(t, err)
+	assert.Greater(t, score, 80)
+	assert.Less(t, score, 100)
+
+	score, err = CompareSSDeep(h, SSDeep(randomData(2, 100000)))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, score)
+
+	// Block sizes that are too different can't be compared.
+	score, err = CompareSSDeep(h, SSDeep(data[:1000]))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, score)
+
+	_, err = CompareSSDeep(h, "foo")
+	assert.Error(t, err)
+}
+
+func TestEliminateSequences(t *testing.T) {
+	assert.Equal(t, "AAAbCCC", eliminateSequences("AAAAAAbCCCC"))
+	assert.Equal(t, "ABC", eliminateSequences("ABC"))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("abc", "abc"))
+	assert.Equal(t, 1, editDistance("abc", "abcd"))
+	assert.Equal(t, 2, editDistance("abc", "abd"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
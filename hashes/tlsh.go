@@ -0,0 +1,215 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashes
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ErrTLSHUndefined is returned by TLSH for inputs that are too short or don't
+// have enough variety for computing a meaningful hash.
+var ErrTLSHUndefined = errors.New("TLSH is not defined for this input")
+
+const (
+	tlshBuckets    = 128
+	tlshCodeSize   = tlshBuckets / 4
+	tlshMinDataLen = 50
+)
+
+// tlshPearson is the Pearson hashing table used by TLSH.
+var tlshPearson = [256]byte{
+	1, 87, 49, 12, 176, 178, 102, 166, 121, 193, 6, 84, 249, 230, 44, 163,
+	14, 197, 213, 181, 161, 85, 218, 80, 64, 239, 24, 226, 236, 142, 38, 200,
+	110, 177, 104, 103, 141, 253, 255, 50, 77, 101, 81, 18, 45, 96, 31, 222,
+	25, 107, 190, 70, 86, 237, 240, 34, 72, 242, 20, 214, 244, 227, 149, 235,
+	97, 234, 57, 22, 60, 250, 82, 175, 208, 5, 127, 199, 111, 62, 135, 248,
+	174, 169, 211, 58, 66, 154, 106, 195, 245, 171, 17, 187, 182, 179, 0, 243,
+	132, 56, 148, 75, 128, 133, 158, 100, 130, 126, 91, 13, 153, 246, 216, 219,
+	119, 68, 223, 78, 83, 88, 201, 99, 122, 11, 92, 32, 136, 114, 52, 10,
+	138, 30, 48, 183, 156, 35, 61, 26, 143, 74, 251, 94, 129, 162, 63, 152,
+	170, 7, 115, 167, 241, 206, 3, 150, 55, 59, 151, 220, 90, 53, 23, 131,
+	125, 173, 15, 238, 79, 95, 89, 16, 105, 137, 225, 224, 217, 160, 37, 123,
+	118, 73, 2, 157, 46, 116, 9, 145, 134, 228, 207, 212, 202, 215, 69, 229,
+	27, 188, 67, 124, 168, 252, 42, 4, 29, 108, 21, 247, 19, 205, 39, 203,
+	233, 40, 186, 147, 198, 192, 155, 33, 164, 191, 98, 204, 165, 180, 117, 76,
+	140, 36, 210, 172, 41, 54, 159, 8, 185, 232, 113, 196, 231, 47, 146, 120,
+	51, 65, 28, 144, 254, 221, 93, 189, 194, 139, 112, 43, 71, 109, 184, 209,
+}
+
+func tlshMapping(salt, i, j, k byte) byte {
+	h := tlshPearson[salt]
+	h = tlshPearson[h^i]
+	h = tlshPearson[h^j]
+	return tlshPearson[h^k]
+}
+
+// tlshLength returns the logarithmic representation of the input length used
+// in TLSH hashes.
+func tlshLength(n int) byte {
+	l := math.Log(float64(n))
+	var i float64
+	switch {
+	case n <= 656:
+		i = math.Floor(l / 0.4054651)
+	case n <= 3199:
+		i = math.Floor(l/0.26236426 - 8.72777)
+	default:
+		i = math.Floor(l/0.095310180 - 62.5472)
+	}
+	return byte(int(i) & 0xff)
+}
+
+func swapNibbles(b byte) byte {
+	return b<<4 | b>>4
+}
+
+// TLSH returns the Trend Micro Locality Sensitive Hash of data, in the format
+// used by version 4 of the reference implementation, which is the one used by
+// VirusTotal: "T1" followed by 70 hex digits.
+func TLSH(data []byte) (string, error) {
+	if len(data) < tlshMinDataLen {
+		return "", ErrTLSHUndefined
+	}
+	var buckets [256]uint32
+	var checksum byte
+	for i := 4; i < len(data); i++ {
+		a, b, c, d, e := data[i], data[i-1], data[i-2], data[i-3], data[i-4]
+		checksum = tlshMapping(0, a, b, checksum)
+		buckets[tlshMapping(2, a, b, c)]++
+		buckets[tlshMapping(3, a, b, d)]++
+		buckets[tlshMapping(5, a, c, d)]++
+		buckets[tlshMapping(7, a, c, e)]++
+		buckets[tlshMapping(11, a, b, e)]++
+		buckets[tlshMapping(13, a, d, e)]++
+	}
+
+	sorted := make([]uint32, tlshBuckets)
+	copy(sorted, buckets[:tlshBuckets])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	q1, q2, q3 := sorted[tlshBuckets/4-1], sorted[tlshBuckets/2-1], sorted[tlshBuckets*3/4-1]
+	nonZero := 0
+	for _, n := range buckets[:tlshBuckets] {
+		if n > 0 {
+			nonZero++
+		}
+	}
+	if q3 == 0 || nonZero <= tlshBuckets/2 {
+		return "", ErrTLSHUndefined
+	}
+
+	var code [tlshCodeSize]byte
+	for i := range code {
+		var h byte
+		for j := 0; j < 4; j++ {
+			switch k := buckets[4*i+j]; {
+			case q3 < k:
+				h += 3 << (j * 2)
+			case q2 < k:
+				h += 2 << (j * 2)
+			case q1 < k:
+				h += 1 << (j * 2)
+			}
+		}
+		// The code is written in reverse order.
+		code[tlshCodeSize-1-i] = h
+	}
+	q1Ratio := byte(q1 * 100 / q3 % 16)
+	q2Ratio := byte(q2 * 100 / q3 % 16)
+
+	b := []byte{swapNibbles(checksum), swapNibbles(tlshLength(len(data))), q1Ratio<<4 | q2Ratio}
+	b = append(b, code[:]...)
+	return "T1" + strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+type tlshDigest struct {
+	checksum, length, q1Ratio, q2Ratio byte
+	code                               []byte
+}
+
+func parseTLSH(h string) (*tlshDigest, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.ToUpper(h), "T1"))
+	if err != nil || len(b) != 3+tlshCodeSize {
+		return nil, fmt.Errorf("invalid TLSH hash %q", h)
+	}
+	return &tlshDigest{
+		checksum: swapNibbles(b[0]),
+		length:   swapNibbles(b[1]),
+		q1Ratio:  b[2] >> 4,
+		q2Ratio:  b[2] & 0xf,
+		code:     b[3:],
+	}, nil
+}
+
+func modDiff(x, y, r int) int {
+	d := x - y
+	if d < 0 {
+		d = -d
+	}
+	return min(d, r-d)
+}
+
+// DiffTLSH returns the distance between two TLSH hashes. Zero means that the
+// inputs are identical or almost identical, and it grows as they differ,
+// with distances below 100 usually meaning that they are similar. The
+// distance is the same returned by the reference implementation.
+func DiffTLSH(h1, h2 string) (int, error) {
+	t1, err := parseTLSH(h1)
+	if err != nil {
+		return 0, err
+	}
+	t2, err := parseTLSH(h2)
+	if err != nil {
+		return 0, err
+	}
+	diff := 0
+	switch d := modDiff(int(t1.length), int(t2.length), 256); {
+	case d <= 1:
+		diff += d
+	default:
+		diff += d * 12
+	}
+	for _, d := range []int{
+		modDiff(int(t1.q1Ratio), int(t2.q1Ratio), 16),
+		modDiff(int(t1.q2Ratio), int(t2.q2Ratio), 16),
+	} {
+		if d <= 1 {
+			diff += d
+		} else {
+			diff += (d - 1) * 12
+		}
+	}
+	if t1.checksum != t2.checksum {
+		diff++
+	}
+	for i := range t1.code {
+		x, y := t1.code[i], t2.code[i]
+		for j := 0; j < 4; j++ {
+			d := int(x>>(2*j)&3) - int(y>>(2*j)&3)
+			switch {
+			case d == 3 || d == -3:
+				diff += 6
+			case d < 0:
+				diff -= d
+			default:
+				diff += d
+			}
+		}
+	}
+	return diff, nil
+}
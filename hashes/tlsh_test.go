@@ -0,0 +1,77 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSHPearson(t *testing.T) {
+	// The table must be a permutation.
+	seen := make(map[byte]bool)
+	for _, b := range tlshPearson {
+		seen[b] = true
+	}
+	assert.Len(t, seen, 256)
+}
+
+// TestTLSHKnownAnswers checks a hash computed by the reference TLSH
+// implementation for an input a bit above the minimum length.
+func TestTLSHKnownAnswers(t *testing.T) {
+	h, err := TLSH(eicar)
+	assert.NoError(t, err)
+	assert.Equal(t, "T141A022003B0EEE2BA20B00200032E8B00808020E2CE00A3820A020B8C83308803EC228", h)
+}
+
+func TestTLSH(t *testing.T) {
+	_, err := TLSH([]byte("too short"))
+	assert.ErrorIs(t, err, ErrTLSHUndefined)
+	_, err = TLSH(bytes.Repeat([]byte("a"), 1000))
+	assert.ErrorIs(t, err, ErrTLSHUndefined)
+
+	data := randomData(1, 100000)
+	h, err := TLSH(data)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^T1[0-9A-F]{70}$`, h)
+
+	d, err := DiffTLSH(h, h)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, d)
+
+	similar, err := TLSH(modified(data))
+	assert.NoError(t, err)
+	d, err = DiffTLSH(h, similar)
+	assert.NoError(t, err)
+	assert.Less(t, d, 50)
+
+	other, err := TLSH(randomData(2, 5000))
+	assert.NoError(t, err)
+	d2, err := DiffTLSH(other, h)
+	assert.NoError(t, err)
+	assert.Greater(t, d2, d)
+	d3, _ := DiffTLSH(h, other)
+	assert.Equal(t, d2, d3)
+
+	_, err = DiffTLSH(h, "T1ABC")
+	assert.Error(t, err)
+}
+
+func TestTLSHLength(t *testing.T) {
+	assert.Equal(t, byte(9), tlshLength(50))
+	assert.Equal(t, byte(17), tlshLength(1000))
+	assert.Equal(t, byte(58), tlshLength(100000))
+}
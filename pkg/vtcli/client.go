@@ -0,0 +1,347 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// DefaultThreads is the number of objects retrieved in parallel by Batch when
+// the client is created without the WithThreads option.
+const DefaultThreads = 5
+
+// ErrMissingAPIKey is returned by NewClient when the API key is empty.
+var ErrMissingAPIKey = errors.New("an API key is required")
+
+type clientConfig struct {
+	agent           string
+	threads         int
+	searchBatchSize int
+	httpClient      *http.Client
+}
+
+// ClientOption represents an option for creating a Client.
+type ClientOption func(*clientConfig)
+
+// WithAgent sets the User-Agent used in the requests to the API.
+func WithAgent(agent string) ClientOption {
+	return func(c *clientConfig) { c.agent = agent }
+}
+
+// WithThreads sets the maximum number of objects retrieved in parallel by
+// Batch.
+func WithThreads(threads int) ClientOption {
+	return func(c *clientConfig) { c.threads = threads }
+}
+
+// WithSearchBatchSize sets the maximum number of hashes included in each of
+// the searches done by BatchFiles.
+func WithSearchBatchSize(n int) ClientOption {
+	return func(c *clientConfig) { c.searchBatchSize = n }
+}
+
+// WithHTTPClient sets the HTTP client used for sending requests to the API.
+// This includes the requests that the vt package doesn't support and are sent
+// by the Client itself, like URL scans with parameters.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *clientConfig) { c.httpClient = httpClient }
+}
+
+// Client is a VirusTotal API client that adds single and batch lookups to
+// the client provided by the vt package.
+type Client struct {
+	*vt.Client
+	threads         int
+	searchBatchSize int
+	httpClient      *http.Client
+}
+
+// NewClient returns a client that uses the given API key.
+func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
+	if apiKey == "" {
+		return nil, ErrMissingAPIKey
+	}
+	cfg := &clientConfig{agent: "vt-cli"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	var vtOpts []vt.ClientOption
+	if cfg.httpClient != nil {
+		vtOpts = append(vtOpts, vt.WithHTTPClient(cfg.httpClient))
+	}
+	c := vt.NewClient(apiKey, vtOpts...)
+	c.Agent = cfg.agent
+	return WrapClient(c, opts...), nil
+}
+
+// WrapClient returns a Client that uses an existing vt.Client. The WithAgent
+// option has no effect, and WithHTTPClient affects only the requests sent by
+// the Client itself, it should be the same HTTP client used by c.
+func WrapClient(c *vt.Client, opts ...ClientOption) *Client {
+	cfg := &clientConfig{
+		threads:         DefaultThreads,
+		searchBatchSize: DefaultSearchBatchSize,
+		httpClient:      http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.threads < 1 {
+		cfg.threads = 1
+	}
+	if cfg.searchBatchSize < 1 {
+		cfg.searchBatchSize = 1
+	}
+	return &Client{
+		Client:          c,
+		threads:         cfg.threads,
+		searchBatchSize: cfg.searchBatchSize,
+		httpClient:      cfg.httpClient,
+	}
+}
+
+// IsNotFound returns true if err is the error returned by the API for objects
+// that don't exist.
+func IsNotFound(err error) bool {
+	var apiErr vt.Error
+	return errors.As(err, &apiErr) && apiErr.Code == "NotFoundError"
+}
+
+// Lookup retrieves an object. The endpoint must contain a %s placeholder that
+// is replaced with the object's identifier, like in "files/%s".
+func (c *Client) Lookup(endpoint, id string) (*vt.Object, error) {
+	return c.LookupWithFallback([]string{endpoint}, id)
+}
+
+// LookupWithFallback retrieves an object trying the endpoints in order, until
+// one of them returns the object. The next endpoint is tried only when the
+// object is not found, any other error is returned immediately.
+func (c *Client) LookupWithFallback(endpoints []string, id string) (*vt.Object, error) {
+	var err error
+	for _, endpoint := range endpoints {
+		var obj *vt.Object
+		obj, err = c.GetObject(vt.URL(endpoint, id))
+		if err == nil {
+			return obj, nil
+		}
+		if !IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// BatchResult is the result of looking up one of the identifiers passed to
+// Batch.
+type BatchResult struct {
+	// Index is the position of the identifier in the input.
+	Index  int
+	ID     string
+	Object *vt.Object
+	Err    error
+}
+
+// batchQueue is a priority queue of results, ordered by index.
+type batchQueue []BatchResult
+
+func (q batchQueue) Len() int            { return len(q) }
+func (q batchQueue) Less(i, j int) bool  { return q[i].Index < q[j].Index }
+func (q batchQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *batchQueue) Push(x interface{}) { *q = append(*q, x.(BatchResult)) }
+func (q *batchQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[0 : n-1]
+	return x
+}
+
+// DefaultBatchWindow is the maximum number of identifiers that Batch and
+// BatchStream have in flight at any time, unless changed with WithWindow.
+const DefaultBatchWindow = 1000
+
+type batchConfig struct {
+	window    int
+	unordered bool
+}
+
+// BatchOption represents an option for Batch and BatchStream.
+type BatchOption func(*batchConfig)
+
+// WithWindow sets the maximum number of identifiers in flight, counting both
+// the ones being looked up and the ones whose results are waiting to be sent
+// because some previous identifier is still being looked up. Once the window
+// is full no more identifiers are read from the input until the oldest result
+// is received by the caller, so memory usage is bounded regardless of the
+// size of the input.
+func WithWindow(n int) BatchOption {
+	return func(c *batchConfig) { c.window = n }
+}
+
+// Unordered makes Batch and BatchStream send results as soon as they are
+// available, instead of in the same order the identifiers have in the input.
+func Unordered() BatchOption {
+	return func(c *batchConfig) { c.unordered = true }
+}
+
+// Batch looks up multiple objects in parallel, using LookupWithFallback for
+// each of them. Results are sent to the returned channel in the same order
+// the identifiers have in ids, and the channel is closed after the last one.
+// If ctx is cancelled no more lookups are started, and the channel is closed
+// once the ongoing ones finish. See BatchStream for details.
+func (c *Client) Batch(ctx context.Context, endpoints []string, ids []string, opts ...BatchOption) <-chan BatchResult {
+	return c.BatchStream(ctx, endpoints, sliceChan(ctx, ids), opts...)
+}
+
+// sliceChan returns a channel that receives the strings in s, and is closed
+// after the last one or when ctx is cancelled.
+func sliceChan(ctx context.Context, s []string) <-chan string {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, item := range s {
+			select {
+			case ch <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// BatchStream is like Batch, but the identifiers are read from a channel as
+// they are needed. Reading stops when the channel is closed or ctx is
+// cancelled. At most the number of identifiers set with WithWindow are in
+// flight at any time, which means that a caller that is slow receiving the
+// results slows down the reading of the identifiers too.
+func (c *Client) BatchStream(ctx context.Context, endpoints []string, ids <-chan string, opts ...BatchOption) <-chan BatchResult {
+	lookup := func(id string) (*vt.Object, error) {
+		return c.LookupWithFallback(endpoints, id)
+	}
+	return batchStream(ctx, ids, c.threads, lookup, newBatchConfig(opts))
+}
+
+// newBatchConfig returns the configuration resulting from applying opts to
+// the defaults.
+func newBatchConfig(opts []BatchOption) *batchConfig {
+	cfg := &batchConfig{window: DefaultBatchWindow}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.window < 1 {
+		cfg.window = 1
+	}
+	return cfg
+}
+
+// batchStream calls lookup for the identifiers read from ids, using the given
+// number of goroutines, and sends the results to the returned channel.
+func batchStream(ctx context.Context, ids <-chan string, threads int, lookup func(string) (*vt.Object, error), cfg *batchConfig) <-chan BatchResult {
+	work := make(chan BatchResult)
+	results := make(chan BatchResult)
+	// A slot is taken for every identifier read, and released when its
+	// result is sent to the caller.
+	slots := make(chan struct{}, cfg.window)
+
+	go func() {
+		defer close(work)
+		for i := 0; ; i++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case id, ok := <-ids:
+				if !ok {
+					return
+				}
+				work <- BatchResult{Index: i, ID: id}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range work {
+				r.Object, r.Err = lookup(r.ID)
+				results <- r
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return deliver(results, slots, cfg.unordered)
+}
+
+// deliver sends the results read from the results channel to the returned
+// channel, releasing a slot after each one. Unless unordered is true results
+// are sent ordered by index, see reorder.
+func deliver(results <-chan BatchResult, slots <-chan struct{}, unordered bool) <-chan BatchResult {
+	out := make(chan BatchResult)
+	release := func() { <-slots }
+	if unordered {
+		go func() {
+			defer close(out)
+			for r := range results {
+				out <- r
+				release()
+			}
+		}()
+	} else {
+		go reorder(results, out, release)
+	}
+	return out
+}
+
+// reorder reads results in any order from the in channel, and sends them to
+// the out channel ordered by index. Results are kept in a queue until all the
+// previous ones have been sent. The out channel is closed once in is closed.
+// release is called after sending each result.
+func reorder(in <-chan BatchResult, out chan<- BatchResult, release func()) {
+	defer close(out)
+	send := func(r BatchResult) {
+		out <- r
+		release()
+	}
+	q := &batchQueue{}
+	next := 0
+	for r := range in {
+		heap.Push(q, r)
+		for q.Len() > 0 && (*q)[0].Index == next {
+			send(heap.Pop(q).(BatchResult))
+			next++
+		}
+	}
+	// If the context was cancelled there can be gaps, send what's left.
+	for q.Len() > 0 {
+		send(heap.Pop(q).(BatchResult))
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestServer returns a server that knows about files whose identifier
+// starts with "file" and URLs whose identifier starts with "url". Files are
+// served with a delay that is shorter for later files, so that responses
+// arrive out of order.
+func newTestServer(t *testing.T) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v3/"), "/")
+		collection, id := parts[0], parts[1]
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasPrefix(id, strings.TrimSuffix(collection, "s")) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": {"code": "NotFoundError", "message": "not found"}}`)
+			return
+		}
+		var n int
+		fmt.Sscanf(id, strings.TrimSuffix(collection, "s")+"%d", &n)
+		time.Sleep(time.Duration(10-n) * time.Millisecond)
+		fmt.Fprintf(w, `{"data": {"type": %q, "id": %q, "attributes": {}}}`,
+			strings.TrimSuffix(collection, "s"), id)
+	}))
+	vt.SetHost(ts.URL)
+	t.Cleanup(func() {
+		ts.Close()
+		vt.SetHost("https://www.virustotal.com")
+	})
+	return ts
+}
+
+func TestNewClientMissingAPIKey(t *testing.T) {
+	_, err := NewClient("")
+	assert.ErrorIs(t, err, ErrMissingAPIKey)
+}
+
+func TestLookupWithFallback(t *testing.T) {
+	newTestServer(t)
+	c, err := NewClient("apikey")
+	assert.NoError(t, err)
+
+	obj, err := c.LookupWithFallback([]string{"files/%s", "urls/%s"}, "url1")
+	assert.NoError(t, err)
+	assert.Equal(t, "url", obj.Type())
+
+	_, err = c.Lookup("files/%s", "url1")
+	assert.True(t, IsNotFound(err))
+}
+
+func TestBatch(t *testing.T) {
+	newTestServer(t)
+	c, err := NewClient("apikey", WithThreads(4))
+	assert.NoError(t, err)
+
+	ids := []string{"file1", "file2", "unknown", "file4", "url5", "file6", "file7"}
+	var results []BatchResult
+	for r := range c.Batch(context.Background(), []string{"files/%s", "urls/%s"}, ids) {
+		results = append(results, r)
+	}
+
+	assert.Len(t, results, len(ids))
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, ids[i], r.ID)
+		if r.ID == "unknown" {
+			assert.True(t, IsNotFound(r.Err))
+		} else {
+			assert.NoError(t, r.Err)
+			assert.Equal(t, r.ID, r.Object.ID())
+		}
+	}
+}
+
+func TestBatchStreamWindow(t *testing.T) {
+	const window = 10
+	var read int32
+	ids := make(chan string)
+	go func() {
+		defer close(ids)
+		for i := 0; i < 1000; i++ {
+			ids <- fmt.Sprintf("id%d", i)
+			atomic.AddInt32(&read, 1)
+		}
+	}()
+	lookup := func(id string) (*vt.Object, error) {
+		return vt.NewObjectWithID("file", id), nil
+	}
+	results := batchStream(context.Background(), ids, 4, lookup, &batchConfig{window: window})
+
+	// While the caller doesn't receive results no more than window
+	// identifiers are read from the input.
+	time.Sleep(50 * time.Millisecond)
+	assert.LessOrEqual(t, atomic.LoadInt32(&read), int32(window))
+
+	n := 0
+	for r := range results {
+		assert.Equal(t, n, r.Index)
+		assert.Equal(t, fmt.Sprintf("id%d", n), r.Object.ID())
+		n++
+	}
+	assert.Equal(t, 1000, n)
+}
+
+func TestBatchStreamUnordered(t *testing.T) {
+	ids := make(chan string, 3)
+	ids <- "slow"
+	ids <- "fast1"
+	ids <- "fast2"
+	close(ids)
+	lookup := func(id string) (*vt.Object, error) {
+		if id == "slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		return vt.NewObjectWithID("file", id), nil
+	}
+
+	var ordered []string
+	for r := range batchStream(context.Background(), ids, 3, lookup, &batchConfig{window: 3}) {
+		ordered = append(ordered, r.ID)
+	}
+	assert.Equal(t, []string{"slow", "fast1", "fast2"}, ordered)
+
+	ids = make(chan string, 3)
+	ids <- "slow"
+	ids <- "fast1"
+	ids <- "fast2"
+	close(ids)
+	var unordered []string
+	for r := range batchStream(context.Background(), ids, 3, lookup, &batchConfig{window: 3, unordered: true}) {
+		unordered = append(unordered, r.ID)
+	}
+	assert.ElementsMatch(t, []string{"slow", "fast1", "fast2"}, unordered)
+	assert.Equal(t, "slow", unordered[2])
+}
+
+func BenchmarkBatchStream(b *testing.B) {
+	lookup := func(id string) (*vt.Object, error) {
+		return nil, nil
+	}
+	for _, n := range []int{10000, 1000000} {
+		for _, unordered := range []bool{false, true} {
+			name := fmt.Sprintf("n=%d/unordered=%v", n, unordered)
+			b.Run(name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					ids := make(chan string)
+					go func() {
+						defer close(ids)
+						for j := 0; j < n; j++ {
+							ids <- "id"
+						}
+					}()
+					cfg := &batchConfig{window: DefaultBatchWindow, unordered: unordered}
+					for range batchStream(context.Background(), ids, DefaultThreads, lookup, cfg) {
+					}
+				}
+			})
+		}
+	}
+}
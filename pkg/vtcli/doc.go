@@ -0,0 +1,45 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vtcli exposes the functionality of the VirusTotal CLI for programs
+// that want to embed it: looking up objects one by one or in batches, and
+// printing them in any of the output formats supported by the vt tool, with
+// the same filtering rules used by its --include and --exclude flags.
+//
+// Unlike the internal packages used by the vt tool, this package doesn't read
+// any global configuration. Everything is set with options when creating a
+// Client or a Printer, and output goes to the io.Writer provided by the
+// caller:
+//
+//	client, err := vtcli.NewClient(apiKey, vtcli.WithThreads(10))
+//	if err != nil {
+//		return err
+//	}
+//	p := vtcli.NewPrinter(os.Stdout,
+//		vtcli.WithFormat(vtcli.FormatJSON),
+//		vtcli.WithFilter([]string{"last_analysis_stats"}, nil))
+//	for r := range client.Batch(ctx, []string{"files/%s"}, hashes) {
+//		if r.Err != nil {
+//			log.Print(r.Err)
+//			continue
+//		}
+//		p.PrintObject(r.Object)
+//	}
+//
+// # Compatibility
+//
+// This package follows semantic versioning together with the rest of the
+// vt-cli module: within a major version exported identifiers are not removed
+// and their signatures and documented behavior don't change. New functions,
+// options and formats may be added in minor versions.
+package vtcli
@@ -0,0 +1,122 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/VirusTotal/vt-cli/csv"
+	"github.com/VirusTotal/vt-cli/siem"
+	"github.com/VirusTotal/vt-cli/yaml"
+	glob "github.com/gobwas/glob"
+)
+
+// Format is an output format.
+type Format string
+
+// Output formats supported by Printer and NewEncoder.
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatECS  Format = "ecs"
+	FormatCEF  Format = "cef"
+	FormatLEEF Format = "leef"
+)
+
+// ErrUnknownFormat is returned when an output format is not supported.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Formats returns all the supported output formats.
+func Formats() []Format {
+	return []Format{FormatYAML, FormatJSON, FormatCSV, FormatECS, FormatCEF, FormatLEEF}
+}
+
+// ParseFormat returns the format with the given name, which is case
+// insensitive. An empty name means YAML, the default format.
+func ParseFormat(name string) (Format, error) {
+	if name == "" {
+		return FormatYAML, nil
+	}
+	f := Format(strings.ToLower(name))
+	for _, known := range Formats() {
+		if f == known {
+			return f, nil
+		}
+	}
+	return "", ErrUnknownFormat
+}
+
+// IsStreaming returns true if the format writes one line per object. Objects
+// written in these formats don't need to be accumulated, they can be written
+// as soon as they are received.
+func (f Format) IsStreaming() bool {
+	switch f {
+	case FormatECS, FormatCEF, FormatLEEF:
+		return true
+	}
+	return false
+}
+
+// Encoder writes values to an output stream in some format.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// defaultDateKeys are the keys whose values are Unix timestamps, which are
+// annotated with a human-readable date in YAML output.
+var defaultDateKeys = []string{"last_login", "user_since", "date", "*_date"}
+
+// NewEncoder returns an encoder that writes to w in the given format. Options
+// that don't apply to encoders, like filters, are ignored.
+func NewEncoder(w io.Writer, f Format, opts ...PrinterOption) (Encoder, error) {
+	cfg := newPrinterConfig(opts)
+	return cfg.encoder(w, f)
+}
+
+func (c *printerConfig) encoder(w io.Writer, f Format) (Encoder, error) {
+	switch f {
+	case "", FormatYAML:
+		dateKeys := make([]glob.Glob, len(c.dateKeys))
+		for i, k := range c.dateKeys {
+			dateKeys[i] = glob.MustCompile(k)
+		}
+		return yaml.NewEncoder(w,
+			yaml.EncoderColors(c.colors),
+			yaml.EncoderDateKeys(dateKeys)), nil
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc, nil
+	case FormatCSV:
+		return csv.NewEncoder(w), nil
+	case FormatECS:
+		return siem.NewECSEncoder(w, c.siemOptions()...), nil
+	case FormatCEF:
+		return siem.NewCEFEncoder(w, c.siemOptions()...), nil
+	case FormatLEEF:
+		return siem.NewLEEFEncoder(w, c.siemOptions()...), nil
+	}
+	return nil, ErrUnknownFormat
+}
+
+func (c *printerConfig) siemOptions() []siem.EncoderOption {
+	if c.product == "" {
+		return nil
+	}
+	return []siem.EncoderOption{siem.EncoderProduct(c.product, c.version)}
+}
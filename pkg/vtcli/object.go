@@ -0,0 +1,61 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// ObjectToMap function that returns the attributes for an object as a map.
+// Keys are attribute names and values are the attribute's value. Two special
+// keys _id and _type are also included in the map with object's identifier
+// and type respectively.
+//
+// Related objects included in the object's relationships are replaced by their
+// identifiers.
+func ObjectToMap(obj *vt.Object) map[string]interface{} {
+	m := make(map[string]interface{})
+	m["_id"] = obj.ID()
+	m["_type"] = obj.Type()
+
+	contextAttributes := make(map[string]interface{})
+	for _, attr := range obj.ContextAttributes() {
+		contextAttributes[attr], _ = obj.GetContext(attr)
+	}
+	if len(contextAttributes) > 0 {
+		m["_context_attributes"] = contextAttributes
+	}
+
+	for _, attr := range obj.Attributes() {
+		m[attr], _ = obj.Get(attr)
+	}
+	for _, name := range obj.Relationships() {
+		r, _ := obj.GetRelationship(name)
+		relatedObjs := r.Objects()
+		if r.IsOneToOne() {
+			if len(relatedObjs) > 0 {
+				m[name] = relatedObjs[0].ID()
+			} else {
+				m[name] = nil
+			}
+		} else {
+			l := make([]string, 0)
+			for _, obj := range relatedObjs {
+				l = append(l, obj.ID())
+			}
+			m[name] = l
+		}
+	}
+	return m
+}
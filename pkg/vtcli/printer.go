@@ -0,0 +1,183 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	"io"
+
+	"github.com/VirusTotal/vt-cli/yaml"
+	vt "github.com/VirusTotal/vt-go"
+)
+
+type printerConfig struct {
+	format          Format
+	include         []string
+	exclude         []string
+	identifiersOnly bool
+	colors          *yaml.Colors
+	dateKeys        []string
+	product         string
+	version         string
+}
+
+func newPrinterConfig(opts []PrinterOption) *printerConfig {
+	c := &printerConfig{format: FormatYAML, dateKeys: defaultDateKeys}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// PrinterOption represents an option for creating a Printer or an Encoder.
+type PrinterOption func(*printerConfig)
+
+// WithFormat sets the output format. The default is FormatYAML.
+func WithFormat(f Format) PrinterOption {
+	return func(c *printerConfig) { c.format = f }
+}
+
+// WithFilter sets the patterns used for filtering the fields of printed
+// objects and maps, see FilterMap. If both include and exclude are nil the
+// data is printed without filtering.
+func WithFilter(include, exclude []string) PrinterOption {
+	return func(c *printerConfig) {
+		c.include = include
+		c.exclude = exclude
+	}
+}
+
+// WithIdentifiersOnly makes the printer print only the identifiers of the
+// objects, instead of their attributes.
+func WithIdentifiersOnly(b bool) PrinterOption {
+	return func(c *printerConfig) { c.identifiersOnly = b }
+}
+
+// WithColors sets the colors used by the YAML format. By default no colors
+// are used.
+func WithColors(colors *yaml.Colors) PrinterOption {
+	return func(c *printerConfig) { c.colors = colors }
+}
+
+// WithDateKeys sets the patterns for the keys whose values are Unix
+// timestamps, which are annotated with a human-readable date in the YAML
+// format. By default they are: last_login, user_since, date and *_date.
+func WithDateKeys(patterns []string) PrinterOption {
+	return func(c *printerConfig) { c.dateKeys = patterns }
+}
+
+// WithProduct sets the name and version of the product generating the output,
+// which is included in the headers of the CEF and LEEF formats.
+func WithProduct(name, version string) PrinterOption {
+	return func(c *printerConfig) {
+		c.product = name
+		c.version = version
+	}
+}
+
+// Printer prints data and VirusTotal objects to an io.Writer.
+type Printer struct {
+	w   io.Writer
+	cfg *printerConfig
+}
+
+// NewPrinter returns a printer that writes to w.
+func NewPrinter(w io.Writer, opts ...PrinterOption) *Printer {
+	return &Printer{w: w, cfg: newPrinterConfig(opts)}
+}
+
+// Format returns the output format used by the printer.
+func (p *Printer) Format() Format {
+	return p.cfg.format
+}
+
+// Print prints arbitrary data. The data is printed as is, without applying
+// the filters.
+func (p *Printer) Print(data interface{}) error {
+	enc, err := p.cfg.encoder(p.w, p.cfg.format)
+	if err != nil {
+		return err
+	}
+	return enc.Encode(data)
+}
+
+// filtering returns true if the printer has include or exclude patterns.
+func (p *Printer) filtering() bool {
+	return p.cfg.include != nil || p.cfg.exclude != nil
+}
+
+// PrintMap prints a map after applying the filters.
+func (p *Printer) PrintMap(m map[string]interface{}) error {
+	if p.filtering() {
+		m = FilterMap(m, p.cfg.include, p.cfg.exclude)
+	}
+	return p.Print(m)
+}
+
+// PrintObjects prints a list of objects. Objects are converted to maps with
+// ObjectToMap and filtered, objects left empty by the filters are not
+// printed. If the printer was created with WithIdentifiersOnly only the
+// identifiers are printed.
+func (p *Printer) PrintObjects(objs []*vt.Object) error {
+	if p.cfg.identifiersOnly {
+		var ids []string
+		for _, obj := range objs {
+			ids = append(ids, obj.ID())
+		}
+		return p.Print(ids)
+	}
+	list := make([]map[string]interface{}, 0)
+	for _, obj := range objs {
+		m := ObjectToMap(obj)
+		if p.filtering() {
+			m = FilterMap(m, p.cfg.include, p.cfg.exclude)
+		}
+		if len(m) > 0 {
+			list = append(list, m)
+		}
+	}
+	if len(list) > 0 {
+		return p.Print(list)
+	}
+	return nil
+}
+
+// PrintObject prints a single object.
+func (p *Printer) PrintObject(obj *vt.Object) error {
+	return p.PrintObjects([]*vt.Object{obj})
+}
+
+// PrintIterator prints the objects returned by an iterator. With streaming
+// formats objects are printed as they are returned by the iterator, with the
+// remaining formats they are printed once the iterator is exhausted. The
+// iterator is not closed.
+func (p *Printer) PrintIterator(it *vt.Iterator) error {
+	stream := p.cfg.format.IsStreaming() && !p.cfg.identifiersOnly
+	var objs []*vt.Object
+	for it.Next() {
+		if stream {
+			if err := p.PrintObject(it.Get()); err != nil {
+				return err
+			}
+		} else {
+			objs = append(objs, it.Get())
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if stream {
+		return nil
+	}
+	return p.PrintObjects(objs)
+}
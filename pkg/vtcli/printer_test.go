@@ -0,0 +1,95 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func testObject(id string) *vt.Object {
+	obj := vt.NewObjectWithID("file", id)
+	obj.Set("size", 68)
+	obj.Set("meaningful_name", "eicar.com")
+	return obj
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatYAML, f)
+
+	f, err = ParseFormat("JSON")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSON, f)
+
+	_, err = ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+
+	assert.True(t, FormatCEF.IsStreaming())
+	assert.False(t, FormatCSV.IsStreaming())
+}
+
+func TestPrinterJSON(t *testing.T) {
+	b := new(bytes.Buffer)
+	p := NewPrinter(b, WithFormat(FormatJSON))
+	assert.NoError(t, p.PrintObject(testObject("foo")))
+
+	var list []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), &list))
+	assert.Equal(t, []map[string]interface{}{{
+		"_id":             "foo",
+		"_type":           "file",
+		"size":            float64(68),
+		"meaningful_name": "eicar.com",
+	}}, list)
+}
+
+func TestPrinterFilter(t *testing.T) {
+	b := new(bytes.Buffer)
+	p := NewPrinter(b, WithFormat(FormatCSV), WithFilter([]string{"_id", "size"}, nil))
+	assert.NoError(t, p.PrintObjects([]*vt.Object{testObject("foo"), testObject("bar")}))
+	assert.Equal(t, "_id,size\nfoo,68\nbar,68\n", b.String())
+}
+
+func TestPrinterFilterEmpty(t *testing.T) {
+	b := new(bytes.Buffer)
+	p := NewPrinter(b, WithFormat(FormatJSON), WithFilter([]string{"nonexistent"}, nil))
+	assert.NoError(t, p.PrintObject(testObject("foo")))
+	assert.Empty(t, b.String())
+}
+
+func TestPrinterIdentifiersOnly(t *testing.T) {
+	b := new(bytes.Buffer)
+	p := NewPrinter(b, WithIdentifiersOnly(true))
+	assert.NoError(t, p.PrintObjects([]*vt.Object{testObject("foo"), testObject("bar")}))
+	assert.Equal(t, "- \"foo\"\n- \"bar\"\n", b.String())
+}
+
+func TestPrinterUnknownFormat(t *testing.T) {
+	p := NewPrinter(new(bytes.Buffer), WithFormat("xml"))
+	assert.ErrorIs(t, p.Print("foo"), ErrUnknownFormat)
+}
+
+func TestNewEncoderYAML(t *testing.T) {
+	b := new(bytes.Buffer)
+	enc, err := NewEncoder(b, FormatYAML)
+	assert.NoError(t, err)
+	assert.NoError(t, enc.Encode(map[string]interface{}{"foo": "bar"}))
+	assert.Equal(t, "foo: \"bar\"\n", b.String())
+}
@@ -0,0 +1,254 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// DefaultSearchBatchSize is the number of hashes included in each of the
+// searches done by BatchFiles when the client is created without the
+// WithSearchBatchSize option.
+const DefaultSearchBatchSize = 50
+
+var hashRe = regexp.MustCompile(`^([[:xdigit:]]{64}|[[:xdigit:]]{40}|[[:xdigit:]]{32})$`)
+
+// searchChunk is a group of hashes that are looked up together.
+type searchChunk struct {
+	// items contain the hashes and their positions in the input.
+	items []BatchResult
+	// search is false for identifiers that are not hashes, which can't be
+	// included in a search and are looked up one by one.
+	search      bool
+	descriptors bool
+}
+
+// searchChunker groups hashes in chunks of at most size hashes. When only
+// descriptors are requested SHA-256 hashes are grouped apart from the rest,
+// as descriptors contain only the SHA-256 of the file and MD5 or SHA-1 hashes
+// can't be matched against them.
+type searchChunker struct {
+	size            int
+	descriptorsOnly bool
+	sha256, other   *searchChunk
+}
+
+// add adds the hash at the given position in the input, and returns the
+// chunks completed by it, if any.
+func (c *searchChunker) add(index int, hash string) []searchChunk {
+	item := BatchResult{Index: index, ID: hash}
+	switch {
+	case !hashRe.MatchString(hash):
+		return []searchChunk{{items: []BatchResult{item}}}
+	case c.descriptorsOnly && len(hash) == 64:
+		return c.append(&c.sha256, true, item)
+	default:
+		return c.append(&c.other, false, item)
+	}
+}
+
+func (c *searchChunker) append(chunk **searchChunk, descriptors bool, item BatchResult) []searchChunk {
+	if *chunk == nil {
+		*chunk = &searchChunk{search: true, descriptors: descriptors}
+	}
+	(*chunk).items = append((*chunk).items, item)
+	if len((*chunk).items) < c.size {
+		return nil
+	}
+	full := **chunk
+	*chunk = nil
+	return []searchChunk{full}
+}
+
+// flush returns the chunks that are not complete yet.
+func (c *searchChunker) flush() []searchChunk {
+	var chunks []searchChunk
+	for _, chunk := range []**searchChunk{&c.sha256, &c.other} {
+		if *chunk != nil {
+			chunks = append(chunks, **chunk)
+			*chunk = nil
+		}
+	}
+	return chunks
+}
+
+// fileNotFound returns the same error returned by the API when a file doesn't
+// exist.
+func fileNotFound(hash string) error {
+	return vt.Error{
+		Code:    "NotFoundError",
+		Message: fmt.Sprintf("File \"%s\" not found", hash),
+	}
+}
+
+// fileHashes returns the hashes of a file object. Descriptors only have the
+// SHA-256, which is the object's identifier.
+func fileHashes(obj *vt.Object) []string {
+	hashes := []string{obj.ID()}
+	for _, attr := range []string{"md5", "sha1", "sha256"} {
+		if h, err := obj.GetString(attr); err == nil && h != "" {
+			hashes = append(hashes, h)
+		}
+	}
+	return hashes
+}
+
+// searchFiles looks up the hashes in a chunk and sends one result per hash
+// to the results channel.
+func (c *Client) searchFiles(chunk searchChunk, results chan<- BatchResult) {
+	lookup := func() {
+		for _, r := range chunk.items {
+			r.Object, r.Err = c.Lookup("files/%s", r.ID)
+			results <- r
+		}
+	}
+	if !chunk.search {
+		lookup()
+		return
+	}
+	terms := make([]string, len(chunk.items))
+	for i, r := range chunk.items {
+		terms[i] = r.ID
+	}
+	found := make(map[string]*vt.Object)
+	it, err := c.Search(strings.Join(terms, " OR "),
+		vt.IteratorLimit(len(terms)),
+		vt.IteratorBatchSize(len(terms)),
+		vt.IteratorDescriptorsOnly(chunk.descriptors))
+	if err == nil {
+		for it.Next() {
+			obj := it.Get()
+			for _, h := range fileHashes(obj) {
+				found[strings.ToLower(h)] = obj
+			}
+		}
+		// The iterator is exhausted, it doesn't need to be closed.
+		err = it.Error()
+	}
+	// Searching requires VirusTotal Intelligence, if it fails the files are
+	// looked up one by one.
+	if err != nil {
+		lookup()
+		return
+	}
+	for _, r := range chunk.items {
+		if obj, ok := found[strings.ToLower(r.ID)]; ok {
+			r.Object = obj
+		} else {
+			r.Err = fileNotFound(r.ID)
+		}
+		results <- r
+	}
+}
+
+// BatchFiles looks up multiple files by their MD5, SHA-1 or SHA-256 hashes.
+// Instead of sending one request per file like Batch, it does VirusTotal
+// Intelligence searches that include many hashes at once, which reduces the
+// number of requests by the search batch size. Identifiers that are not
+// hashes, and hashes in searches that fail (for instance because the API key
+// doesn't have access to VirusTotal Intelligence), are looked up one by one.
+//
+// If descriptorsOnly is true the objects returned for SHA-256 hashes are
+// descriptors containing only the file's identifier, which is enough for
+// knowing if the file exists and much cheaper to retrieve.
+//
+// Results are sent to the returned channel in the same order the hashes have
+// in the input, like in Batch. Files that don't exist are reported with the
+// same error returned by the API for those files, see IsNotFound. See
+// BatchFilesStream for details.
+func (c *Client) BatchFiles(ctx context.Context, hashes []string, descriptorsOnly bool, opts ...BatchOption) <-chan BatchResult {
+	return c.BatchFilesStream(ctx, sliceChan(ctx, hashes), descriptorsOnly, opts...)
+}
+
+// BatchFilesStream is like BatchFiles, but the hashes are read from a channel
+// as they are needed, like in BatchStream. The WithWindow and Unordered
+// options work as they do with BatchStream too. Hashes are held back until
+// there are enough of them for filling a search, incomplete searches are done
+// only when the window is full or the input ends.
+func (c *Client) BatchFilesStream(ctx context.Context, hashes <-chan string, descriptorsOnly bool, opts ...BatchOption) <-chan BatchResult {
+	cfg := newBatchConfig(opts)
+	work := make(chan searchChunk)
+	results := make(chan BatchResult)
+	// A slot is taken for every hash read, and released when its result is
+	// sent to the caller.
+	slots := make(chan struct{}, cfg.window)
+
+	go func() {
+		defer close(work)
+		chunker := &searchChunker{size: c.searchBatchSize, descriptorsOnly: descriptorsOnly}
+		send := func(chunks []searchChunk) bool {
+			for _, chunk := range chunks {
+				select {
+				case work <- chunk:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+		for i := 0; ; i++ {
+			select {
+			case slots <- struct{}{}:
+			default:
+				// Hashes in incomplete chunks hold their slots, and the
+				// results after them can't be sent until they are looked
+				// up, so they must be sent before waiting for a slot.
+				if !send(chunker.flush()) {
+					return
+				}
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case h, ok := <-hashes:
+				if !ok {
+					send(chunker.flush())
+					return
+				}
+				if !send(chunker.add(i, h)) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < c.threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range work {
+				c.searchFiles(chunk, results)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return deliver(results, slots, cfg.unordered)
+}
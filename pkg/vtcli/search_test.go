@@ -0,0 +1,263 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	knownMD5    = strings.Repeat("a", 32)
+	knownSHA1   = strings.Repeat("a", 40)
+	knownSHA256 = strings.Repeat("a", 64)
+	otherSHA256 = strings.Repeat("b", 64)
+	unknownMD5  = strings.Repeat("c", 32)
+)
+
+// newSearchServer returns a server that knows about two files. If
+// intelligence is false searches are forbidden. The returned counters are
+// incremented with each search and each file request.
+func newSearchServer(t *testing.T, intelligence bool) (searches, lookups *int32) {
+	searches, lookups = new(int32), new(int32)
+	files := map[string]map[string]interface{}{
+		knownSHA256: {
+			"type": "file", "id": knownSHA256,
+			"attributes": map[string]string{"md5": knownMD5, "sha1": knownSHA1, "sha256": knownSHA256},
+		},
+		otherSHA256: {
+			"type": "file", "id": otherSHA256,
+			"attributes": map[string]string{"sha256": otherSHA256},
+		},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v3/intelligence/search" {
+			atomic.AddInt32(searches, 1)
+			if !intelligence {
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"error": map[string]string{"code": "ForbiddenError", "message": "forbidden"}})
+				return
+			}
+			data := make([]interface{}, 0)
+			for _, term := range strings.Split(r.URL.Query().Get("query"), " OR ") {
+				for _, f := range files {
+					attrs := f["attributes"].(map[string]string)
+					if term == attrs["md5"] || term == attrs["sha1"] || term == attrs["sha256"] {
+						if r.URL.Query().Get("descriptors_only") == "true" {
+							f = map[string]interface{}{"type": "file", "id": f["id"]}
+						}
+						data = append(data, f)
+					}
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+			return
+		}
+		atomic.AddInt32(lookups, 1)
+		id := strings.TrimPrefix(r.URL.Path, "/api/v3/files/")
+		for _, f := range files {
+			attrs := f["attributes"].(map[string]string)
+			if id == attrs["md5"] || id == attrs["sha1"] || id == attrs["sha256"] {
+				json.NewEncoder(w).Encode(map[string]interface{}{"data": f})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]string{"code": "NotFoundError", "message": "not found"}})
+	}))
+	vt.SetHost(ts.URL)
+	t.Cleanup(func() {
+		ts.Close()
+		vt.SetHost("https://www.virustotal.com")
+	})
+	return searches, lookups
+}
+
+func collectResults(ch <-chan BatchResult) []BatchResult {
+	var results []BatchResult
+	for r := range ch {
+		results = append(results, r)
+	}
+	return results
+}
+
+func chunkIndexes(chunks []searchChunk) [][]int {
+	indexes := make([][]int, len(chunks))
+	for i, chunk := range chunks {
+		for _, r := range chunk.items {
+			indexes[i] = append(indexes[i], r.Index)
+		}
+	}
+	return indexes
+}
+
+func TestSearchChunker(t *testing.T) {
+	hashes := []string{knownSHA256, knownMD5, "foo", otherSHA256, knownSHA1}
+	tests := []struct {
+		name            string
+		size            int
+		descriptorsOnly bool
+		// added are the chunks completed by each hash, flushed the chunks
+		// returned by flush at the end.
+		added   [][][]int
+		flushed [][]int
+	}{
+		{
+			name:            "descriptors apart",
+			size:            10,
+			descriptorsOnly: true,
+			added:           [][][]int{{}, {}, {{2}}, {}, {}},
+			flushed:         [][]int{{0, 3}, {1, 4}},
+		},
+		{
+			name:    "full chunks",
+			size:    2,
+			added:   [][][]int{{}, {{0, 1}}, {{2}}, {}, {{3, 4}}},
+			flushed: [][]int{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &searchChunker{size: test.size, descriptorsOnly: test.descriptorsOnly}
+			for i, h := range hashes {
+				assert.Equal(t, test.added[i], chunkIndexes(c.add(i, h)))
+			}
+			assert.Equal(t, test.flushed, chunkIndexes(c.flush()))
+			assert.Empty(t, c.flush())
+		})
+	}
+
+	c := &searchChunker{size: 10, descriptorsOnly: true}
+	c.add(0, knownSHA256)
+	c.add(1, knownMD5)
+	c.add(2, "foo")
+	chunks := c.flush()
+	assert.Equal(t, searchChunk{
+		items:       []BatchResult{{Index: 0, ID: knownSHA256}},
+		search:      true,
+		descriptors: true,
+	}, chunks[0])
+	assert.Equal(t, searchChunk{
+		items:  []BatchResult{{Index: 1, ID: knownMD5}},
+		search: true,
+	}, chunks[1])
+}
+
+func TestBatchFiles(t *testing.T) {
+	searches, lookups := newSearchServer(t, true)
+	c, err := NewClient("apikey", WithSearchBatchSize(3))
+	assert.NoError(t, err)
+
+	hashes := []string{knownMD5, unknownMD5, otherSHA256, knownSHA1, knownSHA256}
+	results := collectResults(c.BatchFiles(context.Background(), hashes, false))
+
+	assert.Len(t, results, len(hashes))
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, hashes[i], r.ID)
+	}
+	assert.Equal(t, knownSHA256, results[0].Object.ID())
+	assert.True(t, IsNotFound(results[1].Err))
+	assert.Equal(t, otherSHA256, results[2].Object.ID())
+	assert.Equal(t, knownSHA256, results[3].Object.ID())
+	assert.Equal(t, knownSHA256, results[4].Object.ID())
+	assert.Equal(t, int32(2), atomic.LoadInt32(searches))
+	assert.Equal(t, int32(0), atomic.LoadInt32(lookups))
+}
+
+func TestBatchFilesDescriptorsOnly(t *testing.T) {
+	newSearchServer(t, true)
+	c, err := NewClient("apikey")
+	assert.NoError(t, err)
+
+	hashes := []string{knownMD5, otherSHA256, unknownMD5, strings.Repeat("d", 64)}
+	results := collectResults(c.BatchFiles(context.Background(), hashes, true))
+
+	assert.Len(t, results, len(hashes))
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.True(t, IsNotFound(results[2].Err))
+	assert.True(t, IsNotFound(results[3].Err))
+}
+
+func TestBatchFilesFallback(t *testing.T) {
+	searches, lookups := newSearchServer(t, false)
+	c, err := NewClient("apikey", WithSearchBatchSize(10))
+	assert.NoError(t, err)
+
+	hashes := []string{knownMD5, unknownMD5, otherSHA256}
+	results := collectResults(c.BatchFiles(context.Background(), hashes, false))
+
+	assert.Len(t, results, len(hashes))
+	assert.Equal(t, knownSHA256, results[0].Object.ID())
+	assert.True(t, IsNotFound(results[1].Err))
+	assert.Equal(t, otherSHA256, results[2].Object.ID())
+	assert.Equal(t, int32(1), atomic.LoadInt32(searches))
+	assert.Equal(t, int32(3), atomic.LoadInt32(lookups))
+}
+
+func TestBatchFilesWindow(t *testing.T) {
+	searches, lookups := newSearchServer(t, true)
+	c, err := NewClient("apikey", WithSearchBatchSize(10))
+	assert.NoError(t, err)
+
+	// With a window smaller than the search batch size incomplete searches
+	// are done when the window is full.
+	hashes := []string{knownMD5, unknownMD5, otherSHA256, knownSHA1, knownSHA256, "foo"}
+	results := collectResults(c.BatchFiles(context.Background(), hashes, false, WithWindow(4)))
+	assert.Len(t, results, len(hashes))
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, hashes[i], r.ID)
+	}
+	assert.True(t, IsNotFound(results[5].Err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(searches))
+	assert.Equal(t, int32(1), atomic.LoadInt32(lookups))
+
+	// Results are received before the input ends.
+	ch := make(chan string)
+	out := c.BatchFilesStream(context.Background(), ch, false, WithWindow(2))
+	ch <- knownMD5
+	ch <- otherSHA256
+	r := <-out
+	assert.Equal(t, 0, r.Index)
+	assert.Equal(t, knownSHA256, r.Object.ID())
+	ch <- unknownMD5
+	close(ch)
+	assert.Len(t, collectResults(out), 2)
+}
+
+func TestBatchFilesUnordered(t *testing.T) {
+	newSearchServer(t, true)
+	c, err := NewClient("apikey", WithSearchBatchSize(2))
+	assert.NoError(t, err)
+
+	hashes := []string{knownMD5, unknownMD5, otherSHA256, knownSHA1, knownSHA256}
+	results := collectResults(c.BatchFiles(context.Background(), hashes, false, Unordered()))
+	assert.Len(t, results, len(hashes))
+	for _, r := range results {
+		assert.Equal(t, hashes[r.Index], r.ID)
+	}
+}
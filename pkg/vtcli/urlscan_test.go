@@ -0,0 +1,77 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vtcli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanURL(t *testing.T) {
+	var path string
+	var form map[string]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("X-Apikey") != "apikey" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": {"code": "WrongCredentialsError", "message": "wrong API key"}}`)
+			return
+		}
+		assert.NoError(t, r.ParseMultipartForm(1024))
+		form = make(map[string]string)
+		for k, v := range r.MultipartForm.Value {
+			form[k] = v[0]
+		}
+		fmt.Fprint(w, `{"data": {"type": "analysis", "id": "u-1234-5678"}}`)
+	}))
+	vt.SetHost(ts.URL)
+	t.Cleanup(func() {
+		ts.Close()
+		vt.SetHost("https://www.virustotal.com")
+	})
+
+	c, err := NewClient("apikey")
+	assert.NoError(t, err)
+
+	analysis, err := c.ScanURL(context.Background(), "http://example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "u-1234-5678", analysis.ID())
+	assert.Equal(t, "/api/v3/urls", path)
+	assert.Equal(t, map[string]string{"url": "http://example.com"}, form)
+
+	_, err = c.ScanURL(context.Background(), "http://example.com",
+		PrivateScan(),
+		WithUserAgent("Mozilla/5.0"),
+		WithInteractionTimeout(90*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v3/private/urls", path)
+	assert.Equal(t, map[string]string{
+		"url":                 "http://example.com",
+		"user_agent":          "Mozilla/5.0",
+		"interaction_timeout": "90",
+	}, form)
+
+	c, err = NewClient("wrong")
+	assert.NoError(t, err)
+	_, err = c.ScanURL(context.Background(), "http://example.com")
+	assert.Equal(t, vt.Error{Code: "WrongCredentialsError", Message: "wrong API key"}, err)
+}
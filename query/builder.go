@@ -0,0 +1,77 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Builder composes a query from modifiers and values. The terms added to the
+// builder are combined with AND.
+type Builder struct {
+	now   time.Time
+	terms []string
+}
+
+// NewBuilder returns a new Builder. Relative dates are expanded to absolute
+// dates computed from now.
+func NewBuilder(now time.Time) *Builder {
+	return &Builder{now: now}
+}
+
+// Add adds a term with the given modifier and value, which is quoted if it
+// contains spaces. It returns an error if the modifier is unknown or the
+// value is not valid for the modifier.
+func (b *Builder) Add(modifier, value string) error {
+	k, ok := Modifiers[modifier]
+	if !ok {
+		return fmt.Errorf("unknown modifier %q", modifier)
+	}
+	if k == KindString && strings.ContainsAny(value, " \t\"()") {
+		value = `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+	}
+	if msg := checkValue(k, value); msg != "" {
+		return fmt.Errorf("%s:%s: %s", modifier, value, msg)
+	}
+	if k == KindDate {
+		value = expandDate(value, b.now)
+	}
+	b.terms = append(b.terms, fmt.Sprintf("%s:%s", modifier, value))
+	return nil
+}
+
+// AddQuery adds a query, which is enclosed in parentheses if it contains
+// more than one term. It returns the first problem found in the query that
+// is not a warning, if any.
+func (b *Builder) AddQuery(q string) error {
+	if errs := Errors(Lint(q)); len(errs) > 0 {
+		return errs[0]
+	}
+	q, err := ExpandDates(q, b.now)
+	if err != nil {
+		return err
+	}
+	if tokens, _ := scan(q); len(tokens) > 1 {
+		q = "(" + strings.TrimSpace(q) + ")"
+	}
+	b.terms = append(b.terms, q)
+	return nil
+}
+
+// String returns the query.
+func (b *Builder) String() string {
+	return strings.Join(b.terms, " ")
+}
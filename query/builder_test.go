@@ -0,0 +1,39 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder(now)
+	assert.NoError(t, b.Add("type", "peexe"))
+	assert.NoError(t, b.Add("p", "5+"))
+	assert.NoError(t, b.Add("fs", "7d+"))
+	assert.NoError(t, b.Add("name", `my "file".exe`))
+	assert.NoError(t, b.AddQuery("tag:signed OR tag:overlay"))
+	assert.NoError(t, b.AddQuery("eicar"))
+	assert.Equal(t,
+		`type:peexe p:5+ fs:2024-01-24T10:00:00+ name:"my \"file\".exe" (tag:signed OR tag:overlay) eicar`,
+		b.String())
+
+	assert.EqualError(t, b.Add("p", "many"),
+		"p:many: expecting a number optionally followed by + or -, like 5+")
+	assert.EqualError(t, b.Add("foo", "bar"), `unknown modifier "foo"`)
+	assert.Error(t, b.AddQuery("p:x"))
+	assert.NoError(t, b.AddQuery("foo:bar"))
+}
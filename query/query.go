@@ -0,0 +1,454 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package query parses VirusTotal Intelligence search queries locally, for
+// finding problems before sending them to the API, where a misspelled
+// modifier or a malformed date silently matches nothing.
+//
+// A query is a sequence of terms, which can be grouped with parentheses and
+// combined with the AND, OR and NOT operators. Terms are either free text,
+// like a hash or a word, or a modifier followed by a colon and a value, like
+// type:peexe or p:5+. Lint checks that modifiers exist and that their values
+// have the syntax expected by each of them. ExpandDates replaces relative
+// dates like fs:7d+, meaning first seen within the last 7 days, with absolute
+// ones. Builder composes queries from modifiers and values.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind is the type of the values accepted by a modifier.
+type Kind int
+
+// Kinds of modifier values.
+const (
+	// KindString is any non-empty value.
+	KindString Kind = iota
+	// KindNumber is an integer optionally followed by + (at least) or
+	// - (at most), like 5+.
+	KindNumber
+	// KindDate is a date with format YYYY-MM-DD, YYYY-MM-DDTHH:MM or
+	// YYYY-MM-DDTHH:MM:SS, or a relative date like 7d meaning 7 days ago,
+	// optionally followed by + (after) or - (before). Relative dates accept
+	// the units h (hours), d (days) and w (weeks).
+	KindDate
+	// KindSize is a number of bytes, optionally followed by the units kb, mb
+	// or gb and by + (at least) or - (at most), like 100kb+.
+	KindSize
+	// KindContent is a quoted string, a sequence of hex bytes enclosed in
+	// braces or a regular expression enclosed in slashes.
+	KindContent
+)
+
+// Modifiers are the search modifiers known by the parser and the kind of
+// their values. The list is not exhaustive, so unknown modifiers are reported
+// as warnings.
+var Modifiers = map[string]Kind{
+	"androguard":                   KindString,
+	"asn":                          KindNumber,
+	"attack_tactic":                KindString,
+	"attack_technique":             KindString,
+	"authentihash":                 KindString,
+	"behavior":                     KindString,
+	"behaviour":                    KindString,
+	"behaviour_files":              KindString,
+	"behaviour_injected_processes": KindString,
+	"behaviour_network":            KindString,
+	"behaviour_processes":          KindString,
+	"behaviour_registry":           KindString,
+	"behaviour_services":           KindString,
+	"children":                     KindString,
+	"comment":                      KindString,
+	"content":                      KindContent,
+	"country":                      KindString,
+	"crowdsourced_ids":             KindString,
+	"crowdsourced_yara_rule":       KindString,
+	"domain":                       KindString,
+	"embedded_domain":              KindString,
+	"embedded_ip":                  KindString,
+	"embedded_url":                 KindString,
+	"engines":                      KindString,
+	"entity":                       KindString,
+	"exports":                      KindString,
+	"fs":                           KindDate,
+	"have":                         KindString,
+	"hostname":                     KindString,
+	"imphash":                      KindString,
+	"imports":                      KindString,
+	"ip":                           KindString,
+	"itw":                          KindString,
+	"jarm":                         KindString,
+	"la":                           KindDate,
+	"lang":                         KindString,
+	"ls":                           KindDate,
+	"magic":                        KindString,
+	"main_icon_dhash":              KindString,
+	"md5":                          KindString,
+	"metadata":                     KindString,
+	"name":                         KindString,
+	"p":                            KindNumber,
+	"parent":                       KindString,
+	"path":                         KindString,
+	"popular_threat_category":      KindString,
+	"popular_threat_name":          KindString,
+	"positives":                    KindNumber,
+	"registrar":                    KindString,
+	"resource":                     KindString,
+	"response_code":                KindNumber,
+	"sandbox_name":                 KindString,
+	"section":                      KindString,
+	"sha1":                         KindString,
+	"sha256":                       KindString,
+	"sigma_rule":                   KindString,
+	"signature":                    KindString,
+	"signer":                       KindString,
+	"similar-to":                   KindString,
+	"size":                         KindSize,
+	"sources":                      KindNumber,
+	"ssdeep":                       KindString,
+	"submissions":                  KindNumber,
+	"submitter":                    KindString,
+	"suggested_threat_label":       KindString,
+	"tag":                          KindString,
+	"title":                        KindString,
+	"tld":                          KindString,
+	"tlsh":                         KindString,
+	"trid":                         KindString,
+	"type":                         KindString,
+	"url":                          KindString,
+	"vhash":                        KindString,
+	"whois":                        KindString,
+}
+
+// Error is a problem found in a query.
+type Error struct {
+	// Offset is the position of the problematic term in the query, in bytes.
+	Offset int
+	// Term is the problematic term.
+	Term string
+	Msg  string
+	// Warning is true for problems that don't necessarily make the query
+	// wrong, like modifiers that are not in Modifiers.
+	Warning bool
+}
+
+func (e *Error) Error() string {
+	if e.Warning {
+		return fmt.Sprintf("%q at offset %d: warning: %s", e.Term, e.Offset, e.Msg)
+	}
+	return fmt.Sprintf("%q at offset %d: %s", e.Term, e.Offset, e.Msg)
+}
+
+// Errors returns the problems in errs that are not warnings.
+func Errors(errs []*Error) []*Error {
+	var res []*Error
+	for _, err := range errs {
+		if !err.Warning {
+			res = append(res, err)
+		}
+	}
+	return res
+}
+
+type tokenType int
+
+const (
+	tokenTerm tokenType = iota
+	tokenOperator
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	typ    tokenType
+	offset int
+	text   string
+	// modifier and value are set for terms with the form modifier:value.
+	modifier string
+	value    string
+}
+
+var modifierRe = regexp.MustCompile(`^-?([A-Za-z_][A-Za-z0-9_-]*):(.*)$`)
+
+// scan splits a query in tokens. It returns an error if a quoted string, a
+// hex string or a regular expression is not terminated.
+func scan(q string) ([]token, *Error) {
+	var tokens []token
+	i := 0
+	for i < len(q) {
+		switch c := q[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '(':
+			tokens = append(tokens, token{typ: tokenOpen, offset: i, text: "("})
+			i++
+			continue
+		case c == ')':
+			tokens = append(tokens, token{typ: tokenClose, offset: i, text: ")"})
+			i++
+			continue
+		}
+		start := i
+		// Parentheses opened within a term, like in name:foo(1).exe, belong to
+		// the term.
+		depth := 0
+	term:
+		for i < len(q) {
+			c := q[i]
+			var closing byte
+			switch {
+			case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+				break term
+			case c == ')' && depth == 0:
+				break term
+			case c == '(':
+				depth++
+			case c == ')':
+				depth--
+			case c == '"':
+				closing = '"'
+			case c == '{':
+				closing = '}'
+			case c == '/' && i > 0 && q[i-1] == ':':
+				closing = '/'
+			}
+			i++
+			if closing == 0 {
+				continue
+			}
+			for i < len(q) && q[i] != closing {
+				if q[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(q) {
+				return nil, &Error{Offset: start, Term: q[start:], Msg: fmt.Sprintf("missing closing %c", closing)}
+			}
+			i++
+		}
+		t := token{typ: tokenTerm, offset: start, text: q[start:i]}
+		switch t.text {
+		case "AND", "OR", "NOT":
+			t.typ = tokenOperator
+		default:
+			// Terms like http://example.com are not modifiers.
+			if m := modifierRe.FindStringSubmatch(t.text); m != nil && !strings.HasPrefix(m[2], "//") {
+				t.modifier, t.value = m[1], m[2]
+			}
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+var (
+	numberRe  = regexp.MustCompile(`^\d+[+-]?$`)
+	dateRe    = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}(:\d{2})?)?)([+-]?)$`)
+	relDateRe = regexp.MustCompile(`^(\d+)([hdw])([+-]?)$`)
+	sizeRe    = regexp.MustCompile(`(?i)^\d+(\.\d+)?(b|kb|mb|gb)?[+-]?$`)
+	hexRe     = regexp.MustCompile(`^\{[[:xdigit:]?\s\[\]\-|()]*\}$`)
+)
+
+var dateLayouts = []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02T15:04:05"}
+
+func unquote(v string) string {
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// checkValue returns an error message if v is not a valid value of kind k.
+func checkValue(k Kind, v string) string {
+	if unquote(v) == "" {
+		return "missing value"
+	}
+	switch k {
+	case KindNumber:
+		if !numberRe.MatchString(v) {
+			return "expecting a number optionally followed by + or -, like 5+"
+		}
+	case KindSize:
+		if !sizeRe.MatchString(v) {
+			return "expecting a size optionally followed by + or -, like 100kb+"
+		}
+	case KindDate:
+		if relDateRe.MatchString(v) {
+			return ""
+		}
+		m := dateRe.FindStringSubmatch(v)
+		if m == nil {
+			return "expecting a date like 2024-01-31, 2024-01-31T10:00:00 or 7d, optionally followed by + or -"
+		}
+		if _, err := time.Parse(dateLayouts[strings.Count(m[1], ":")], m[1]); err != nil {
+			return fmt.Sprintf("invalid date %s", m[1])
+		}
+	case KindContent:
+		if v[0] == '{' && !hexRe.MatchString(v) {
+			return "invalid hex string, expecting hex bytes like {cafe babe}"
+		}
+		if v[0] == '/' {
+			if _, err := regexp.Compile(v[1 : len(v)-1]); err != nil {
+				return fmt.Sprintf("invalid regular expression: %v", err)
+			}
+		}
+	}
+	return ""
+}
+
+// Lint returns the problems found in a query, or nil if there are none.
+// Unknown modifiers are reported as warnings.
+func Lint(q string) []*Error {
+	tokens, err := scan(q)
+	if err != nil {
+		return []*Error{err}
+	}
+	var errs []*Error
+	add := func(t token, format string, args ...interface{}) {
+		errs = append(errs, &Error{Offset: t.offset, Term: t.text, Msg: fmt.Sprintf(format, args...)})
+	}
+	warn := func(t token, format string, args ...interface{}) {
+		errs = append(errs, &Error{Offset: t.offset, Term: t.text, Msg: fmt.Sprintf(format, args...), Warning: true})
+	}
+	var open []token
+	for i, t := range tokens {
+		switch t.typ {
+		case tokenOpen:
+			open = append(open, t)
+		case tokenClose:
+			if len(open) == 0 {
+				add(t, "unbalanced parenthesis")
+			} else {
+				open = open[:len(open)-1]
+			}
+		case tokenOperator:
+			last := i == len(tokens)-1 || tokens[i+1].typ == tokenClose || tokens[i+1].typ == tokenOperator && tokens[i+1].text != "NOT"
+			first := i == 0 || tokens[i-1].typ == tokenOpen || tokens[i-1].typ == tokenOperator
+			if last || t.text != "NOT" && first {
+				add(t, "missing operand")
+			}
+		case tokenTerm:
+			if t.modifier == "" {
+				continue
+			}
+			k, ok := Modifiers[strings.ToLower(t.modifier)]
+			if !ok {
+				if s := suggest(t.modifier); s != "" {
+					warn(t, "unknown modifier %q, did you mean %q?", t.modifier, s)
+				} else {
+					warn(t, "unknown modifier %q", t.modifier)
+				}
+				continue
+			}
+			if msg := checkValue(k, t.value); msg != "" {
+				add(t, "%s", msg)
+			}
+		}
+	}
+	for _, t := range open {
+		add(t, "unbalanced parenthesis")
+	}
+	return errs
+}
+
+// suggest returns the known modifier that is closest to m, if it's close
+// enough for being a typo.
+func suggest(m string) string {
+	m = strings.ToLower(m)
+	names := make([]string, 0, len(Modifiers))
+	for name := range Modifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	best, bestDist := "", 3
+	for _, name := range names {
+		if d := distance(m, name); d < bestDist && d < len(name) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// DateLayout is the layout of the absolute dates produced by ExpandDates.
+const DateLayout = "2006-01-02T15:04:05"
+
+// expandDate returns the absolute date, relative to now, for a relative date
+// like 7d+. Other values are returned unchanged.
+func expandDate(v string, now time.Time) string {
+	m := relDateRe.FindStringSubmatch(v)
+	if m == nil {
+		return v
+	}
+	var n int
+	fmt.Sscan(m[1], &n)
+	d := time.Duration(n) * time.Hour
+	switch m[2] {
+	case "d":
+		d *= 24
+	case "w":
+		d *= 24 * 7
+	}
+	return now.UTC().Add(-d).Format(DateLayout) + m[3]
+}
+
+// ExpandDates returns the query with relative dates, like the one in fs:7d+,
+// replaced by absolute dates computed from now, like fs:2024-01-24T10:00:00+.
+func ExpandDates(q string, now time.Time) (string, error) {
+	tokens, err := scan(q)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	last := 0
+	for _, t := range tokens {
+		if t.modifier == "" || Modifiers[strings.ToLower(t.modifier)] != KindDate {
+			continue
+		}
+		if v := expandDate(t.value, now); v != t.value {
+			end := t.offset + len(t.text)
+			b.WriteString(q[last : end-len(t.value)])
+			b.WriteString(v)
+			last = end
+		}
+	}
+	b.WriteString(q[last:])
+	return b.String(), nil
+}
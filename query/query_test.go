@@ -0,0 +1,95 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC)
+
+func TestScan(t *testing.T) {
+	tokens, err := scan(`(type:peexe OR name:"foo bar.exe") NOT content:{ca fe} name:a(1).exe http://x.com`)
+	assert.Nil(t, err)
+	var texts []string
+	for _, t := range tokens {
+		texts = append(texts, t.text)
+	}
+	assert.Equal(t, []string{
+		"(", "type:peexe", "OR", `name:"foo bar.exe"`, ")", "NOT",
+		"content:{ca fe}", "name:a(1).exe", "http://x.com"}, texts)
+	assert.Equal(t, "name", tokens[3].modifier)
+	assert.Equal(t, `"foo bar.exe"`, tokens[3].value)
+	assert.Equal(t, "", tokens[8].modifier)
+
+	_, err = scan(`content:"foo`)
+	assert.EqualError(t, err, `"content:\"foo" at offset 0: missing closing "`)
+}
+
+func TestLint(t *testing.T) {
+	for _, q := range []string{
+		"eicar",
+		"type:peexe p:5+ fs:7d+ ls:2024-01-01T10:00:00- size:100kb+",
+		`(tag:signed OR tag:overlay) AND NOT engines:emotet`,
+		`content:"foo bar" content:{cafe ?? babe} content:/virustotal(\.com|\.org)/`,
+		"-tag:signed similar-to:44d88612fea8a8f36de82e1278abb02f",
+		`have:itw signer:"Microsoft Windows" embedded_domain:example.com`,
+	} {
+		assert.Empty(t, Lint(q), q)
+	}
+
+	lint := func(q string) []string {
+		var msgs []string
+		for _, err := range Lint(q) {
+			msgs = append(msgs, err.Msg)
+		}
+		return msgs
+	}
+	assert.Equal(t, []string{`unknown modifier "tpye", did you mean "type"?`}, lint("tpye:peexe"))
+	assert.Equal(t, []string{`unknown modifier "foobarbaz"`}, lint("foobarbaz:1"))
+	assert.Equal(t, []string{"expecting a number optionally followed by + or -, like 5+"}, lint("p:five"))
+	assert.Equal(t, []string{"invalid date 2024-02-30"}, lint("fs:2024-02-30+"))
+	assert.Equal(t, []string{"expecting a size optionally followed by + or -, like 100kb+"}, lint("size:1tb"))
+	assert.Equal(t, []string{"missing value"}, lint("tag:"))
+	assert.Equal(t, []string{"unbalanced parenthesis"}, lint("(tag:a OR tag:b"))
+	assert.Equal(t, []string{"missing operand"}, lint("tag:a OR"))
+	assert.Equal(t, []string{"missing operand"}, lint("AND tag:a"))
+
+	errs := Lint("type:peexe p:x")
+	assert.Equal(t, 11, errs[0].Offset)
+	assert.Equal(t, "p:x", errs[0].Term)
+
+	// Unknown modifiers are warnings, they may be newer than the parser.
+	errs = Lint("tpye:peexe p:x")
+	if assert.Len(t, errs, 2) {
+		assert.True(t, errs[0].Warning)
+		assert.EqualError(t, errs[0], `"tpye:peexe" at offset 0: warning: unknown modifier "tpye", did you mean "type"?`)
+		assert.False(t, errs[1].Warning)
+	}
+	assert.Equal(t, errs[1:], Errors(errs))
+}
+
+func TestExpandDates(t *testing.T) {
+	q, err := ExpandDates("type:peexe fs:7d+ (ls:2024-01-01- OR -la:12h+) p:5+", now)
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"type:peexe fs:2024-01-24T10:00:00+ (ls:2024-01-01- OR -la:2024-01-30T22:00:00+) p:5+", q)
+
+	q, err = ExpandDates("fs:2w", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "fs:2024-01-17T10:00:00", q)
+}
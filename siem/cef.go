@@ -0,0 +1,131 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package siem
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// In CEF headers pipes and backslashes must be escaped. In the extension
+// backslashes and equal signs are escaped, and line breaks are encoded as \n
+// and \r.
+var (
+	cefHeaderEscape    = escaper(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefExtensionEscape = escaper(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// CEFEncoder writes objects as ArcSight Common Event Format (CEF) records, one
+// record per line.
+type CEFEncoder struct {
+	w    io.Writer
+	opts *options
+}
+
+// NewCEFEncoder returns a new CEF encoder that writes to w.
+func NewCEFEncoder(w io.Writer, opts ...EncoderOption) *CEFEncoder {
+	return &CEFEncoder{w: w, opts: newOptions(opts)}
+}
+
+// Encode writes the CEF encoding of v to the stream. v must be a map or a
+// slice of maps.
+func (enc *CEFEncoder) Encode(v interface{}) error {
+	return forEachMap(v, func(m map[string]interface{}) error {
+		return writeLine(enc.w, enc.record(newIndicator(m)))
+	})
+}
+
+func (enc *CEFEncoder) record(i *indicator) string {
+	header := []string{
+		"CEF:0",
+		cefHeaderEscape(vendor),
+		cefHeaderEscape(enc.opts.product),
+		cefHeaderEscape(enc.opts.version),
+		cefHeaderEscape(i.Type),
+		cefHeaderEscape(fmt.Sprintf("%s %s indicator", vendor, i.Type)),
+		strconv.Itoa(i.Severity()),
+	}
+
+	ext := map[string]string{
+		"externalId": i.ID,
+		"rt":         strconv.FormatInt(i.Timestamp().UnixMilli(), 10),
+		"cs6Label":   "permalink",
+		"cs6":        i.Permalink(),
+	}
+
+	add := func(key, value string) {
+		if value != "" {
+			ext[key] = value
+		}
+	}
+
+	switch i.Type {
+	case "file":
+		add("fileHash", i.SHA256)
+		add("fname", i.FileName)
+		add("fileType", i.FileType)
+		if i.FileSize > 0 {
+			add("fsize", strconv.FormatInt(i.FileSize, 10))
+		}
+	case "url":
+		add("request", i.URL)
+	case "domain":
+		add("dhost", i.Domain)
+	case "ip_address":
+		if i.IsIPv6() {
+			add("c6a3Label", "Destination IPv6 Address")
+			add("c6a3", i.IP)
+		} else {
+			add("dst", i.IP)
+		}
+	}
+
+	if i.Total() > 0 {
+		ext["cn1Label"] = "malicious"
+		ext["cn1"] = strconv.FormatInt(i.Malicious, 10)
+		ext["cn2Label"] = "suspicious"
+		ext["cn2"] = strconv.FormatInt(i.Suspicious, 10)
+		ext["cn3Label"] = "engines"
+		ext["cn3"] = strconv.FormatInt(i.Total(), 10)
+	}
+	if len(i.Tags) > 0 {
+		ext["cs1Label"] = "tags"
+		ext["cs1"] = strings.Join(i.Tags, ",")
+	}
+	if i.Origin != "" {
+		ext["cs2Label"] = "origin"
+		ext["cs2"] = i.Origin
+	}
+	if len(i.Sources) > 0 {
+		ext["cs3Label"] = "sources"
+		ext["cs3"] = strings.Join(i.SourceNames(), ",")
+	}
+	if !i.FirstSeen.IsZero() {
+		ext["deviceCustomDate1Label"] = "first_seen"
+		ext["deviceCustomDate1"] = strconv.FormatInt(i.FirstSeen.UnixMilli(), 10)
+	}
+	if !i.LastSeen.IsZero() {
+		ext["deviceCustomDate2Label"] = "last_seen"
+		ext["deviceCustomDate2"] = strconv.FormatInt(i.LastSeen.UnixMilli(), 10)
+	}
+
+	pairs := make([]string, 0, len(ext))
+	for _, k := range sortedKeys(ext) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, cefExtensionEscape(ext[k])))
+	}
+
+	return strings.Join(header, "|") + "|" + strings.Join(pairs, " ")
+}
@@ -0,0 +1,41 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package siem
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCEF(t *testing.T) {
+	b := new(bytes.Buffer)
+	err := NewCEFEncoder(b, EncoderProduct("vt|cli", "1.0")).Encode(fileObject)
+	assert.NoError(t, err)
+
+	line := b.String()
+	assert.True(t, strings.HasPrefix(line,
+		`CEF:0|VirusTotal|vt\|cli|1.0|file|VirusTotal file indicator|8|`), line)
+	assert.Contains(t, line, ` fname=eicar|test\=1.com `)
+	assert.Contains(t, line, `|cn1=60 `)
+	assert.Contains(t, line, ` cs1=known-distributor `)
+	assert.True(t, strings.HasSuffix(line, "\n"))
+}
+
+func TestCEFExtensionEscape(t *testing.T) {
+	assert.Equal(t, `a\\b\=c\nd`, cefExtensionEscape("a\\b=c\nd"))
+	assert.Equal(t, `a\|b\\c`, cefHeaderEscape(`a|b\c`))
+}
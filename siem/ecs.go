@@ -0,0 +1,199 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package siem
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ecsVersion is the version of the Elastic Common Schema implemented by the
+// ECS encoder.
+const ecsVersion = "8.11.0"
+
+// ECSEncoder writes objects as Elastic Common Schema documents, one JSON
+// document per line. Objects are mapped to the threat.indicator.* fields.
+type ECSEncoder struct {
+	w    io.Writer
+	opts *options
+}
+
+// NewECSEncoder returns a new ECS encoder that writes to w.
+func NewECSEncoder(w io.Writer, opts ...EncoderOption) *ECSEncoder {
+	return &ECSEncoder{w: w, opts: newOptions(opts)}
+}
+
+// Encode writes the ECS encoding of v to the stream. v must be a map or a
+// slice of maps.
+func (enc *ECSEncoder) Encode(v interface{}) error {
+	return forEachMap(v, func(m map[string]interface{}) error {
+		b, err := json.Marshal(ecsDocument(newIndicator(m)))
+		if err != nil {
+			return err
+		}
+		return writeLine(enc.w, string(b))
+	})
+}
+
+// ecsIndicatorType maps VirusTotal object types to the values accepted by the
+// threat.indicator.type field, which are based on STIX 2.0 object types.
+func ecsIndicatorType(i *indicator) string {
+	switch i.Type {
+	case "file":
+		return "file"
+	case "url":
+		return "url"
+	case "domain":
+		return "domain-name"
+	case "ip_address":
+		if i.IsIPv6() {
+			return "ipv6-addr"
+		}
+		return "ipv4-addr"
+	}
+	return i.Type
+}
+
+func ecsTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+// ecsDocument builds the ECS document for an indicator. Fields without a value
+// are omitted.
+func ecsDocument(i *indicator) map[string]interface{} {
+	ind := map[string]interface{}{
+		"type":          ecsIndicatorType(i),
+		"provider":      vendor,
+		"reference":     i.Permalink(),
+		"scanner_stats": i.Malicious,
+	}
+
+	set := func(m map[string]interface{}, key string, value interface{}) {
+		switch v := value.(type) {
+		case nil:
+			return
+		case string:
+			if v == "" {
+				return
+			}
+		case int64:
+			if v == 0 {
+				return
+			}
+		case []string:
+			if len(v) == 0 {
+				return
+			}
+		case map[string]interface{}:
+			if len(v) == 0 {
+				return
+			}
+		}
+		m[key] = value
+	}
+
+	set(ind, "first_seen", ecsTime(i.FirstSeen))
+	set(ind, "last_seen", ecsTime(i.LastSeen))
+	set(ind, "modified_at", ecsTime(i.Modified))
+
+	switch i.Type {
+	case "file":
+		hash := map[string]interface{}{}
+		set(hash, "md5", i.MD5)
+		set(hash, "sha1", i.SHA1)
+		set(hash, "sha256", i.SHA256)
+		file := map[string]interface{}{}
+		set(file, "hash", hash)
+		set(file, "name", i.FileName)
+		set(file, "type", i.FileType)
+		set(file, "size", i.FileSize)
+		set(ind, "file", file)
+	case "url":
+		url := map[string]interface{}{}
+		set(url, "full", i.URL)
+		set(url, "original", i.URL)
+		set(ind, "url", url)
+	case "domain":
+		ind["url"] = map[string]interface{}{"domain": i.Domain}
+	case "ip_address":
+		ind["ip"] = i.IP
+	}
+
+	if i.Total() > 0 {
+		ind["confidence"] = ecsConfidence(i)
+	}
+
+	threat := map[string]interface{}{"indicator": ind}
+
+	event := map[string]interface{}{
+		"kind":     "enrichment",
+		"category": []string{"threat"},
+		"type":     []string{"indicator"},
+		"module":   "virustotal",
+		"dataset":  "virustotal.object",
+		"severity": i.Severity(),
+	}
+
+	vtFields := map[string]interface{}{
+		"id":   i.ID,
+		"type": i.Type,
+		"last_analysis_stats": map[string]interface{}{
+			"malicious":  i.Malicious,
+			"suspicious": i.Suspicious,
+			"harmless":   i.Harmless,
+			"undetected": i.Undetected,
+		},
+	}
+	set(vtFields, "reputation", i.Reputation)
+	set(vtFields, "final_url", i.FinalURL)
+
+	if i.NotificationID != "" || !i.NotificationDate.IsZero() {
+		event["dataset"] = "virustotal.ioc_stream"
+		set(event, "id", i.NotificationID)
+		set(event, "created", ecsTime(i.NotificationDate))
+		threat["feed"] = map[string]interface{}{"name": "VirusTotal IoC Stream"}
+		set(vtFields, "origin", i.Origin)
+		set(vtFields, "sources", i.SourceNames())
+	}
+
+	doc := map[string]interface{}{
+		"@timestamp": i.Timestamp().Format(time.RFC3339),
+		"ecs":        map[string]interface{}{"version": ecsVersion},
+		"event":      event,
+		"threat":     threat,
+		"virustotal": vtFields,
+	}
+	set(doc, "tags", i.Tags)
+
+	return doc
+}
+
+// ecsConfidence maps the proportion of engines flagging the indicator as
+// malicious to the values accepted by threat.indicator.confidence.
+func ecsConfidence(i *indicator) string {
+	switch sev := i.Severity(); {
+	case sev == 0:
+		return "None"
+	case sev <= 3:
+		return "Low"
+	case sev <= 6:
+		return "Medium"
+	default:
+		return "High"
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package siem
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECS(t *testing.T) {
+	b := new(bytes.Buffer)
+	err := NewECSEncoder(b).Encode([]map[string]interface{}{fileObject, ipNotification})
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 2)
+
+	var doc struct {
+		Timestamp string `json:"@timestamp"`
+		Event     struct {
+			Kind    string `json:"kind"`
+			Dataset string `json:"dataset"`
+		} `json:"event"`
+		Threat struct {
+			Indicator struct {
+				Type string `json:"type"`
+				IP   string `json:"ip"`
+				File struct {
+					Hash map[string]string `json:"hash"`
+					Name string            `json:"name"`
+				} `json:"file"`
+				ScannerStats int    `json:"scanner_stats"`
+				FirstSeen    string `json:"first_seen"`
+			} `json:"indicator"`
+		} `json:"threat"`
+	}
+
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &doc))
+	assert.Equal(t, "2023-11-14T22:13:20Z", doc.Timestamp)
+	assert.Equal(t, "enrichment", doc.Event.Kind)
+	assert.Equal(t, "virustotal.object", doc.Event.Dataset)
+	assert.Equal(t, "file", doc.Threat.Indicator.Type)
+	assert.Equal(t, "eicar|test=1.com", doc.Threat.Indicator.File.Name)
+	assert.Equal(t, "3395856ce81f2b7382dee72602f798b642f14140", doc.Threat.Indicator.File.Hash["sha1"])
+	assert.Equal(t, 60, doc.Threat.Indicator.ScannerStats)
+	assert.Equal(t, "2006-05-22T12:42:02Z", doc.Threat.Indicator.FirstSeen)
+
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &doc))
+	assert.Equal(t, "virustotal.ioc_stream", doc.Event.Dataset)
+	assert.Equal(t, "ipv4-addr", doc.Threat.Indicator.Type)
+	assert.Equal(t, "8.8.8.8", doc.Threat.Indicator.IP)
+}
@@ -0,0 +1,387 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package siem implements encoders that write VirusTotal objects in formats
+// understood by SIEM platforms: Elastic Common Schema (ECS) documents, ArcSight
+// Common Event Format (CEF) and IBM QRadar Log Event Extended Format (LEEF).
+//
+// The encoders receive the same maps that are passed to the YAML, JSON and CSV
+// encoders, this is, maps where the keys are attribute names plus the special
+// keys _id, _type and _context_attributes. Each map is written as a single line
+// so the output can be streamed straight into a log shipper.
+package siem
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	vendor  = "VirusTotal"
+	guiBase = "https://www.virustotal.com/gui"
+)
+
+// EncoderOption represents an option for creating a new encoder.
+type EncoderOption func(*options)
+
+type options struct {
+	product string
+	version string
+}
+
+// EncoderProduct sets the product name and version that identify the device
+// generating the events. They are used in the headers of CEF and LEEF records.
+func EncoderProduct(product, version string) EncoderOption {
+	return func(o *options) {
+		o.product = product
+		o.version = version
+	}
+}
+
+func newOptions(opts []EncoderOption) *options {
+	o := &options{product: "vt-cli"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// source describes the origin of an IoC Stream notification, like a hunting
+// ruleset or a collection.
+type source struct {
+	Type  string
+	ID    string
+	Label string
+}
+
+func (s source) String() string {
+	if s.Label != "" {
+		return fmt.Sprintf("%s:%s", s.Type, s.Label)
+	}
+	return fmt.Sprintf("%s:%s", s.Type, s.ID)
+}
+
+// indicator contains the fields extracted from a VirusTotal object that are
+// relevant for a SIEM, independently of the output format.
+type indicator struct {
+	ID   string
+	Type string
+
+	MD5      string
+	SHA1     string
+	SHA256   string
+	FileName string
+	FileType string
+	FileSize int64
+
+	URL      string
+	FinalURL string
+	Domain   string
+	IP       string
+
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Modified  time.Time
+
+	Malicious  int64
+	Suspicious int64
+	Harmless   int64
+	Undetected int64
+	Reputation int64
+
+	Tags []string
+
+	// Fields populated only for IoC Stream notifications.
+	NotificationID   string
+	NotificationDate time.Time
+	Origin           string
+	Sources          []source
+}
+
+// Timestamp returns the time at which the event represented by the indicator
+// occurred. For IoC Stream notifications this is the notification date, for
+// any other object is the most recent date known for the object.
+func (i *indicator) Timestamp() time.Time {
+	for _, t := range []time.Time{i.NotificationDate, i.LastSeen, i.Modified, i.FirstSeen} {
+		if !t.IsZero() {
+			return t
+		}
+	}
+	return time.Now().UTC()
+}
+
+// Total returns the number of engines that produced a verdict.
+func (i *indicator) Total() int64 {
+	return i.Malicious + i.Suspicious + i.Harmless + i.Undetected
+}
+
+// Severity returns a value between 0 and 10 proportional to the number of
+// engines that flagged the indicator as malicious.
+func (i *indicator) Severity() int {
+	total := i.Total()
+	if total == 0 {
+		return 0
+	}
+	sev := int((10*i.Malicious + total - 1) / total)
+	if sev == 0 && i.Suspicious > 0 {
+		sev = 1
+	}
+	return sev
+}
+
+// Permalink returns the URL for the indicator in the VirusTotal web interface.
+func (i *indicator) Permalink() string {
+	switch i.Type {
+	case "file":
+		return fmt.Sprintf("%s/file/%s", guiBase, i.ID)
+	case "url":
+		return fmt.Sprintf("%s/url/%s", guiBase, i.ID)
+	case "domain":
+		return fmt.Sprintf("%s/domain/%s", guiBase, i.ID)
+	case "ip_address":
+		return fmt.Sprintf("%s/ip-address/%s", guiBase, i.ID)
+	}
+	return ""
+}
+
+// IsIPv6 returns true if the indicator is an IPv6 address.
+func (i *indicator) IsIPv6() bool {
+	ip := net.ParseIP(i.IP)
+	return ip != nil && ip.To4() == nil
+}
+
+// SourceNames returns the sources of an IoC Stream notification as strings.
+func (i *indicator) SourceNames() []string {
+	names := make([]string, len(i.Sources))
+	for j, s := range i.Sources {
+		names[j] = s.String()
+	}
+	return names
+}
+
+// newIndicator extracts an indicator from a map like the ones produced by
+// utils.ObjectToMap.
+func newIndicator(m map[string]interface{}) *indicator {
+	i := &indicator{
+		ID:   toString(m["_id"]),
+		Type: toString(m["_type"]),
+	}
+
+	i.FirstSeen = firstTime(m, "first_submission_date", "creation_date")
+	i.LastSeen = firstTime(m, "last_analysis_date", "last_submission_date")
+	i.Modified = toTime(m["last_modification_date"])
+	i.Reputation = toInt64(m["reputation"])
+	i.Tags = toStringSlice(m["tags"])
+
+	if stats, ok := m["last_analysis_stats"].(map[string]interface{}); ok {
+		i.Malicious = toInt64(stats["malicious"])
+		i.Suspicious = toInt64(stats["suspicious"])
+		i.Harmless = toInt64(stats["harmless"])
+		i.Undetected = toInt64(stats["undetected"])
+	}
+
+	switch i.Type {
+	case "file":
+		i.MD5 = toString(m["md5"])
+		i.SHA1 = toString(m["sha1"])
+		i.SHA256 = toString(m["sha256"])
+		if i.SHA256 == "" && len(i.ID) == 64 {
+			i.SHA256 = i.ID
+		}
+		i.FileName = toString(m["meaningful_name"])
+		i.FileType = toString(m["type_description"])
+		i.FileSize = toInt64(m["size"])
+	case "url":
+		i.URL = toString(m["url"])
+		i.FinalURL = toString(m["last_final_url"])
+		if i.URL == "" && !isSHA256(i.ID) {
+			// URL identifiers provided by the user are the URL encoded in
+			// base64, if that's the case the URL can be recovered. The ones
+			// returned by the API are the SHA-256 of the URL, which can't.
+			if b, err := base64.RawURLEncoding.DecodeString(i.ID); err == nil {
+				i.URL = string(b)
+			}
+		}
+	case "domain":
+		i.Domain = i.ID
+	case "ip_address":
+		i.IP = i.ID
+	}
+
+	if ctx, ok := m["_context_attributes"].(map[string]interface{}); ok {
+		i.NotificationID = toString(ctx["notification_id"])
+		i.NotificationDate = toTime(ctx["notification_date"])
+		i.Origin = toString(ctx["origin"])
+		i.Tags = appendUnique(i.Tags, toStringSlice(ctx["tags"])...)
+		if sources, ok := ctx["sources"].([]interface{}); ok {
+			for _, s := range sources {
+				if sm, ok := s.(map[string]interface{}); ok {
+					i.Sources = append(i.Sources, source{
+						Type:  toString(sm["type"]),
+						ID:    toString(sm["id"]),
+						Label: toString(sm["label"]),
+					})
+				}
+			}
+		}
+	}
+
+	return i
+}
+
+// forEachMap calls fn for every map contained in v. v can be either a single
+// map or a slice of maps. Values that are not maps produce an error, as they
+// can't be converted into SIEM events.
+func forEachMap(v interface{}, fn func(map[string]interface{}) error) error {
+	if v == nil {
+		return nil
+	}
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Slice:
+		for j := 0; j < val.Len(); j++ {
+			if err := forEachMap(val.Index(j).Interface(), fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			return fn(m)
+		}
+	}
+	return fmt.Errorf("can't encode %T as a SIEM event, full objects are required", v)
+}
+
+// isSHA256 returns true if s is a SHA-256 hex digest.
+func isSHA256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeLine writes s followed by a line break to w.
+func writeLine(w io.Writer, s string) error {
+	_, err := io.WriteString(w, s+"\n")
+	return err
+}
+
+func firstTime(m map[string]interface{}, keys ...string) time.Time {
+	for _, k := range keys {
+		if t := toTime(m[k]); !t.IsZero() {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	default:
+		return fmt.Sprintf("%v", s)
+	}
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		f, _ := n.Float64()
+		return int64(f)
+	case float64:
+		return int64(n)
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	}
+	return 0
+}
+
+func toTime(v interface{}) time.Time {
+	if ts := toInt64(v); ts > 0 {
+		return time.Unix(ts, 0).UTC()
+	}
+	return time.Time{}
+}
+
+func toStringSlice(v interface{}) []string {
+	var result []string
+	switch s := v.(type) {
+	case []string:
+		result = append(result, s...)
+	case []interface{}:
+		for _, item := range s {
+			if item != nil {
+				result = append(result, toString(item))
+			}
+		}
+	}
+	return result
+}
+
+func appendUnique(s []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range s {
+			if existing == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s = append(s, item)
+		}
+	}
+	return s
+}
+
+// sortedKeys returns the keys of m in alphabetical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escaper replaces the characters in a string that have special meaning in
+// some format by their escaped versions.
+func escaper(oldnew ...string) func(string) string {
+	r := strings.NewReplacer(oldnew...)
+	return r.Replace
+}
@@ -0,0 +1,117 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package siem
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fileObject = map[string]interface{}{
+	"_id":                   "275a021bbfb6489e54d471899f7db9d1663fc695ec2fe2a2c4538aabf651fd0f",
+	"_type":                 "file",
+	"md5":                   "44d88612fea8a8f36de82e1278abb02f",
+	"sha1":                  "3395856ce81f2b7382dee72602f798b642f14140",
+	"sha256":                "275a021bbfb6489e54d471899f7db9d1663fc695ec2fe2a2c4538aabf651fd0f",
+	"meaningful_name":       "eicar|test=1.com",
+	"type_description":      "DOS COM",
+	"size":                  json.Number("68"),
+	"first_submission_date": json.Number("1148301722"),
+	"last_analysis_date":    json.Number("1700000000"),
+	"tags":                  []interface{}{"known-distributor"},
+	"last_analysis_stats": map[string]interface{}{
+		"malicious":  json.Number("60"),
+		"suspicious": json.Number("0"),
+		"harmless":   json.Number("0"),
+		"undetected": json.Number("15"),
+	},
+}
+
+var ipNotification = map[string]interface{}{
+	"_id":   "8.8.8.8",
+	"_type": "ip_address",
+	"_context_attributes": map[string]interface{}{
+		"notification_id":   "1234",
+		"notification_date": json.Number("1700000100"),
+		"origin":            "hunting",
+		"tags":              []interface{}{"my_rule"},
+		"sources": []interface{}{
+			map[string]interface{}{"type": "hunting_ruleset", "id": "42", "label": "My ruleset"},
+		},
+	},
+}
+
+func TestNewIndicator(t *testing.T) {
+	i := newIndicator(fileObject)
+	assert.Equal(t, "file", i.Type)
+	assert.Equal(t, "44d88612fea8a8f36de82e1278abb02f", i.MD5)
+	assert.Equal(t, int64(68), i.FileSize)
+	assert.Equal(t, int64(75), i.Total())
+	assert.Equal(t, 8, i.Severity())
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), i.Timestamp())
+
+	i = newIndicator(ipNotification)
+	assert.Equal(t, "8.8.8.8", i.IP)
+	assert.Equal(t, "hunting", i.Origin)
+	assert.Equal(t, []string{"my_rule"}, i.Tags)
+	assert.Equal(t, []string{"hunting_ruleset:My ruleset"}, i.SourceNames())
+	assert.Equal(t, time.Unix(1700000100, 0).UTC(), i.Timestamp())
+	assert.Equal(t, 0, i.Severity())
+}
+
+func TestNewIndicatorURL(t *testing.T) {
+	tests := []struct {
+		name string
+		m    map[string]interface{}
+		url  string
+	}{
+		{
+			name: "url attribute",
+			m: map[string]interface{}{
+				"_id":   "ab5b5d6e8dba0d8f9d0e6c1a1c1d9e2a3b4c5d6e7f8091a2b3c4d5e6f708192a",
+				"_type": "url",
+				"url":   "http://example.com/",
+			},
+			url: "http://example.com/",
+		},
+		{
+			name: "base64 identifier",
+			m:    map[string]interface{}{"_id": "aHR0cDovL2V4YW1wbGUuY29tLw", "_type": "url"},
+			url:  "http://example.com/",
+		},
+		{
+			name: "SHA-256 identifier",
+			m: map[string]interface{}{
+				"_id":   "ab5b5d6e8dba0d8f9d0e6c1a1c1d9e2a3b4c5d6e7f8091a2b3c4d5e6f708192a",
+				"_type": "url",
+			},
+			url: "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.url, newIndicator(test.m).URL)
+		})
+	}
+}
+
+func TestForEachMapRejectsIdentifiers(t *testing.T) {
+	err := forEachMap([]string{"8.8.8.8"}, func(map[string]interface{}) error {
+		return nil
+	})
+	assert.Error(t, err)
+}
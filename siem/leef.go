@@ -0,0 +1,117 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package siem
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LEEF 1.0 uses tabs for separating attributes. Header fields can't contain
+// pipes, and attribute values can't contain tabs or line breaks.
+var (
+	leefHeaderEscape    = escaper(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ", "\t", " ")
+	leefAttributeEscape = escaper(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+)
+
+// LEEFEncoder writes objects as IBM QRadar Log Event Extended Format (LEEF)
+// 1.0 records, one record per line.
+type LEEFEncoder struct {
+	w    io.Writer
+	opts *options
+}
+
+// NewLEEFEncoder returns a new LEEF encoder that writes to w.
+func NewLEEFEncoder(w io.Writer, opts ...EncoderOption) *LEEFEncoder {
+	return &LEEFEncoder{w: w, opts: newOptions(opts)}
+}
+
+// Encode writes the LEEF encoding of v to the stream. v must be a map or a
+// slice of maps.
+func (enc *LEEFEncoder) Encode(v interface{}) error {
+	return forEachMap(v, func(m map[string]interface{}) error {
+		return writeLine(enc.w, enc.record(newIndicator(m)))
+	})
+}
+
+func (enc *LEEFEncoder) record(i *indicator) string {
+	header := []string{
+		"LEEF:1.0",
+		leefHeaderEscape(vendor),
+		leefHeaderEscape(enc.opts.product),
+		leefHeaderEscape(enc.opts.version),
+		leefHeaderEscape(i.Type),
+	}
+
+	attrs := map[string]string{
+		"cat":     i.Type,
+		"sev":     strconv.Itoa(i.Severity()),
+		"devTime": strconv.FormatInt(i.Timestamp().UnixMilli(), 10),
+		"vtId":    i.ID,
+		"vtLink":  i.Permalink(),
+	}
+
+	add := func(key, value string) {
+		if value != "" {
+			attrs[key] = value
+		}
+	}
+
+	switch i.Type {
+	case "file":
+		add("md5", i.MD5)
+		add("sha1", i.SHA1)
+		add("sha256", i.SHA256)
+		add("fileName", i.FileName)
+		add("fileType", i.FileType)
+		if i.FileSize > 0 {
+			add("fileSize", strconv.FormatInt(i.FileSize, 10))
+		}
+	case "url":
+		add("url", i.URL)
+	case "domain":
+		add("domain", i.Domain)
+	case "ip_address":
+		if i.IsIPv6() {
+			add("dstv6", i.IP)
+		} else {
+			add("dst", i.IP)
+		}
+	}
+
+	if i.Total() > 0 {
+		attrs["malicious"] = strconv.FormatInt(i.Malicious, 10)
+		attrs["suspicious"] = strconv.FormatInt(i.Suspicious, 10)
+		attrs["engines"] = strconv.FormatInt(i.Total(), 10)
+	}
+	add("tags", strings.Join(i.Tags, ","))
+	add("origin", i.Origin)
+	add("sources", strings.Join(i.SourceNames(), ","))
+	add("notificationId", i.NotificationID)
+	if !i.FirstSeen.IsZero() {
+		attrs["firstSeen"] = strconv.FormatInt(i.FirstSeen.UnixMilli(), 10)
+	}
+	if !i.LastSeen.IsZero() {
+		attrs["lastSeen"] = strconv.FormatInt(i.LastSeen.UnixMilli(), 10)
+	}
+
+	pairs := make([]string, 0, len(attrs))
+	for _, k := range sortedKeys(attrs) {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", k, leefAttributeEscape(attrs[k])))
+	}
+
+	return strings.Join(header, "|") + "|" + strings.Join(pairs, "\t")
+}
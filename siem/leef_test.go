@@ -0,0 +1,41 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package siem
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLEEF(t *testing.T) {
+	b := new(bytes.Buffer)
+	err := NewLEEFEncoder(b, EncoderProduct("vt-cli", "1.0")).Encode(ipNotification)
+	assert.NoError(t, err)
+
+	line := strings.TrimSuffix(b.String(), "\n")
+	assert.True(t, strings.HasPrefix(line, "LEEF:1.0|VirusTotal|vt-cli|1.0|ip_address|"), line)
+
+	attrs := strings.Split(line[strings.LastIndex(line, "|")+1:], "\t")
+	assert.Contains(t, attrs, "dst=8.8.8.8")
+	assert.Contains(t, attrs, "origin=hunting")
+	assert.Contains(t, attrs, "sources=hunting_ruleset:My ruleset")
+	assert.Contains(t, attrs, "devTime=1700000100000")
+}
+
+func TestLEEFAttributeEscape(t *testing.T) {
+	assert.Equal(t, `a\tb\nc\\d`, leefAttributeEscape("a\tb\nc\\d"))
+}
@@ -0,0 +1,261 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AttackTactic is a MITRE ATT&CK tactic.
+type AttackTactic struct {
+	ID   string
+	Name string
+	// ShortName is the identifier used for the tactic in ATT&CK Navigator
+	// layers.
+	ShortName string
+}
+
+// AttackTactics contains the tactics in the MITRE ATT&CK Enterprise matrix,
+// in the same order they appear in the matrix.
+var AttackTactics = []AttackTactic{
+	{"TA0043", "Reconnaissance", "reconnaissance"},
+	{"TA0042", "Resource Development", "resource-development"},
+	{"TA0001", "Initial Access", "initial-access"},
+	{"TA0002", "Execution", "execution"},
+	{"TA0003", "Persistence", "persistence"},
+	{"TA0004", "Privilege Escalation", "privilege-escalation"},
+	{"TA0005", "Defense Evasion", "defense-evasion"},
+	{"TA0006", "Credential Access", "credential-access"},
+	{"TA0007", "Discovery", "discovery"},
+	{"TA0008", "Lateral Movement", "lateral-movement"},
+	{"TA0009", "Collection", "collection"},
+	{"TA0011", "Command and Control", "command-and-control"},
+	{"TA0010", "Exfiltration", "exfiltration"},
+	{"TA0040", "Impact", "impact"},
+}
+
+// AttackTacticName returns the name of the tactic with the given ID, or the
+// ID itself if the tactic is unknown.
+func AttackTacticName(id string) string {
+	for _, t := range AttackTactics {
+		if t.ID == id {
+			return t.Name
+		}
+	}
+	return id
+}
+
+// AttackTacticOrder returns the position of a tactic in the matrix. Unknown
+// tactics go last.
+func AttackTacticOrder(name string) int {
+	for i, t := range AttackTactics {
+		if t.Name == name {
+			return i
+		}
+	}
+	return len(AttackTactics)
+}
+
+// AttackTacticShortName returns the Navigator identifier for the tactic with
+// the given name.
+func AttackTacticShortName(name string) string {
+	for _, t := range AttackTactics {
+		if t.Name == name {
+			return t.ShortName
+		}
+	}
+	return strings.ReplaceAll(strings.ToLower(name), " ", "-")
+}
+
+// MitreTrees is the structure returned by the files/{id}/behaviour_mitre_trees
+// endpoint. Keys are sandbox names.
+type MitreTrees map[string]struct {
+	Tactics []struct {
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+		Techniques []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"techniques"`
+	} `json:"tactics"`
+}
+
+// TechniqueUsage is a technique observed under a given tactic, together with
+// the samples where it was observed.
+type TechniqueUsage struct {
+	Tactic  string
+	ID      string
+	Name    string
+	Samples []string
+}
+
+// AttackMatrix contains the techniques observed in a set of samples. The zero
+// value is an empty matrix ready to use.
+type AttackMatrix struct {
+	Samples    int
+	Techniques []*TechniqueUsage
+	// index maps tactic/technique keys to the entries in Techniques.
+	index map[string]*TechniqueUsage
+}
+
+// Add adds the techniques in the MITRE trees of a sample to the matrix.
+func (m *AttackMatrix) Add(sample string, trees MitreTrees) {
+	if m.index == nil {
+		m.index = make(map[string]*TechniqueUsage)
+	}
+	// The same technique can be reported by multiple sandboxes, but each
+	// sample must be counted only once.
+	seen := make(map[string]bool)
+	for _, tree := range trees {
+		for _, tactic := range tree.Tactics {
+			tacticName := AttackTacticName(tactic.ID)
+			if tacticName == tactic.ID && tactic.Name != "" {
+				tacticName = tactic.Name
+			}
+			for _, technique := range tactic.Techniques {
+				key := tacticName + "/" + technique.ID
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				u, ok := m.index[key]
+				if !ok {
+					u = &TechniqueUsage{Tactic: tacticName, ID: technique.ID, Name: technique.Name}
+					m.index[key] = u
+					m.Techniques = append(m.Techniques, u)
+				}
+				u.Samples = append(u.Samples, sample)
+			}
+		}
+	}
+	m.Samples++
+}
+
+// Sort sorts the techniques in the matrix by tactic, in the order the tactics
+// appear in the ATT&CK matrix, and then by number of samples. Samples are
+// sorted too, as they are added in the order the API responses arrive.
+func (m *AttackMatrix) Sort() {
+	for _, u := range m.Techniques {
+		sort.Strings(u.Samples)
+	}
+	sort.SliceStable(m.Techniques, func(i, j int) bool {
+		a, b := m.Techniques[i], m.Techniques[j]
+		if oa, ob := AttackTacticOrder(a.Tactic), AttackTacticOrder(b.Tactic); oa != ob {
+			return oa < ob
+		}
+		if a.Tactic != b.Tactic {
+			return a.Tactic < b.Tactic
+		}
+		if len(a.Samples) != len(b.Samples) {
+			return len(a.Samples) > len(b.Samples)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// Rows returns the matrix as a list of maps, one per technique and tactic.
+func (m *AttackMatrix) Rows() []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, len(m.Techniques))
+	for _, u := range m.Techniques {
+		rows = append(rows, map[string]interface{}{
+			"tactic":    u.Tactic,
+			"technique": u.ID,
+			"name":      u.Name,
+			"samples":   len(u.Samples),
+		})
+	}
+	return rows
+}
+
+// NavigatorLayer is an ATT&CK Navigator layer, as described in
+// https://github.com/mitre-attack/attack-navigator/tree/master/layers.
+type NavigatorLayer struct {
+	Name        string                 `json:"name"`
+	Versions    map[string]string      `json:"versions"`
+	Domain      string                 `json:"domain"`
+	Description string                 `json:"description"`
+	Techniques  []NavigatorTechnique   `json:"techniques"`
+	Gradient    NavigatorGradient      `json:"gradient"`
+	LegendItems []interface{}          `json:"legendItems"`
+	Metadata    []NavigatorMetadata    `json:"metadata"`
+	Layout      map[string]interface{} `json:"layout"`
+}
+
+// NavigatorTechnique is a technique in a NavigatorLayer.
+type NavigatorTechnique struct {
+	TechniqueID string              `json:"techniqueID"`
+	Tactic      string              `json:"tactic"`
+	Score       int                 `json:"score"`
+	Comment     string              `json:"comment"`
+	Enabled     bool                `json:"enabled"`
+	Metadata    []NavigatorMetadata `json:"metadata"`
+}
+
+// NavigatorGradient is the color gradient used for the scores in a
+// NavigatorLayer.
+type NavigatorGradient struct {
+	Colors   []string `json:"colors"`
+	MinValue int      `json:"minValue"`
+	MaxValue int      `json:"maxValue"`
+}
+
+// NavigatorMetadata is a name/value pair attached to a NavigatorLayer or to
+// any of its techniques.
+type NavigatorMetadata struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NavigatorLayer returns the matrix as an ATT&CK Navigator layer where the
+// score of each technique is the number of samples where it was observed.
+func (m *AttackMatrix) NavigatorLayer(name string) *NavigatorLayer {
+	layer := &NavigatorLayer{
+		Name: name,
+		Versions: map[string]string{
+			"attack":    "16",
+			"navigator": "5.1.0",
+			"layer":     "4.5",
+		},
+		Domain: "enterprise-attack",
+		Description: fmt.Sprintf(
+			"Techniques observed in the sandbox behaviours of %d samples", m.Samples),
+		Techniques: make([]NavigatorTechnique, 0, len(m.Techniques)),
+		Gradient: NavigatorGradient{
+			Colors:   []string{"#ffe766", "#ff6666"},
+			MinValue: 1,
+			MaxValue: m.Samples,
+		},
+		LegendItems: []interface{}{},
+		Metadata: []NavigatorMetadata{
+			{Name: "generated_by", Value: "vt-cli"},
+		},
+		Layout: map[string]interface{}{"layout": "side", "showName": true},
+	}
+	for _, u := range m.Techniques {
+		t := NavigatorTechnique{
+			TechniqueID: u.ID,
+			Tactic:      AttackTacticShortName(u.Tactic),
+			Score:       len(u.Samples),
+			Comment:     fmt.Sprintf("Observed in %d of %d samples", len(u.Samples), m.Samples),
+			Enabled:     true,
+		}
+		for _, sample := range u.Samples {
+			t.Metadata = append(t.Metadata, NavigatorMetadata{Name: "sample", Value: sample})
+		}
+		layer.Techniques = append(layer.Techniques, t)
+	}
+	return layer
+}
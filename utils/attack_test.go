@@ -0,0 +1,111 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMitreTrees(t *testing.T, data string) utils.MitreTrees {
+	var trees utils.MitreTrees
+	assert.NoError(t, json.Unmarshal([]byte(data), &trees))
+	return trees
+}
+
+func newAttackMatrix(t *testing.T) *utils.AttackMatrix {
+	m := &utils.AttackMatrix{}
+	// Both sandboxes report T1059 under Execution for sample "b", but it must
+	// be counted once.
+	m.Add("b", newMitreTrees(t, `{
+		"zenbox": {"tactics": [
+			{"id": "TA0002", "name": "Execution", "techniques": [
+				{"id": "T1059", "name": "Command and Scripting Interpreter"}]},
+			{"id": "TA0005", "name": "Defense Evasion", "techniques": [
+				{"id": "T1027", "name": "Obfuscated Files or Information"}]}
+		]},
+		"cape": {"tactics": [
+			{"id": "TA0002", "name": "Execution", "techniques": [
+				{"id": "T1059", "name": "Command and Scripting Interpreter"}]}
+		]}
+	}`))
+	m.Add("a", newMitreTrees(t, `{
+		"zenbox": {"tactics": [
+			{"id": "TA0005", "name": "Defense Evasion", "techniques": [
+				{"id": "T1027", "name": "Obfuscated Files or Information"}]},
+			{"id": "TA9999", "name": "Brand New Tactic", "techniques": [
+				{"id": "T9999", "name": "Something"}]},
+			{"id": "TA0002", "name": "Execution", "techniques": [
+				{"id": "T1106", "name": "Native API"},
+				{"id": "T1059", "name": "Command and Scripting Interpreter"}]}
+		]}
+	}`))
+	m.Add("c", utils.MitreTrees{})
+	m.Sort()
+	return m
+}
+
+func Test_AttackMatrix(t *testing.T) {
+	m := newAttackMatrix(t)
+	assert.Equal(t, 3, m.Samples)
+	assert.Equal(t, []*utils.TechniqueUsage{
+		{Tactic: "Execution", ID: "T1059", Name: "Command and Scripting Interpreter", Samples: []string{"a", "b"}},
+		{Tactic: "Execution", ID: "T1106", Name: "Native API", Samples: []string{"a"}},
+		{Tactic: "Defense Evasion", ID: "T1027", Name: "Obfuscated Files or Information", Samples: []string{"a", "b"}},
+		{Tactic: "Brand New Tactic", ID: "T9999", Name: "Something", Samples: []string{"a"}},
+	}, m.Techniques)
+}
+
+func Test_AttackMatrixRows(t *testing.T) {
+	assert.Empty(t, (&utils.AttackMatrix{}).Rows())
+	rows := newAttackMatrix(t).Rows()
+	assert.Len(t, rows, 4)
+	assert.Equal(t, map[string]interface{}{
+		"tactic":    "Execution",
+		"technique": "T1059",
+		"name":      "Command and Scripting Interpreter",
+		"samples":   2,
+	}, rows[0])
+}
+
+func Test_AttackMatrixNavigatorLayer(t *testing.T) {
+	layer := newAttackMatrix(t).NavigatorLayer("Incident 42")
+	assert.Equal(t, "Incident 42", layer.Name)
+	assert.Equal(t, "enterprise-attack", layer.Domain)
+	assert.Equal(t, 3, layer.Gradient.MaxValue)
+	assert.Len(t, layer.Techniques, 4)
+	assert.Equal(t, utils.NavigatorTechnique{
+		TechniqueID: "T1059",
+		Tactic:      "execution",
+		Score:       2,
+		Comment:     "Observed in 2 of 3 samples",
+		Enabled:     true,
+		Metadata: []utils.NavigatorMetadata{
+			{Name: "sample", Value: "a"},
+			{Name: "sample", Value: "b"},
+		},
+	}, layer.Techniques[0])
+	assert.Equal(t, "defense-evasion", layer.Techniques[2].Tactic)
+	// Tactics not in the matrix get a short name derived from their name.
+	assert.Equal(t, "brand-new-tactic", layer.Techniques[3].Tactic)
+
+	// The layer is serialized with the field names expected by Navigator.
+	b, err := json.Marshal(layer)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"techniqueID":"T1059"`)
+	assert.Contains(t, string(b), `"legendItems":[]`)
+}
@@ -0,0 +1,157 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// CertPivotMethods are the ways of finding hosts related to a certificate,
+// from the most to the least specific:
+//
+//	thumbprint      hosts presenting the same certificate.
+//	issuer_subject  hosts presenting a certificate with the same issuer and
+//	                subject.
+//	jarm            hosts with the same JARM fingerprint, which identifies the
+//	                TLS server configuration.
+var CertPivotMethods = []string{"thumbprint", "issuer_subject", "jarm"}
+
+// CertSource is what certificate pivots start from: a certificate and,
+// when the certificate comes from a domain or IP address, the JARM
+// fingerprint of the host.
+type CertSource struct {
+	// Host is the domain or IP address the certificate comes from, if any.
+	Host       string
+	Thumbprint string
+	Subject    string
+	Issuer     string
+	JARM       string
+}
+
+// certName returns the common name in the subject or issuer of a
+// certificate, or the organization if there's no common name.
+func certName(obj *vt.Object, attr string) string {
+	if s, _ := obj.GetString(attr + ".CN"); s != "" {
+		return s
+	}
+	s, _ := obj.GetString(attr + ".O")
+	return s
+}
+
+// NewCertSourceFromHost returns the source for the last HTTPS certificate of
+// a domain or IP address object. It returns an error if the host doesn't have
+// a certificate nor a JARM fingerprint.
+func NewCertSourceFromHost(obj *vt.Object) (*CertSource, error) {
+	s := &CertSource{Host: obj.ID()}
+	s.Thumbprint, _ = obj.GetString("last_https_certificate.thumbprint_sha256")
+	s.Subject = certName(obj, "last_https_certificate.subject")
+	s.Issuer = certName(obj, "last_https_certificate.issuer")
+	s.JARM, _ = obj.GetString("jarm")
+	if s.Thumbprint == "" && s.JARM == "" {
+		return nil, fmt.Errorf("%s has no HTTPS certificate nor JARM fingerprint", obj.ID())
+	}
+	return s, nil
+}
+
+// NewCertSourceFromCert returns the source for an SSL certificate object.
+func NewCertSourceFromCert(obj *vt.Object) *CertSource {
+	return &CertSource{
+		Thumbprint: obj.ID(),
+		Subject:    certName(obj, "subject"),
+		Issuer:     certName(obj, "issuer"),
+	}
+}
+
+// Queries returns the VirusTotal Intelligence search query for each of the
+// given methods the source has the data for, indexed by method name. The
+// queries must be combined with an entity:domain or entity:ip modifier.
+func (s *CertSource) Queries(methods []string) map[string]string {
+	quote := func(v string) string { return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"` }
+	queries := make(map[string]string)
+	for _, m := range methods {
+		switch {
+		case m == "thumbprint" && s.Thumbprint != "":
+			queries[m] = "ssl_thumbprint:" + quote(s.Thumbprint)
+		case m == "issuer_subject" && s.Issuer != "" && s.Subject != "":
+			queries[m] = "ssl_issuer:" + quote(s.Issuer) + " ssl_subject:" + quote(s.Subject)
+		case m == "jarm" && s.JARM != "":
+			queries[m] = "jarm:" + quote(s.JARM)
+		}
+	}
+	return queries
+}
+
+// CertHost is a domain or IP address found by one or more pivots.
+type CertHost struct {
+	Object *vt.Object
+	// Methods are the names of the methods that found the host, in the same
+	// order they have in CertPivotMethods.
+	Methods []string
+}
+
+// CertHostSet merges the hosts found by different pivot methods.
+type CertHostSet struct {
+	source *CertSource
+	hosts  map[string]*CertHost
+}
+
+// NewCertHostSet returns an empty set of hosts related to the source. The
+// source host itself is never added to the set.
+func NewCertHostSet(source *CertSource) *CertHostSet {
+	return &CertHostSet{source: source, hosts: make(map[string]*CertHost)}
+}
+
+// Add adds a host found by the given method.
+func (s *CertHostSet) Add(method string, obj *vt.Object) {
+	if obj.ID() == s.source.Host {
+		return
+	}
+	h, ok := s.hosts[obj.ID()]
+	if !ok {
+		h = &CertHost{Object: obj}
+		s.hosts[obj.ID()] = h
+	}
+	if slices.Contains(h.Methods, method) {
+		return
+	}
+	h.Methods = append(h.Methods, method)
+	sort.Slice(h.Methods, func(i, j int) bool {
+		return slices.Index(CertPivotMethods, h.Methods[i]) < slices.Index(CertPivotMethods, h.Methods[j])
+	})
+}
+
+// Hosts returns the hosts in the set, the ones found by more methods first,
+// then the ones found by more specific methods, and then by ID.
+func (s *CertHostSet) Hosts() []*CertHost {
+	hosts := make([]*CertHost, 0, len(s.hosts))
+	for _, h := range s.hosts {
+		hosts = append(hosts, h)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		a, b := hosts[i], hosts[j]
+		if len(a.Methods) != len(b.Methods) {
+			return len(a.Methods) > len(b.Methods)
+		}
+		if ma, mb := slices.Index(CertPivotMethods, a.Methods[0]), slices.Index(CertPivotMethods, b.Methods[0]); ma != mb {
+			return ma < mb
+		}
+		return a.Object.ID() < b.Object.ID()
+	})
+	return hosts
+}
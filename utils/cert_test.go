@@ -0,0 +1,75 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CertSource(t *testing.T) {
+	domain := newObject(t, "domain", "example.com", `{
+		"jarm": "29d29d00029d29d00042d43d00041d",
+		"last_https_certificate": {
+			"thumbprint_sha256": "abcd",
+			"subject": {"CN": "example.com"},
+			"issuer": {"O": "Acme \"Trust\""}
+		}
+	}`)
+	s, err := utils.NewCertSourceFromHost(domain)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"thumbprint":     `ssl_thumbprint:"abcd"`,
+		"issuer_subject": `ssl_issuer:"Acme \"Trust\"" ssl_subject:"example.com"`,
+		"jarm":           `jarm:"29d29d00029d29d00042d43d00041d"`,
+	}, s.Queries(utils.CertPivotMethods))
+	assert.Equal(t, map[string]string{
+		"jarm": `jarm:"29d29d00029d29d00042d43d00041d"`,
+	}, s.Queries([]string{"jarm"}))
+
+	_, err = utils.NewCertSourceFromHost(newObject(t, "ip_address", "1.2.3.4", `{}`))
+	assert.Error(t, err)
+
+	cert := newObject(t, "ssl_cert", "abcd", `{"subject": {"CN": "example.com"}, "issuer": {"CN": "R3"}}`)
+	assert.Equal(t, map[string]string{
+		"thumbprint":     `ssl_thumbprint:"abcd"`,
+		"issuer_subject": `ssl_issuer:"R3" ssl_subject:"example.com"`,
+	}, utils.NewCertSourceFromCert(cert).Queries(utils.CertPivotMethods))
+}
+
+func Test_CertHostSet(t *testing.T) {
+	s := utils.NewCertHostSet(&utils.CertSource{Host: "example.com"})
+	a := newObject(t, "domain", "a.com", `{}`)
+	b := newObject(t, "ip_address", "1.2.3.4", `{}`)
+	c := newObject(t, "domain", "c.com", `{}`)
+	d := newObject(t, "domain", "d.com", `{}`)
+
+	s.Add("jarm", a)
+	s.Add("thumbprint", a)
+	s.Add("jarm", a)
+	s.Add("jarm", b)
+	s.Add("issuer_subject", d)
+	s.Add("issuer_subject", c)
+	s.Add("thumbprint", newObject(t, "domain", "example.com", `{}`))
+
+	hosts := s.Hosts()
+	ids := make([]string, len(hosts))
+	for i, h := range hosts {
+		ids[i] = h.Object.ID()
+	}
+	assert.Equal(t, []string{"a.com", "c.com", "d.com", "1.2.3.4"}, ids)
+	assert.Equal(t, []string{"thumbprint", "jarm"}, hosts[0].Methods)
+}
@@ -14,32 +14,50 @@
 package utils
 
 import (
-	"container/heap"
+	"context"
 	"errors"
-	"fmt"
-	"os"
-	"sync"
+	"net/http"
 
+	"github.com/VirusTotal/vt-cli/pkg/vtcli"
 	vt "github.com/VirusTotal/vt-go"
-	"github.com/spf13/viper"
 )
 
 // APIClient represents a VirusTotal API client.
 type APIClient struct {
 	*vt.Client
+	httpClient *http.Client
+	threads    int
+	unordered  bool
 }
 
-// NewAPIClient returns a new VirusTotal API client using the API key configured
-// either using the program configuration file or the --apikey command-line flag.
-func NewAPIClient(agent string) (*APIClient, error) {
-	apikey := viper.GetString("apikey")
-	if apikey == "" {
+// NewAPIClient returns a new VirusTotal API client using the API key and
+// proxy in the given options. The API key is configured either using the
+// program configuration file or the --apikey command-line flag.
+func NewAPIClient(agent string, opts *Options) (*APIClient, error) {
+	if opts.APIKey == "" {
 		return nil, errors.New(
 			"An API key is needed. Either use the --apikey flag or run \"vt init\" to set up your API key")
 	}
-	c := vt.NewClient(apikey)
+	httpClient, err := opts.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	c := vt.NewClient(opts.APIKey, vt.WithHTTPClient(httpClient))
 	c.Agent = agent
-	return &APIClient{c}, nil
+	return &APIClient{
+		Client:     c,
+		httpClient: httpClient,
+		threads:    opts.Threads,
+		unordered:  opts.Unordered,
+	}, nil
+}
+
+// Wrapped returns a vtcli.Client that shares the underlying vt.Client and
+// settings with this client.
+func (c *APIClient) Wrapped() *vtcli.Client {
+	return vtcli.WrapClient(c.Client,
+		vtcli.WithThreads(c.threads),
+		vtcli.WithHTTPClient(c.httpClient))
 }
 
 // RetrieveObjects retrieves objects from the specified endpoint. The endpoint
@@ -53,102 +71,84 @@ func (c *APIClient) RetrieveObjects(endpoint string, args []string, outCh chan *
 // tries the endpoints in the order they are provided until one of them returns
 // the object. The endpoint strings must contain a %s placeholder that will be
 // replaced with items from the args slice. The objects are put into the outCh
-// as they are retrieved.
+// as they are retrieved. The number of objects retrieved in parallel is the
+// number of threads in the options used for creating the client.
 func (c *APIClient) RetrieveObjectsWithFallback(endpoints []string, args []string, outCh chan *vt.Object, errCh chan error) error {
+	return c.RetrieveObjectsFromReader(endpoints, NewStringArrayReader(args), outCh, errCh)
+}
+
+// RetrieveObjectsFromReader is like RetrieveObjectsWithFallback, but the
+// identifiers are read from r as they are needed. At most
+// vtcli.DefaultBatchWindow identifiers are in flight at any time, so the
+// memory used doesn't depend on the number of identifiers, but the caller
+// must receive from outCh and errCh concurrently. If the client was created
+// with the --unordered option objects are put into outCh as soon as they are
+// retrieved, instead of in the same order the identifiers have in r. Objects
+// that don't exist are reported through errCh, any other error stops the
+// retrieval and is returned, as well as the errors returned by r.
+func (c *APIClient) RetrieveObjectsFromReader(endpoints []string, r StringReader, outCh chan *vt.Object, errCh chan error) error {
 
 	// Make sure outCh and errCh are closed
 	defer close(outCh)
 	defer close(errCh)
 
-	h := PQueue{}
-	heap.Init(&h)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	objCh := make(chan PQueueNode)
-	getWg := &sync.WaitGroup{}
+	ids, readErr := StringChannel(ctx, r)
+	if err := sendResults(cancel, c.Wrapped().BatchStream(ctx, endpoints, ids, c.batchOptions()...), outCh, errCh); err != nil {
+		return err
+	}
+	return readErr()
+}
 
-	// Channel used for limiting the number of parallel goroutines
-	threads := viper.GetInt("threads")
+// RetrieveFilesFromReader retrieves files from the hashes read from r, like
+// RetrieveObjectsFromReader does with the "files/%s" endpoint, but using
+// VirusTotal Intelligence searches that include many hashes at once instead
+// of one request per file. See vtcli.Client.BatchFilesStream for details.
+func (c *APIClient) RetrieveFilesFromReader(r StringReader, outCh chan *vt.Object, errCh chan error) error {
 
-	if threads == 0 {
-		panic("RetrieveObjects called with 0 threads")
+	// Make sure outCh and errCh are closed
+	defer close(outCh)
+	defer close(errCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hashes, readErr := StringChannel(ctx, r)
+	if err := sendResults(cancel, c.Wrapped().BatchFilesStream(ctx, hashes, false, c.batchOptions()...), outCh, errCh); err != nil {
+		return err
 	}
+	return readErr()
+}
 
-	throttler := make(chan interface{}, threads)
-
-	// Read object IDs from the input channel, launch goroutines to retrieve the
-	// objects and send them through objCh together with a number indicating
-	// their order in the input. As goroutines run in parallel the objects can
-	// be sent out of order to objCh, but the order number is used to reorder
-	// them.
-	for order, arg := range args {
-		getWg.Add(1)
-		go func(order int, arg string) {
-			throttler <- nil
-			var obj *vt.Object
-			var err error
-			for _, endpoint := range endpoints {
-				obj, err = c.GetObject(vt.URL(endpoint, arg))
-				if err == nil {
-					objCh <- PQueueNode{Priority: order, Data: obj}
-					break
-				}
-				if apiErr, ok := err.(vt.Error); ok && apiErr.Code == "NotFoundError" {
-					// Try the next endpoint
-				} else {
-					fmt.Fprintln(os.Stderr, err)
-					os.Exit(1)
-				}
-			}
-			if err != nil {
-				objCh <- PQueueNode{Priority: order, Data: err}
-			}
-			getWg.Done()
-			<-throttler
-		}(order, arg)
+// batchOptions returns the options for vtcli.Client.BatchStream and
+// vtcli.Client.BatchFilesStream that correspond to the client's settings.
+func (c *APIClient) batchOptions() []vtcli.BatchOption {
+	var opts []vtcli.BatchOption
+	if c.unordered {
+		opts = append(opts, vtcli.Unordered())
 	}
+	return opts
+}
 
-	outWg := &sync.WaitGroup{}
-	outWg.Add(1)
-
-	// Read objects from objCh, put them into a priority queue and send them in
-	// their original order to outCh.
-	go func() {
-		order := 0
-		for p := range objCh {
-			heap.Push(&h, p)
-			// If the object in the top of the queue is the next one in the order
-			// it can be sent to outCh and removed from the queue, if not, we keep
-			// pushing objects into the queue.
-			if h[0].Priority == order {
-				if obj, ok := h[0].Data.(*vt.Object); ok {
-					outCh <- obj
-				} else {
-					errCh <- h[0].Data.(error)
-				}
-				heap.Pop(&h)
-				order++
-			}
+// sendResults sends the objects in the results to outCh, and the not found
+// errors to errCh. Any other error is returned after calling cancel and
+// draining the results, no more objects are sent after it.
+func sendResults(cancel func(), results <-chan vtcli.BatchResult, outCh chan *vt.Object, errCh chan error) error {
+	var err error
+	for r := range results {
+		if err != nil {
+			continue
 		}
-		// Send to outCh any object remaining in the queue
-		for h.Len() > 0 {
-			item := heap.Pop(&h).(PQueueNode).Data
-			if obj, ok := item.(*vt.Object); ok {
-				outCh <- obj
-			} else {
-				errCh <- item.(error)
-			}
+		if r.Err == nil {
+			outCh <- r.Object
+		} else if vtcli.IsNotFound(r.Err) {
+			errCh <- r.Err
+		} else {
+			err = r.Err
+			cancel()
 		}
-		outWg.Done()
-	}()
-
-	// Wait for all objects to be retrieved
-	getWg.Wait()
-
-	// Once all object were retrieved is safe to close objCh.
-	close(objCh)
-
-	// Wait for objects to be sent to outCh
-	outWg.Wait()
-
-	return nil
+	}
+	return err
 }
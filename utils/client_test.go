@@ -0,0 +1,78 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RetrieveObjectsFromReader(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		id := strings.TrimPrefix(r.URL.Path, "/api/v3/domains/")
+		switch id {
+		case "missing.com":
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]string{"code": "NotFoundError", "message": "not found"}})
+		case "forbidden.com":
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": map[string]string{"code": "ForbiddenError", "message": "forbidden"}})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{"type": "domain", "id": id}})
+		}
+	}))
+	vt.SetHost(ts.URL)
+	defer func() {
+		ts.Close()
+		vt.SetHost("https://www.virustotal.com")
+	}()
+
+	client, err := utils.NewAPIClient("test", &utils.Options{APIKey: "apikey", Threads: 1})
+	assert.NoError(t, err)
+
+	// Objects that don't exist are reported through the errors channel, any
+	// other error stops the retrieval and is returned.
+	r := utils.NewStringArrayReader([]string{
+		"example.com", "missing.com", "forbidden.com", "other.com"})
+	objectsCh := make(chan *vt.Object)
+	errorsCh := make(chan error, 4)
+	done := make(chan error)
+	go func() {
+		done <- client.RetrieveObjectsFromReader([]string{"domains/%s"}, r, objectsCh, errorsCh)
+	}()
+	var ids []string
+	for obj := range objectsCh {
+		ids = append(ids, obj.ID())
+	}
+	var errs []error
+	for err := range errorsCh {
+		errs = append(errs, err)
+	}
+	assert.EqualError(t, <-done, "forbidden")
+	assert.Equal(t, []string{"example.com"}, ids)
+	if assert.Len(t, errs, 1) {
+		assert.EqualError(t, errs[0], "not found")
+	}
+}
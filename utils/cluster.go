@@ -0,0 +1,333 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/VirusTotal/vt-cli/hashes"
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// ClusterFeatures are the features by which files can be clustered. Files
+// with the same value for any of them are put in the same cluster, except
+// for tlsh, for which the hashes must be closer than a threshold.
+var ClusterFeatures = []string{
+	"imphash", "vhash", "signer", "rich_pe_header_hash", "icon_dhash", "yara", "tlsh",
+}
+
+// clusterRepresentatives is the maximum number of representatives of a
+// cluster.
+const clusterRepresentatives = 3
+
+// featureValues returns the values of a feature for a file.
+func featureValues(obj *vt.Object, feature string) []string {
+	get := func(attr string) []string {
+		if s, err := obj.GetString(attr); err == nil && s != "" {
+			return []string{s}
+		}
+		return nil
+	}
+	switch feature {
+	case "imphash":
+		return get("pe_info.imphash")
+	case "vhash":
+		return get("vhash")
+	case "rich_pe_header_hash":
+		return get("pe_info.rich_pe_header_hash")
+	case "icon_dhash":
+		return get("main_icon.dhash")
+	case "signer":
+		// The signers are the certificate chain separated by semicolons, the
+		// first one is the signer of the file.
+		if s := get("signature_info.signers"); s != nil {
+			if signer := strings.TrimSpace(strings.Split(s[0], ";")[0]); signer != "" {
+				return []string{signer}
+			}
+		}
+	case "yara":
+		results, _ := obj.Get("crowdsourced_yara_results")
+		list, _ := results.([]interface{})
+		var rules []string
+		for _, r := range list {
+			if m, ok := r.(map[string]interface{}); ok {
+				if name, ok := m["rule_name"].(string); ok && name != "" {
+					rules = append(rules, name)
+				}
+			}
+		}
+		return rules
+	case "tlsh":
+		return get("tlsh")
+	}
+	return nil
+}
+
+// SharedFeature is a feature value shared by files in a cluster.
+type SharedFeature struct {
+	Feature string `json:"feature" yaml:"feature" csv:"feature"`
+	Value   string `json:"value" yaml:"value" csv:"value"`
+	// Count is the number of files in the cluster with the value.
+	Count int `json:"count" yaml:"count" csv:"count"`
+}
+
+// Cluster is a group of files linked by shared features.
+type Cluster struct {
+	ID   int `json:"id" yaml:"id" csv:"id"`
+	Size int `json:"size" yaml:"size" csv:"size"`
+	// Representatives are the files linked to more files in the cluster.
+	Representatives []string        `json:"representatives" yaml:"representatives" csv:"representatives"`
+	Shared          []SharedFeature `json:"shared" yaml:"shared" csv:"shared"`
+	Members         []string        `json:"members" yaml:"members" csv:"members"`
+}
+
+// tlshLink links two files with close TLSH hashes.
+type tlshLink struct {
+	a, b     int
+	distance int
+}
+
+// Clusterer groups files by the features they share. Files are linked if
+// they have a feature value in common, and clusters are the groups of files
+// connected by links, directly or through other files.
+type Clusterer struct {
+	features      []string
+	tlshThreshold int
+	objs          []*vt.Object
+	// values are the values of each feature for each file.
+	values    []map[string][]string
+	tlshLinks []tlshLink
+}
+
+// NewClusterer returns a clusterer that uses the given features, which must
+// be in ClusterFeatures. Files with a TLSH distance lower or equal than
+// tlshThreshold are linked if tlsh is one of the features.
+func NewClusterer(features []string, tlshThreshold int) (*Clusterer, error) {
+	for _, f := range features {
+		known := false
+		for _, k := range ClusterFeatures {
+			known = known || f == k
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown feature %q, valid features are: %s",
+				f, strings.Join(ClusterFeatures, ", "))
+		}
+	}
+	return &Clusterer{features: features, tlshThreshold: tlshThreshold}, nil
+}
+
+// Add adds a file to the clusterer.
+func (c *Clusterer) Add(obj *vt.Object) {
+	values := make(map[string][]string)
+	for _, f := range c.features {
+		if v := featureValues(obj, f); len(v) > 0 {
+			values[f] = v
+		}
+	}
+	if h, ok := values["tlsh"]; ok {
+		delete(values, "tlsh")
+		for i, other := range c.objs {
+			h2, err := other.GetString("tlsh")
+			if err != nil {
+				continue
+			}
+			if d, err := hashes.DiffTLSH(h[0], h2); err == nil && d <= c.tlshThreshold {
+				c.tlshLinks = append(c.tlshLinks, tlshLink{a: i, b: len(c.objs), distance: d})
+			}
+		}
+	}
+	c.objs = append(c.objs, obj)
+	c.values = append(c.values, values)
+}
+
+// valueKey identifies a feature value.
+type valueKey struct{ feature, value string }
+
+// owners returns the files that have each feature value.
+func (c *Clusterer) owners() map[valueKey][]int {
+	owners := make(map[valueKey][]int)
+	for i, values := range c.values {
+		for f, vs := range values {
+			for _, v := range vs {
+				k := valueKey{f, v}
+				// A file can match the same YARA rule more than once.
+				if n := len(owners[k]); n == 0 || owners[k][n-1] != i {
+					owners[k] = append(owners[k], i)
+				}
+			}
+		}
+	}
+	return owners
+}
+
+// Clusters returns the clusters, from the largest to the smallest. Files that
+// don't share any feature with others are clusters of size 1.
+func (c *Clusterer) Clusters() []*Cluster {
+	parent := make([]int, len(c.objs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) { parent[find(a)] = find(b) }
+
+	// degree is the number of links of each file, used for choosing the
+	// representatives.
+	degree := make([]int, len(c.objs))
+	owners := c.owners()
+	for _, files := range owners {
+		for _, i := range files {
+			union(files[0], i)
+			degree[i] += len(files) - 1
+		}
+	}
+	for _, l := range c.tlshLinks {
+		union(l.a, l.b)
+		degree[l.a]++
+		degree[l.b]++
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := range c.objs {
+		r := find(i)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], i)
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		return len(groups[roots[i]]) > len(groups[roots[j]])
+	})
+
+	clusters := make([]*Cluster, len(roots))
+	for n, r := range roots {
+		files := groups[r]
+		cl := &Cluster{ID: n + 1, Size: len(files)}
+		for _, i := range files {
+			cl.Members = append(cl.Members, c.objs[i].ID())
+		}
+		reps := append([]int{}, files...)
+		sort.SliceStable(reps, func(i, j int) bool { return degree[reps[i]] > degree[reps[j]] })
+		for _, i := range reps[:min(len(reps), clusterRepresentatives)] {
+			cl.Representatives = append(cl.Representatives, c.objs[i].ID())
+		}
+		cl.Shared = c.shared(files, owners)
+		clusters[n] = cl
+	}
+	return clusters
+}
+
+// shared returns the feature values shared by more than one of the files,
+// sorted by the number of files that share them.
+func (c *Clusterer) shared(files []int, owners map[valueKey][]int) []SharedFeature {
+	shared := []SharedFeature{}
+	if len(files) < 2 {
+		return shared
+	}
+	in := make(map[int]bool)
+	for _, i := range files {
+		in[i] = true
+	}
+	for k, o := range owners {
+		if len(o) > 1 && in[o[0]] {
+			shared = append(shared, SharedFeature{Feature: k.feature, Value: k.value, Count: len(o)})
+		}
+	}
+	linked := make(map[int]bool)
+	for _, l := range c.tlshLinks {
+		if in[l.a] {
+			linked[l.a], linked[l.b] = true, true
+		}
+	}
+	if len(linked) > 0 {
+		shared = append(shared, SharedFeature{
+			Feature: "tlsh",
+			Value:   fmt.Sprintf("distance <= %d", c.tlshThreshold),
+			Count:   len(linked),
+		})
+	}
+	order := make(map[string]int)
+	for i, f := range ClusterFeatures {
+		order[f] = i
+	}
+	sort.Slice(shared, func(i, j int) bool {
+		a, b := shared[i], shared[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Feature != b.Feature {
+			return order[a.Feature] < order[b.Feature]
+		}
+		return a.Value < b.Value
+	})
+	return shared
+}
+
+// dotQuote quotes a string as a Graphviz ID.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// WriteDOT writes the given clusters, which must have been returned by
+// Clusters, as a Graphviz graph. Each cluster is a subgraph where files are
+// connected to the feature values they share, and files with close TLSH
+// hashes are connected to each other.
+func (c *Clusterer) WriteDOT(w io.Writer, clusters []*Cluster) error {
+	index := make(map[string]int)
+	for i, obj := range c.objs {
+		index[obj.ID()] = i
+	}
+	owners := c.owners()
+	var b strings.Builder
+	b.WriteString("graph clusters {\n")
+	b.WriteString("  node [shape=box, fontsize=10];\n")
+	for _, cl := range clusters {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", cl.ID)
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(fmt.Sprintf("cluster %d (%d files)", cl.ID, cl.Size)))
+		in := make(map[int]bool)
+		for _, id := range cl.Members {
+			in[index[id]] = true
+			fmt.Fprintf(&b, "    %s;\n", dotQuote(id))
+		}
+		for _, s := range cl.Shared {
+			if s.Feature == "tlsh" {
+				continue
+			}
+			node := dotQuote(s.Feature + ":" + s.Value)
+			fmt.Fprintf(&b, "    %s [shape=ellipse, label=%s];\n", node, dotQuote(s.Feature+"\n"+s.Value))
+			for _, i := range owners[valueKey{s.Feature, s.Value}] {
+				fmt.Fprintf(&b, "    %s -- %s;\n", dotQuote(c.objs[i].ID()), node)
+			}
+		}
+		for _, l := range c.tlshLinks {
+			if in[l.a] {
+				fmt.Fprintf(&b, "    %s -- %s [style=dashed, label=\"tlsh %d\"];\n",
+					dotQuote(c.objs[l.a].ID()), dotQuote(c.objs[l.b].ID()), l.distance)
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
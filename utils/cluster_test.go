@@ -0,0 +1,81 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tlshA = "T1A4A002B5A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2"
+	tlshB = "T1A4A002B5A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A3"
+	tlshC = "T15F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F5F"
+)
+
+func Test_Clusterer(t *testing.T) {
+	_, err := utils.NewClusterer([]string{"imphash", "color"}, 0)
+	assert.Error(t, err)
+
+	c, err := utils.NewClusterer(utils.ClusterFeatures, 10)
+	assert.NoError(t, err)
+	c.Add(newObject(t, "file", "a", `{"pe_info": {"imphash": "i1"}, "signature_info": {"signers": "Acme Ltd; CA; Root"}}`))
+	c.Add(newObject(t, "file", "b", `{"pe_info": {"imphash": "i1"}, "crowdsourced_yara_results": [{"rule_name": "r1"}, {"rule_name": "r1"}]}`))
+	c.Add(newObject(t, "file", "c", `{"crowdsourced_yara_results": [{"rule_name": "r1"}]}`))
+	c.Add(newObject(t, "file", "d", `{"signature_info": {"signers": "Other Inc"}, "tlsh": "`+tlshA+`"}`))
+	c.Add(newObject(t, "file", "e", `{"tlsh": "`+tlshB+`"}`))
+	c.Add(newObject(t, "file", "f", `{"tlsh": "`+tlshC+`", "signature_info": {"signers": "Acme Ltd"}}`))
+	c.Add(newObject(t, "file", "g", `{}`))
+	c.Add(newObject(t, "file", "h", `{"crowdsourced_yara_results": [{"rule_name": "r1"}]}`))
+
+	clusters := c.Clusters()
+	assert.Len(t, clusters, 3)
+
+	assert.Equal(t, 1, clusters[0].ID)
+	assert.Equal(t, []string{"a", "b", "c", "f", "h"}, clusters[0].Members)
+	// b is linked to a by the imphash and to c and h by the YARA rule.
+	assert.Equal(t, []string{"b", "a", "c"}, clusters[0].Representatives)
+	assert.Equal(t, []utils.SharedFeature{
+		{Feature: "yara", Value: "r1", Count: 3},
+		{Feature: "imphash", Value: "i1", Count: 2},
+		{Feature: "signer", Value: "Acme Ltd", Count: 2},
+	}, clusters[0].Shared)
+
+	assert.Equal(t, []string{"d", "e"}, clusters[1].Members)
+	assert.Equal(t, []utils.SharedFeature{
+		{Feature: "tlsh", Value: "distance <= 10", Count: 2},
+	}, clusters[1].Shared)
+
+	assert.Equal(t, []string{"g"}, clusters[2].Members)
+	assert.Equal(t, []string{"g"}, clusters[2].Representatives)
+	assert.Empty(t, clusters[2].Shared)
+
+	var b bytes.Buffer
+	assert.NoError(t, c.WriteDOT(&b, clusters[:2]))
+	assert.Contains(t, b.String(), `"a" -- "imphash:i1";`)
+	assert.Contains(t, b.String(), `"signer:Acme Ltd" [shape=ellipse, label="signer\nAcme Ltd"];`)
+	assert.Contains(t, b.String(), `"d" -- "e" [style=dashed, label="tlsh 1"];`)
+	assert.NotContains(t, b.String(), `"g"`)
+}
+
+func Test_ClustererFeatures(t *testing.T) {
+	c, err := utils.NewClusterer([]string{"vhash"}, 100)
+	assert.NoError(t, err)
+	c.Add(newObject(t, "file", "a", `{"vhash": "v1", "pe_info": {"imphash": "i1"}, "tlsh": "`+tlshA+`"}`))
+	c.Add(newObject(t, "file", "b", `{"vhash": "v2", "pe_info": {"imphash": "i1"}, "tlsh": "`+tlshA+`"}`))
+	assert.Len(t, c.Clusters(), 2)
+}
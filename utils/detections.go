@@ -0,0 +1,173 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// RuleHit is a rule from a crowdsourced YARA ruleset, a Sigma rule or an IDS
+// rule that matched one or more files.
+type RuleHit struct {
+	Engine      string
+	Source      string
+	RulesetID   string
+	Ruleset     string
+	Rule        string
+	Severity    string
+	Description string
+	Files       []string
+}
+
+// key returns a string that identifies the rule, used for aggregating the
+// hits of the same rule in different files.
+func (h *RuleHit) key() string {
+	return strings.Join([]string{h.Engine, h.Source, h.RulesetID, h.Rule}, "\x00")
+}
+
+// ToMap returns the hit as a map, omitting the empty fields.
+func (h *RuleHit) ToMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"engine": h.Engine,
+		"rule":   h.Rule,
+		"files":  h.Files,
+	}
+	for k, v := range map[string]string{
+		"source":      h.Source,
+		"ruleset_id":  h.RulesetID,
+		"ruleset":     h.Ruleset,
+		"severity":    h.Severity,
+		"description": h.Description,
+	} {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	return m
+}
+
+// severityRank is used for sorting hits, most severe first. Crowdsourced YARA
+// rules don't have severity, so they go after the rest.
+var severityRank = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+	"info":     4,
+}
+
+func rankSeverity(s string) int {
+	if r, ok := severityRank[strings.ToLower(s)]; ok {
+		return r
+	}
+	return len(severityRank)
+}
+
+// DetectionEngines maps the names accepted by --engine to the file attribute
+// containing the results for that engine.
+var DetectionEngines = map[string]string{
+	"yara":  "crowdsourced_yara_results",
+	"sigma": "sigma_analysis_results",
+	"ids":   "crowdsourced_ids_results",
+}
+
+// ValidateDetectionEngines returns an error if any of the engines is not in
+// DetectionEngines.
+func ValidateDetectionEngines(engines []string) error {
+	for _, engine := range engines {
+		if _, ok := DetectionEngines[engine]; !ok {
+			return fmt.Errorf("unknown engine %q, valid engines are: yara, sigma, ids", engine)
+		}
+	}
+	return nil
+}
+
+// FileRuleHits returns the rule hits for a single file object.
+func FileRuleHits(obj *vt.Object, engines []string) []*RuleHit {
+	hits := make([]*RuleHit, 0)
+	for _, engine := range engines {
+		v, _ := obj.Get(DetectionEngines[engine])
+		results, _ := v.([]interface{})
+		for _, r := range results {
+			m, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			str := func(key string) string {
+				s, _ := m[key].(string)
+				return s
+			}
+			h := &RuleHit{Engine: engine, Files: []string{obj.ID()}}
+			switch engine {
+			case "yara":
+				h.Source = str("source")
+				h.RulesetID = str("ruleset_id")
+				h.Ruleset = str("ruleset_name")
+				h.Rule = str("rule_name")
+				h.Description = str("description")
+			case "sigma":
+				h.Source = str("rule_source")
+				h.RulesetID = str("rule_id")
+				h.Rule = str("rule_title")
+				h.Severity = str("rule_level")
+				h.Description = str("rule_description")
+			case "ids":
+				h.Source = str("rule_source")
+				h.RulesetID = str("rule_id")
+				h.Rule = str("rule_msg")
+				h.Severity = str("alert_severity")
+				h.Description = str("rule_category")
+			}
+			hits = append(hits, h)
+		}
+	}
+	return hits
+}
+
+// AggregateRuleHits merges the hits of the same rule, keeping the list of
+// files matched by each rule, and sorts them by severity and number of files.
+func AggregateRuleHits(hits []*RuleHit) []*RuleHit {
+	index := make(map[string]*RuleHit)
+	result := make([]*RuleHit, 0)
+	for _, h := range hits {
+		if existing, ok := index[h.key()]; ok {
+			for _, f := range h.Files {
+				if !containsFold(existing.Files, f) {
+					existing.Files = append(existing.Files, f)
+				}
+			}
+			continue
+		}
+		index[h.key()] = h
+		result = append(result, h)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if ra, rb := rankSeverity(a.Severity), rankSeverity(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if len(a.Files) != len(b.Files) {
+			return len(a.Files) > len(b.Files)
+		}
+		if a.Engine != b.Engine {
+			return a.Engine < b.Engine
+		}
+		return a.Rule < b.Rule
+	})
+	return result
+}
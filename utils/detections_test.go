@@ -0,0 +1,145 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const detectionsFixture = `{
+	"crowdsourced_yara_results": [{
+		"source": "https://github.com/Neo23x0/signature-base",
+		"ruleset_id": "000ba3f2ab",
+		"ruleset_name": "gen_mal_3cx",
+		"rule_name": "MAL_3CXDesktopApp_Mar23",
+		"description": "Detects 3CX malicious DLL"
+	}, "not a map"],
+	"sigma_analysis_results": [{
+		"rule_source": "Sigma Integrated Rule Set (GitHub)",
+		"rule_id": "c1f8a2b3",
+		"rule_title": "Suspicious Rundll32 Activity",
+		"rule_level": "high",
+		"rule_description": "Detects suspicious rundll32 usage"
+	}],
+	"crowdsourced_ids_results": [{
+		"rule_source": "Proofpoint Emerging Threats Open",
+		"rule_id": "1:2044765",
+		"rule_msg": "ET MALWARE 3CX Beacon",
+		"alert_severity": "critical",
+		"rule_category": "A Network Trojan was detected"
+	}]
+}`
+
+func Test_FileRuleHits(t *testing.T) {
+	obj := newObject(t, "file", "f1", detectionsFixture)
+	yara := &utils.RuleHit{
+		Engine:      "yara",
+		Source:      "https://github.com/Neo23x0/signature-base",
+		RulesetID:   "000ba3f2ab",
+		Ruleset:     "gen_mal_3cx",
+		Rule:        "MAL_3CXDesktopApp_Mar23",
+		Description: "Detects 3CX malicious DLL",
+		Files:       []string{"f1"},
+	}
+	sigma := &utils.RuleHit{
+		Engine:      "sigma",
+		Source:      "Sigma Integrated Rule Set (GitHub)",
+		RulesetID:   "c1f8a2b3",
+		Rule:        "Suspicious Rundll32 Activity",
+		Severity:    "high",
+		Description: "Detects suspicious rundll32 usage",
+		Files:       []string{"f1"},
+	}
+	ids := &utils.RuleHit{
+		Engine:      "ids",
+		Source:      "Proofpoint Emerging Threats Open",
+		RulesetID:   "1:2044765",
+		Rule:        "ET MALWARE 3CX Beacon",
+		Severity:    "critical",
+		Description: "A Network Trojan was detected",
+		Files:       []string{"f1"},
+	}
+	tests := []struct {
+		name    string
+		engines []string
+		want    []*utils.RuleHit
+	}{
+		{"all engines", []string{"yara", "sigma", "ids"}, []*utils.RuleHit{yara, sigma, ids}},
+		{"only sigma", []string{"sigma"}, []*utils.RuleHit{sigma}},
+		{"ids before yara", []string{"ids", "yara"}, []*utils.RuleHit{ids, yara}},
+		{"no engines", nil, []*utils.RuleHit{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, utils.FileRuleHits(obj, test.engines))
+		})
+	}
+
+	// Files without results don't produce hits.
+	empty := newObject(t, "file", "f2", `{}`)
+	assert.Empty(t, utils.FileRuleHits(empty, []string{"yara", "sigma", "ids"}))
+}
+
+func Test_AggregateRuleHits(t *testing.T) {
+	engines := []string{"yara", "sigma", "ids"}
+	hits := utils.FileRuleHits(newObject(t, "file", "f1", detectionsFixture), engines)
+	hits = append(hits, utils.FileRuleHits(newObject(t, "file", "f2", detectionsFixture), engines)...)
+	hits = append(hits, utils.FileRuleHits(newObject(t, "file", "f3", `{
+		"sigma_analysis_results": [
+			{"rule_title": "Low rule", "rule_level": "low"},
+			{"rule_title": "Suspicious Rundll32 Activity", "rule_level": "high",
+			 "rule_source": "Sigma Integrated Rule Set (GitHub)", "rule_id": "c1f8a2b3"}
+		]
+	}`), engines)...)
+	// The same file reported twice, with a different case.
+	hits = append(hits, utils.FileRuleHits(newObject(t, "file", "F1", detectionsFixture), []string{"ids"})...)
+
+	aggregated := utils.AggregateRuleHits(hits)
+	type summary struct {
+		Engine string
+		Rule   string
+		Files  []string
+	}
+	got := make([]summary, len(aggregated))
+	for i, h := range aggregated {
+		got[i] = summary{h.Engine, h.Rule, h.Files}
+	}
+	// Sorted by severity, YARA rules without severity last.
+	assert.Equal(t, []summary{
+		{"ids", "ET MALWARE 3CX Beacon", []string{"f1", "f2"}},
+		{"sigma", "Suspicious Rundll32 Activity", []string{"f1", "f2", "f3"}},
+		{"sigma", "Low rule", []string{"f3"}},
+		{"yara", "MAL_3CXDesktopApp_Mar23", []string{"f1", "f2"}},
+	}, got)
+}
+
+func Test_RuleHitToMap(t *testing.T) {
+	h := &utils.RuleHit{Engine: "sigma", Rule: "R", Severity: "low", Files: []string{"f"}}
+	assert.Equal(t, map[string]interface{}{
+		"engine":   "sigma",
+		"rule":     "R",
+		"severity": "low",
+		"files":    []string{"f"},
+	}, h.ToMap())
+}
+
+func Test_ValidateDetectionEngines(t *testing.T) {
+	assert.NoError(t, utils.ValidateDetectionEngines([]string{"yara", "sigma", "ids"}))
+	assert.NoError(t, utils.ValidateDetectionEngines(nil))
+	assert.EqualError(t, utils.ValidateDetectionEngines([]string{"sigma", "snort"}),
+		`unknown engine "snort", valid engines are: yara, sigma, ids`)
+}
@@ -14,15 +14,14 @@
 package utils
 
 import (
-	"fmt"
-	"github.com/fatih/color"
-	"sync"
+	"context"
+	"io"
 	"time"
 
+	"github.com/VirusTotal/vt-cli/coordinator"
 	vt "github.com/VirusTotal/vt-go"
 	"github.com/briandowns/spinner"
-	"github.com/plusvic/go-ansi"
-	"github.com/spf13/viper"
+	"github.com/fatih/color"
 )
 
 // Coordinator coordinates the work of multiple instances of a Doer that run
@@ -30,16 +29,15 @@ import (
 type Coordinator struct {
 	Threads int
 	Spinner *spinner.Spinner
-
-	printingWg *sync.WaitGroup
-	doerStates []DoerState
-	resultsCh  chan string
+	// Silent disables the progress indication.
+	Silent bool
+	// Output is where results are printed, the standard output if nil.
+	Output io.Writer
 }
 
-// DoerState represents the current state of a Doer.
-type DoerState struct {
-	Progress string
-}
+// DoerState represents the current state of a Doer. Doers report their
+// progress with SetProgress, which can be called from any goroutine.
+type DoerState = coordinator.State
 
 // Doer is the interface that must be implemented for any type to be used with
 // DoWithStringsFromReader and DoWithStringsFromChannel.
@@ -62,16 +60,23 @@ func (c *Coordinator) EnableSpinner() {
 // DoWithStringsFromReader calls the Do of a type implementing the Doer
 // interface with strings read from a StringReader. The doer's Do method is
 // called once for each string, and this function doesn't exit until the
-// StringReader returns an empty string.
-func (c *Coordinator) DoWithStringsFromReader(doer Doer, reader StringReader) {
+// StringReader returns an error. If the error is not io.EOF it's returned once
+// the strings read before it have been processed.
+func (c *Coordinator) DoWithStringsFromReader(doer Doer, reader StringReader) error {
 	ch := make(chan interface{})
+	var err error
 	go func() {
-		for s, err := reader.ReadString(); s != "" || err == nil; s, err = reader.ReadString() {
+		defer close(ch)
+		var s string
+		for s, err = reader.ReadString(); s != "" || err == nil; s, err = reader.ReadString() {
 			ch <- s
 		}
-		close(ch)
 	}()
 	c.DoWithItemsFromChannel(doer, ch)
+	if err != io.EOF {
+		return err
+	}
+	return nil
 }
 
 // DoWithObjectsFromIterator calls the Do of a type implementing the Doer
@@ -91,86 +96,28 @@ func (c *Coordinator) DoWithObjectsFromIterator(doer Doer, it *vt.Iterator, buff
 // DoWithItemsFromChannel calls the Do method of a type implementing the Doer
 // interface with items read from a channel. This function doesn't exit until
 // the channel is closed.
+//
+// This is an adapter on top of coordinator.Coordinator, new code should use
+// that package directly.
 func (c *Coordinator) DoWithItemsFromChannel(doer Doer, ch <-chan interface{}) {
-
-	c.resultsCh = make(chan string, c.Threads)
-	c.doerStates = make([]DoerState, c.Threads)
-	wg := &sync.WaitGroup{}
-
-	for i := 0; i < c.Threads; i++ {
-		wg.Add(1)
-		go func(i int) {
-			for arg := range ch {
-				c.resultsCh <- doer.Do(arg, &c.doerStates[i])
-				c.doerStates[i].Progress = ""
-			}
-			wg.Done()
-		}(i)
-	}
-
-	c.printingWg = &sync.WaitGroup{}
-	c.printingWg.Add(1)
-
 	// Use the NoColor flag from the color library as an indicator of whether
 	// or not stdout is a tty or a file. If NoColor is true it means that
 	// stdout is being redirected to a file and we don't want escape sequences
 	// in the output, in that case print only the final results from the doers,
 	// without any progress indication.
-	if color.NoColor || viper.GetBool("silent") {
-		go c.printResultsOnly()
-	} else {
-		go c.printProgressAndResults()
-	}
-
-	wg.Wait()
-	close(c.resultsCh)
-	c.printingWg.Wait()
-}
-
-func (c *Coordinator) printResultsOnly() {
-	for res := range c.resultsCh {
-		ansi.Println(res)
+	sink := coordinator.NewTerminalSink(
+		func(r coordinator.Result[interface{}, string]) string { return r.Output },
+		!color.NoColor && !c.Silent)
+	sink.Spinner = c.Spinner
+	if c.Output != nil {
+		sink.Out = c.Output
 	}
-	c.printingWg.Done()
-}
 
-func (c *Coordinator) printProgressAndResults() {
-Loop:
-	for {
-		if c.Spinner != nil {
-			c.Spinner.Start()
-		}
-		select {
-		case res, ok := <-c.resultsCh:
-			if !ok {
-				break Loop
-			}
-			if c.Spinner != nil {
-				c.Spinner.Stop()
-			}
-			ansi.Printf("%s", res)
-			ansi.EraseInLine(0) // Clear to the end of the line.
-			fmt.Println()
-		default:
-			// Print progress for pending workers
-			lines := 0
-			for _, ds := range c.doerStates {
-				if ds.Progress != "" {
-					ansi.Printf("%s", ds.Progress)
-					ansi.EraseInLine(0) // Clear to the end of the line.
-					fmt.Println()
-					lines++
-				}
-			}
-			time.Sleep(time.Millisecond * 250)
-			if lines > 0 {
-				// Move cursor up, to the line it was before printing worker's progress
-				ansi.CursorPreviousLine(lines)
-			}
-		}
-	}
-	if c.Spinner != nil {
-		c.Spinner.Stop()
-	}
-	c.printingWg.Done()
+	coordinator.New[interface{}, string](c.Threads, sink).Run(
+		context.Background(),
+		coordinator.DoerFunc[interface{}, string](
+			func(_ context.Context, item interface{}, ds *DoerState) (string, error) {
+				return doer.Do(item, ds), nil
+			}),
+		ch)
 }
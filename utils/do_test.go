@@ -0,0 +1,110 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDoer reports progress both from the worker goroutine and from a
+// separate goroutine, like the doers that upload files do, while the
+// coordinator is reading it.
+type fakeDoer struct {
+	duration time.Duration
+}
+
+func (d *fakeDoer) Do(item interface{}, ds *utils.DoerState) string {
+	ds.SetProgress("%s starting", item)
+	stop := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				ds.SetProgress("%s working %d", item, i)
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+	time.Sleep(d.duration)
+	close(stop)
+	wg.Wait()
+	ds.SetProgress("%s finishing", item)
+	return fmt.Sprintf("%s done", item)
+}
+
+func runCoordinator(t *testing.T, noColor bool, duration time.Duration, items []string) string {
+	oldNoColor := color.NoColor
+	color.NoColor = noColor
+	defer func() {
+		color.NoColor = oldNoColor
+	}()
+
+	var out bytes.Buffer
+	c := utils.NewCoordinator(3)
+	c.Output = &out
+	c.DoWithStringsFromReader(&fakeDoer{duration: duration}, utils.NewStringArrayReader(items))
+	return out.String()
+}
+
+func Test_Coordinator_NonTTY(t *testing.T) {
+	items := []string{"a", "b", "c", "d", "e", "f", "g"}
+	out := runCoordinator(t, true, 10*time.Millisecond, items)
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	assert.ElementsMatch(t,
+		[]string{"a done", "b done", "c done", "d done", "e done", "f done", "g done"},
+		lines)
+	// Without a terminal only the results are printed.
+	assert.NotContains(t, out, "\x1b[")
+	assert.NotContains(t, out, "working")
+}
+
+func Test_Coordinator_TTY(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	// Make the doers last long enough for the progress to be displayed at
+	// least once.
+	out := runCoordinator(t, false, 600*time.Millisecond, items)
+
+	for _, item := range items {
+		assert.Contains(t, out, fmt.Sprintf("%s done\x1b[0K\n", item))
+	}
+	assert.Contains(t, out, "working")
+	// Every time progress is printed the cursor is moved back up.
+	assert.Regexp(t, `\x1b\[\dF`, out)
+}
+
+func Test_Coordinator_ReadError(t *testing.T) {
+	var out bytes.Buffer
+	c := utils.NewCoordinator(3)
+	c.Output = &out
+	r := utils.NewCSVColumnReader(strings.NewReader("a\nb\"b\nc\n"), 0)
+	err := c.DoWithStringsFromReader(&fakeDoer{}, r)
+	assert.EqualError(t, err, `malformed record at line 2: bare " in non-quoted-field`)
+	// The strings read before the error are processed.
+	assert.Equal(t, "a done\n", out.String())
+}
@@ -0,0 +1,104 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultPorts maps URL schemes to the port used when none is specified.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ftp":   "21",
+}
+
+// NormalizeHash returns the hash in lowercase.
+func NormalizeHash(s string) string {
+	return strings.ToLower(s)
+}
+
+// NormalizeDomain returns the domain in lowercase, without trailing dots and
+// with internationalized labels converted to punycode. If the domain can't be
+// converted to punycode it's returned in lowercase and without trailing dots.
+func NormalizeDomain(s string) string {
+	s = strings.TrimRight(strings.ToLower(s), ".")
+	if ascii, err := idna.ToASCII(s); err == nil {
+		return ascii
+	}
+	return s
+}
+
+// NormalizeURL returns the canonical form of a URL, with the scheme and host
+// in lowercase, the host converted to punycode and without the port if it's
+// the default one for the scheme. The rest of the URL is kept as typed, as
+// escaping it differently could change the URL. Strings that are not absolute
+// URLs, like the URL identifiers returned by VirusTotal, are returned
+// unchanged.
+func NormalizeURL(s string) string {
+	scheme, rest, ok := strings.Cut(s, "://")
+	if !ok || !isURLScheme(scheme) {
+		return s
+	}
+	scheme = strings.ToLower(scheme)
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	authority, tail := rest[:end], rest[end:]
+	var userinfo string
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		userinfo, authority = authority[:i+1], authority[i+1:]
+	}
+	host, port := authority, ""
+	if i := strings.LastIndex(authority, ":"); i >= 0 && !strings.Contains(authority[i:], "]") {
+		host, port = authority[:i], authority[i+1:]
+	}
+	if host == "" {
+		return s
+	}
+	if port == defaultPorts[scheme] {
+		port = ""
+	}
+	// IPv6 addresses are left as they are, apart from the case.
+	if ip := strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"); net.ParseIP(ip) == nil {
+		host = NormalizeDomain(host)
+	} else {
+		host = strings.ToLower(host)
+	}
+	if port != "" {
+		host = host + ":" + port
+	}
+	return scheme + "://" + userinfo + host + tail
+}
+
+// isURLScheme returns true if s is a valid URL scheme, a letter followed by
+// letters, digits, plus signs, hyphens or dots.
+func isURLScheme(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+		case i > 0 && ('0' <= c && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
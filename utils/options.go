@@ -0,0 +1,225 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/VirusTotal/vt-cli/pkg/vtcli"
+	vt "github.com/VirusTotal/vt-go"
+	ansi "github.com/k0kubun/go-ansi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Options holds the settings for a single invocation of a command. They are
+// built once from the command-line flags, the environment and the config file
+// before the command runs, and passed down to the API client, the printer and
+// the coordinator. Nothing is stored in global state, so multiple commands can
+// run concurrently in the same process with different settings.
+type Options struct {
+	APIKey  string
+	Host    string
+	Proxy   string
+	Verbose bool
+	Silent  bool
+
+	Format string
+	// Include and Exclude are the patterns passed to --include and --exclude,
+	// they are nil when the corresponding flag was not used.
+	Include         []string
+	Exclude         []string
+	IdentifiersOnly bool
+
+	Threads int
+	// Unordered allows results to be printed in a different order than the
+	// one in which they were requested.
+	Unordered bool
+	Limit     int
+	All       bool
+	Cursor    string
+	Filter    string
+	Output    string
+
+	// Normalize, Dedupe, InputColumn and InputField control how the
+	// arguments read from Stdin are preprocessed, see NewInputReader.
+	Normalize   bool
+	Dedupe      bool
+	InputColumn int
+	InputField  string
+	// Strict makes commands fail if any of their arguments is invalid.
+	Strict bool
+
+	// Stdin is where arguments are read from when the command receives a
+	// single hyphen. It's the input set with the command's SetIn, or the
+	// standard input by default.
+	Stdin io.Reader
+
+	// Stdout is where the command writes its results. It's the output set
+	// with the command's SetOut, or the standard output by default.
+	Stdout io.Writer
+	// Stderr is where errors and diagnostics are written.
+	Stderr io.Writer
+
+	v *viper.Viper
+}
+
+// NewOptions returns the options for an invocation of cmd, after its flags
+// have been parsed. Flags explicitly set in the command line take precedence
+// over VTCLI_* environment variables, which in turn take precedence over the
+// settings in config. config can be nil.
+func NewOptions(cmd *cobra.Command, config *viper.Viper) (*Options, error) {
+	v := viper.New()
+	v.SetEnvPrefix("VTCLI")
+	v.AutomaticEnv()
+	if config != nil {
+		if err := v.MergeConfigMap(config.AllSettings()); err != nil {
+			return nil, err
+		}
+	}
+	if err := v.BindPFlags(cmd.Flags()); err != nil {
+		return nil, err
+	}
+	o := &Options{
+		APIKey:          v.GetString("apikey"),
+		Host:            v.GetString("host"),
+		Proxy:           v.GetString("proxy"),
+		Verbose:         v.GetBool("verbose"),
+		Silent:          v.GetBool("silent"),
+		Format:          v.GetString("format"),
+		IdentifiersOnly: v.GetBool("identifiers-only"),
+		Threads:         v.GetInt("threads"),
+		Unordered:       v.GetBool("unordered"),
+		Limit:           v.GetInt("limit"),
+		All:             v.GetBool("all"),
+		Cursor:          v.GetString("cursor"),
+		Filter:          v.GetString("filter"),
+		Output:          v.GetString("output"),
+		Normalize:       v.GetBool("normalize"),
+		Dedupe:          v.GetBool("dedupe"),
+		InputColumn:     v.GetInt("input-column"),
+		InputField:      v.GetString("input-csv-field"),
+		Strict:          v.GetBool("strict"),
+		Stdin:           cmd.InOrStdin(),
+		Stdout:          cmd.OutOrStdout(),
+		Stderr:          cmd.ErrOrStderr(),
+		v:               v,
+	}
+	// Unless the command was told to write somewhere else, results go to
+	// the standard output, with ANSI escape sequences converted on Windows.
+	if o.Stdout == os.Stdout {
+		o.Stdout = ansi.NewAnsiStdout()
+	}
+	if v.IsSet("include") || v.IsSet("exclude") {
+		o.Include = v.GetStringSlice("include")
+		o.Exclude = v.GetStringSlice("exclude")
+	}
+	// Commands without the --threads flag use the default number of threads.
+	if !v.IsSet("threads") && o.Threads == 0 {
+		o.Threads = vtcli.DefaultThreads
+	} else if o.Threads < 1 {
+		o.Threads = 1
+	}
+	return o, nil
+}
+
+type optionsKey struct{}
+
+// WithOptions returns a copy of ctx that carries the given options.
+func WithOptions(ctx context.Context, o *Options) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, optionsKey{}, o)
+}
+
+// CommandOptions returns the options for the current invocation of cmd. These
+// are the options stored in the command's context by the root command. If
+// there are none, they are built from the command's flags alone, and the
+// error returned by NewOptions, if any, is returned.
+func CommandOptions(cmd *cobra.Command) (*Options, error) {
+	if ctx := cmd.Context(); ctx != nil {
+		if o, ok := ctx.Value(optionsKey{}).(*Options); ok {
+			return o, nil
+		}
+	}
+	return NewOptions(cmd, nil)
+}
+
+// IsSet returns true if the setting with the given name was set, either in
+// the command line, the environment or the config file.
+func (o *Options) IsSet(name string) bool {
+	return o.v.IsSet(name)
+}
+
+// GetBool returns the value of a command-specific setting as a bool.
+func (o *Options) GetBool(name string) bool {
+	return o.v.GetBool(name)
+}
+
+// GetInt returns the value of a command-specific setting as an int.
+func (o *Options) GetInt(name string) int {
+	return o.v.GetInt(name)
+}
+
+// GetString returns the value of a command-specific setting as a string.
+func (o *Options) GetString(name string) string {
+	return o.v.GetString(name)
+}
+
+// GetDuration returns the value of a command-specific setting as a
+// time.Duration.
+func (o *Options) GetDuration(name string) time.Duration {
+	return o.v.GetDuration(name)
+}
+
+// GetStringSlice returns the value of a command-specific setting as a slice
+// of strings.
+func (o *Options) GetStringSlice(name string) []string {
+	return o.v.GetStringSlice(name)
+}
+
+// IteratorOptions returns the options for iterating over a collection
+// according to the --limit, --all, --cursor and --filter flags.
+func (o *Options) IteratorOptions() []vt.IteratorOption {
+	limit := o.Limit
+	if o.All {
+		limit = 0
+	}
+	return []vt.IteratorOption{
+		vt.IteratorLimit(limit),
+		vt.IteratorCursor(o.Cursor),
+		vt.IteratorFilter(o.Filter),
+	}
+}
+
+// HTTPClient returns the HTTP client used for talking with the API, which
+// goes through the proxy specified in the options, if any.
+func (o *Options) HTTPClient() (*http.Client, error) {
+	if o.Proxy == "" {
+		return &http.Client{}, nil
+	}
+	proxyURL, err := url.Parse(o.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,248 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCommand returns a command tree similar to the one created by
+// cmd.NewVTCommand, with a "print" subcommand that prints a map and reports
+// the options it received.
+func newTestCommand(config *viper.Viper, out *bytes.Buffer, got **utils.Options) *cobra.Command {
+	root := &cobra.Command{
+		Use: "vt",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.NewOptions(cmd, config)
+			if err != nil {
+				return err
+			}
+			cmd.SetContext(utils.WithOptions(cmd.Context(), opts))
+			return nil
+		},
+	}
+	root.PersistentFlags().StringP("apikey", "k", "", "")
+	root.PersistentFlags().String("format", "yaml", "")
+
+	print := &cobra.Command{
+		Use: "print",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := utils.CommandOptions(cmd)
+			if err != nil {
+				return err
+			}
+			*got = opts
+			p, err := utils.NewPrinter(nil, cmd, nil)
+			if err != nil {
+				return err
+			}
+			sm := &sync.Map{}
+			sm.Store("foo", 1)
+			sm.Store("bar", 2)
+			return p.PrintSyncMap(sm)
+		},
+	}
+	print.Flags().StringSliceP("include", "i", []string{"**"}, "")
+	print.Flags().StringSliceP("exclude", "x", []string{}, "")
+	print.Flags().IntP("threads", "t", 5, "")
+	print.Flags().IntP("limit", "n", 10, "")
+	print.Flags().Bool("human", false, "")
+
+	root.AddCommand(print)
+	root.SetOut(out)
+	return root
+}
+
+func runTestCommand(config *viper.Viper, args ...string) (string, *utils.Options, error) {
+	var out bytes.Buffer
+	var opts *utils.Options
+	root := newTestCommand(config, &out, &opts)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), opts, err
+}
+
+func Test_NewOptions_Precedence(t *testing.T) {
+	config := viper.New()
+	config.Set("apikey", "config-key")
+	config.Set("limit", 20)
+
+	_, opts, err := runTestCommand(config, "print")
+	assert.NoError(t, err)
+	assert.Equal(t, "config-key", opts.APIKey)
+	assert.Equal(t, 20, opts.Limit)
+	assert.Equal(t, 5, opts.Threads)
+	assert.Nil(t, opts.Include)
+	assert.Nil(t, opts.Exclude)
+
+	t.Setenv("VTCLI_APIKEY", "env-key")
+	_, opts, err = runTestCommand(config, "print", "-n", "3")
+	assert.NoError(t, err)
+	assert.Equal(t, "env-key", opts.APIKey)
+	assert.Equal(t, 3, opts.Limit)
+
+	_, opts, err = runTestCommand(config, "print", "-k", "flag-key", "-i", "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "flag-key", opts.APIKey)
+	assert.Equal(t, []string{"foo"}, opts.Include)
+	assert.Equal(t, []string{}, opts.Exclude)
+}
+
+func Test_NewOptions_Threads(t *testing.T) {
+	_, opts, err := runTestCommand(nil, "print", "-t", "0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, opts.Threads)
+}
+
+func Test_Commands_Concurrent(t *testing.T) {
+	type run struct {
+		args    []string
+		output  string
+		threads int
+		human   bool
+	}
+	runs := []run{
+		{
+			args:    []string{"print", "--format", "json", "-i", "foo", "-t", "2"},
+			output:  "{\n  \"foo\": 1\n}\n",
+			threads: 2,
+		},
+		{
+			args:    []string{"print", "--format", "csv", "-t", "7", "--human"},
+			output:  "bar,foo\n2,1\n",
+			threads: 7,
+			human:   true,
+		},
+		{
+			args:    []string{"print", "-x", "bar"},
+			output:  "foo: 1\n",
+			threads: 5,
+		},
+	}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		for _, r := range runs {
+			wg.Add(1)
+			go func(r run) {
+				defer wg.Done()
+				out, opts, err := runTestCommand(nil, r.args...)
+				assert.NoError(t, err)
+				assert.Equal(t, r.output, out, fmt.Sprint(r.args))
+				assert.Equal(t, r.threads, opts.Threads)
+				assert.Equal(t, r.human, opts.GetBool("human"))
+			}(r)
+		}
+	}
+	wg.Wait()
+}
+
+// newPagedServer returns a server for a collection of six objects, returned
+// in pages of two. The filters received are appended to filters.
+func newPagedServer(t *testing.T, filters *[]string) {
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("filter") {
+			mu.Lock()
+			*filters = append(*filters, r.URL.Query().Get("filter"))
+			mu.Unlock()
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		data := []map[string]string{}
+		for i := 2 * page; i < 2*page+2; i++ {
+			data = append(data, map[string]string{"type": "thing", "id": strconv.Itoa(i)})
+		}
+		links := map[string]string{
+			"self": fmt.Sprintf("http://%s/api/v3/things?page=%d", r.Host, page),
+		}
+		if page < 2 {
+			links["next"] = fmt.Sprintf("http://%s/api/v3/things?page=%d", r.Host, page+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "links": links})
+	}))
+	vt.SetHost(ts.URL)
+	t.Cleanup(func() {
+		ts.Close()
+		vt.SetHost("https://www.virustotal.com")
+	})
+}
+
+// iteratorIDs returns the IDs of the objects returned by it. The iterator is
+// not closed, as vt.Iterator.Close races with the goroutine fetching the
+// pages if it has already finished. With the few pages served by
+// newPagedServer the goroutine finishes by itself once the limit is reached.
+func iteratorIDs(t *testing.T, it *vt.Iterator) []string {
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Get().ID())
+	}
+	assert.NoError(t, it.Error())
+	return ids
+}
+
+func Test_OptionsIteratorOptions(t *testing.T) {
+	var filters []string
+	newPagedServer(t, &filters)
+	client := vt.NewClient("apikey")
+
+	tests := []struct {
+		name string
+		opts *utils.Options
+		ids  []string
+	}{
+		{
+			name: "limit",
+			opts: &utils.Options{Limit: 3, Filter: "foo"},
+			ids:  []string{"0", "1", "2"},
+		},
+		{
+			name: "all ignores limit",
+			opts: &utils.Options{Limit: 3, All: true},
+			ids:  []string{"0", "1", "2", "3", "4", "5"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			it, err := client.Iterator(vt.URL("things"), test.opts.IteratorOptions()...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.ids, iteratorIDs(t, it))
+		})
+	}
+	assert.Equal(t, []string{"foo"}, filters)
+
+	// Iteration resumes after the object where the cursor was taken.
+	it, err := client.Iterator(vt.URL("things"), (&utils.Options{Limit: 3}).IteratorOptions()...)
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		assert.True(t, it.Next())
+	}
+	cursor := it.Cursor()
+	it, err = client.Iterator(vt.URL("things"), (&utils.Options{Limit: 2, Cursor: cursor}).IteratorOptions()...)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3", "4"}, iteratorIDs(t, it))
+}
@@ -0,0 +1,147 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// PDNSRecord is a passive DNS record: a domain that resolved to an IP
+// address between two dates.
+type PDNSRecord struct {
+	RRName    string `json:"rrname" yaml:"rrname" csv:"rrname"`
+	RRType    string `json:"rrtype" yaml:"rrtype" csv:"rrtype"`
+	RData     string `json:"rdata" yaml:"rdata" csv:"rdata"`
+	FirstSeen string `json:"first_seen" yaml:"first_seen" csv:"first_seen"`
+	LastSeen  string `json:"last_seen" yaml:"last_seen" csv:"last_seen"`
+}
+
+// COFRecord is a passive DNS record in the Passive DNS Common Output Format,
+// described in draft-dulaunoy-dnsop-passive-dns-cof.
+type COFRecord struct {
+	RRName    string `json:"rrname"`
+	RRType    string `json:"rrtype"`
+	RData     string `json:"rdata"`
+	TimeFirst int64  `json:"time_first"`
+	TimeLast  int64  `json:"time_last"`
+}
+
+type pdnsKey struct{ rrname, rdata string }
+
+type pdnsSeen struct{ first, last time.Time }
+
+// PDNSSet is a set of passive DNS records built from VirusTotal resolution
+// objects. Records for the same domain and IP address are merged.
+type PDNSSet struct {
+	seen map[pdnsKey]*pdnsSeen
+}
+
+// NewPDNSSet returns an empty set.
+func NewPDNSSet() *PDNSSet {
+	return &PDNSSet{seen: make(map[pdnsKey]*pdnsSeen)}
+}
+
+// Add adds a resolution object to the set. It returns false if the object
+// is not a valid resolution.
+func (s *PDNSSet) Add(obj *vt.Object) bool {
+	host, _ := obj.GetString("host_name")
+	ip, _ := obj.GetString("ip_address")
+	date, err := obj.GetTime("date")
+	if host == "" || ip == "" || err != nil {
+		return false
+	}
+	k := pdnsKey{host, ip}
+	if seen, ok := s.seen[k]; ok {
+		if date.Before(seen.first) {
+			seen.first = date
+		}
+		if date.After(seen.last) {
+			seen.last = date
+		}
+	} else {
+		s.seen[k] = &pdnsSeen{first: date, last: date}
+	}
+	return true
+}
+
+// Len returns the number of records in the set.
+func (s *PDNSSet) Len() int {
+	return len(s.seen)
+}
+
+// keys returns the records seen between from and to, sorted by domain and IP
+// address. Zero times are not bounds.
+func (s *PDNSSet) keys(from, to time.Time) []pdnsKey {
+	var keys []pdnsKey
+	for k, seen := range s.seen {
+		if !from.IsZero() && seen.last.Before(from) || !to.IsZero() && seen.first.After(to) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].rrname != keys[j].rrname {
+			return keys[i].rrname < keys[j].rrname
+		}
+		return keys[i].rdata < keys[j].rdata
+	})
+	return keys
+}
+
+func rrtype(ip string) string {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return "AAAA"
+	}
+	return "A"
+}
+
+// Records returns the records seen between from and to, sorted by domain
+// and IP address. Zero times are not bounds.
+func (s *PDNSSet) Records(from, to time.Time) []PDNSRecord {
+	keys := s.keys(from, to)
+	records := make([]PDNSRecord, len(keys))
+	for i, k := range keys {
+		seen := s.seen[k]
+		records[i] = PDNSRecord{
+			RRName:    k.rrname,
+			RRType:    rrtype(k.rdata),
+			RData:     k.rdata,
+			FirstSeen: seen.first.UTC().Format(TimelineDateLayout),
+			LastSeen:  seen.last.UTC().Format(TimelineDateLayout),
+		}
+	}
+	return records
+}
+
+// COFRecords is like Records, but returns the records in the Passive DNS
+// Common Output Format.
+func (s *PDNSSet) COFRecords(from, to time.Time) []COFRecord {
+	keys := s.keys(from, to)
+	records := make([]COFRecord, len(keys))
+	for i, k := range keys {
+		seen := s.seen[k]
+		records[i] = COFRecord{
+			RRName:    k.rrname,
+			RRType:    rrtype(k.rdata),
+			RData:     k.rdata,
+			TimeFirst: seen.first.Unix(),
+			TimeLast:  seen.last.Unix(),
+		}
+	}
+	return records
+}
@@ -0,0 +1,63 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PDNSSet(t *testing.T) {
+	s := utils.NewPDNSSet()
+	assert.True(t, s.Add(newObject(t, "resolution", "1",
+		`{"date": 1704067200, "host_name": "b.example.com", "ip_address": "1.2.3.4"}`)))
+	assert.True(t, s.Add(newObject(t, "resolution", "2",
+		`{"date": 1706745600, "host_name": "a.example.com", "ip_address": "2001:db8::1"}`)))
+	// The same resolution seen from the IP address, and seen again later.
+	assert.True(t, s.Add(newObject(t, "resolution", "1",
+		`{"date": 1704067200, "host_name": "b.example.com", "ip_address": "1.2.3.4"}`)))
+	assert.True(t, s.Add(newObject(t, "resolution", "1",
+		`{"date": 1709251200, "host_name": "b.example.com", "ip_address": "1.2.3.4"}`)))
+	assert.False(t, s.Add(newObject(t, "resolution", "3", `{"date": 1704067200}`)))
+	assert.Equal(t, 2, s.Len())
+
+	assert.Equal(t, []utils.PDNSRecord{
+		{
+			RRName:    "a.example.com",
+			RRType:    "AAAA",
+			RData:     "2001:db8::1",
+			FirstSeen: "2024-02-01T00:00:00Z",
+			LastSeen:  "2024-02-01T00:00:00Z",
+		},
+		{
+			RRName:    "b.example.com",
+			RRType:    "A",
+			RData:     "1.2.3.4",
+			FirstSeen: "2024-01-01T00:00:00Z",
+			LastSeen:  "2024-03-01T00:00:00Z",
+		},
+	}, s.Records(time.Time{}, time.Time{}))
+
+	// Records are kept if they were seen at any time in the range.
+	from := time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []utils.COFRecord{
+		{RRName: "b.example.com", RRType: "A", RData: "1.2.3.4", TimeFirst: 1704067200, TimeLast: 1709251200},
+	}, s.COFRecords(from, time.Time{}))
+	to := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	assert.Len(t, s.Records(time.Time{}, to), 1)
+	assert.Empty(t, s.Records(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), time.Time{}))
+}
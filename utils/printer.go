@@ -14,24 +14,19 @@
 package utils
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"net/url"
-	"os"
 	"regexp"
 	"strings"
 	"sync"
 
-	"github.com/VirusTotal/vt-cli/csv"
+	"github.com/VirusTotal/vt-cli/pkg/vtcli"
 	"github.com/VirusTotal/vt-cli/yaml"
 	vt "github.com/VirusTotal/vt-go"
 	"github.com/fatih/color"
-	glob "github.com/gobwas/glob"
 	ansi "github.com/k0kubun/go-ansi"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"github.com/spf13/viper"
 )
 
 // Printer prints objects to stdout.
@@ -39,35 +34,52 @@ type Printer struct {
 	client *APIClient
 	colors *yaml.Colors
 	cmd    *cobra.Command
+	opts   *Options
 }
 
-// NewPrinter creates a new object printer.
+// NewPrinter creates a new object printer. The printer is configured with the
+// options for the current invocation of cmd, see CommandOptions.
 func NewPrinter(client *APIClient, cmd *cobra.Command, colors *yaml.Colors) (*Printer, error) {
-	return &Printer{client: client, cmd: cmd, colors: colors}, nil
+	opts, err := CommandOptions(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return &Printer{client: client, cmd: cmd, colors: colors, opts: opts}, nil
 }
 
-// Print prints the provided data to stdout.
+// printer returns a vtcli.Printer that writes to the output in the options,
+// configured according to the --format, --include and --exclude flags. The --identifiers-only flag
+// is honoured only if identifiersOnly is true.
+func (p *Printer) printer(identifiersOnly bool) (*vtcli.Printer, error) {
+	format, err := vtcli.ParseFormat(p.opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	opts := []vtcli.PrinterOption{
+		vtcli.WithFormat(format),
+		vtcli.WithColors(p.colors),
+		vtcli.WithIdentifiersOnly(identifiersOnly && p.opts.IdentifiersOnly),
+	}
+	if p.opts.Include != nil || p.opts.Exclude != nil {
+		opts = append(opts, vtcli.WithFilter(p.opts.Include, p.opts.Exclude))
+	}
+	// The product name and version are taken from the agent string used by
+	// the API client, which has the form "vt-cli <version>".
+	if p.client != nil {
+		if agent := strings.SplitN(p.client.Agent, " ", 2); len(agent) == 2 {
+			opts = append(opts, vtcli.WithProduct(agent[0], agent[1]))
+		}
+	}
+	return vtcli.NewPrinter(p.opts.Stdout, opts...), nil
+}
+
+// Print prints the provided data.
 func (p *Printer) Print(data interface{}) error {
-	format := strings.ToLower(viper.GetString("format"))
-	if format == "" || format == "yaml" {
-		return yaml.NewEncoder(
-			ansi.NewAnsiStdout(),
-			yaml.EncoderColors(p.colors),
-			yaml.EncoderDateKeys([]glob.Glob{
-				glob.MustCompile("last_login"),
-				glob.MustCompile("user_since"),
-				glob.MustCompile("date"),
-				glob.MustCompile("*_date"),
-			})).Encode(data)
-	} else if format == "json" {
-		encoder := json.NewEncoder(ansi.NewAnsiStdout())
-		encoder.SetIndent("", "  ")
-		return encoder.Encode(data)
-	} else if format == "csv" {
-		return csv.NewEncoder(ansi.NewAnsiStdout()).Encode(data)
-	} else {
-		return errors.New("unknown format")
+	vp, err := p.printer(false)
+	if err != nil {
+		return err
 	}
+	return vp.Print(data)
 }
 
 // PrintSyncMap prints a sync.Map.
@@ -77,76 +89,33 @@ func (p *Printer) PrintSyncMap(sm *sync.Map) error {
 		m[key.(string)] = value
 		return true
 	})
-	if viper.IsSet("include") || viper.IsSet("exclude") {
-		m = FilterMap(m,
-			viper.GetStringSlice("include"),
-			viper.GetStringSlice("exclude"))
+	vp, err := p.printer(false)
+	if err != nil {
+		return err
 	}
-	return p.Print(m)
+	return vp.PrintMap(m)
 }
 
 // ObjectToMap function that returns the attributes for an object as a map.
 // Keys are attribute names and values are the attribute's value. Two special
 // keys _id and _type are also included in the map with object's identifier
-// and type respectively. The map is filtered according to the filters specified
-// in the --include and --exclude command-line arguments.
+// and type respectively.
 func ObjectToMap(obj *vt.Object) map[string]interface{} {
-	m := make(map[string]interface{})
-	m["_id"] = obj.ID()
-	m["_type"] = obj.Type()
-
-	contextAttributes := make(map[string]interface{})
-	for _, attr := range obj.ContextAttributes() {
-		contextAttributes[attr], _ = obj.GetContext(attr)
-	}
-	if len(contextAttributes) > 0 {
-		m["_context_attributes"] = contextAttributes
-	}
-
-	for _, attr := range obj.Attributes() {
-		m[attr], _ = obj.Get(attr)
-	}
-	for _, name := range obj.Relationships() {
-		r, _ := obj.GetRelationship(name)
-		relatedObjs := r.Objects()
-		if r.IsOneToOne() {
-			if len(relatedObjs) > 0 {
-				m[name] = relatedObjs[0].ID()
-			} else {
-				m[name] = nil
-			}
-		} else {
-			l := make([]string, 0)
-			for _, obj := range relatedObjs {
-				l = append(l, obj.ID())
-			}
-			m[name] = l
-		}
-	}
-	return m
+	return vtcli.ObjectToMap(obj)
 }
 
-// PrintObjects prints all the specified objects to stdout.
+// PrintObjects prints all the specified objects. Objects are printed
+// in full even if --identifiers-only is set, callers that honour that flag
+// must check it themselves.
 func (p *Printer) PrintObjects(objs []*vt.Object) error {
-	list := make([]map[string]interface{}, 0)
-	for _, obj := range objs {
-		m := ObjectToMap(obj)
-		if viper.IsSet("include") || viper.IsSet("exclude") {
-			m = FilterMap(m,
-				viper.GetStringSlice("include"),
-				viper.GetStringSlice("exclude"))
-		}
-		if len(m) > 0 {
-			list = append(list, m)
-		}
-	}
-	if len(list) > 0 {
-		return p.Print(list)
+	vp, err := p.printer(false)
+	if err != nil {
+		return err
 	}
-	return nil
+	return vp.PrintObjects(objs)
 }
 
-// PrintObject prints the specified object to stdout.
+// PrintObject prints the specified object.
 func (p *Printer) PrintObject(obj *vt.Object) error {
 	objs := make([]*vt.Object, 1)
 	objs[0] = obj
@@ -169,21 +138,52 @@ func (p *Printer) GetAndPrintObjects(endpoint string, r StringReader, argRe *reg
 // "-" string, the args are read from stdin one per line. If argRe is non-nil, only
 // args that match the regular expression are used and the rest are discarded.
 func (p *Printer) GetAndPrintObjectsWithFallback(endpoints []string, r StringReader, argRe *regexp.Regexp) error {
+	return p.retrieveAndPrint(r, argRe, func(r StringReader, outCh chan *vt.Object, errCh chan error) error {
+		return p.client.RetrieveObjectsFromReader(endpoints, r, outCh, errCh)
+	})
+}
+
+// GetAndPrintFiles retrieves files by their hashes and prints them, like
+// GetAndPrintObjects does with the "files/%s" endpoint, but retrieving many
+// files with each request. See APIClient.RetrieveFilesFromReader.
+func (p *Printer) GetAndPrintFiles(r StringReader, argRe *regexp.Regexp) error {
+	return p.retrieveAndPrint(r, argRe, p.client.RetrieveFilesFromReader)
+}
+
+// retrieveAndPrint prints the objects retrieved by the retrieve function for
+// the args read from r, filtered with argRe if it's non-nil. Errors sent by
+// retrieve are printed to the options' stderr after the objects, and the
+// error returned by retrieve, if any, is returned once all the objects have
+// been printed. With the line-oriented SIEM formats objects are printed as
+// soon as they are received, so the memory used doesn't depend on the number
+// of args.
+func (p *Printer) retrieveAndPrint(r StringReader, argRe *regexp.Regexp, retrieve func(StringReader, chan *vt.Object, chan error) error) error {
+	format, err := vtcli.ParseFormat(p.opts.Format)
+	if err != nil {
+		return err
+	}
 	if argRe != nil {
 		r = NewFilteredStringReader(r, argRe)
 	}
 
-	filteredArgs := make([]string, 0)
-	for s, err := r.ReadString(); s != "" || err == nil; s, err = r.ReadString() {
-		filteredArgs = append(filteredArgs, s)
-	}
-
 	objectsCh := make(chan *vt.Object)
-	errorsCh := make(chan error, len(filteredArgs))
+	errorsCh := make(chan error)
+
+	retrieveErr := make(chan error, 1)
+	go func() {
+		retrieveErr <- retrieve(r, objectsCh, errorsCh)
+	}()
 
-	go p.client.RetrieveObjectsWithFallback(endpoints, filteredArgs, objectsCh, errorsCh)
+	var errs []error
+	errsDone := make(chan struct{})
+	go func() {
+		for err := range errorsCh {
+			errs = append(errs, err)
+		}
+		close(errsDone)
+	}()
 
-	if viper.GetBool("identifiers-only") {
+	if p.opts.IdentifiersOnly {
 		var objectIds []string
 		for obj := range objectsCh {
 			objectIds = append(objectIds, obj.ID())
@@ -191,6 +191,12 @@ func (p *Printer) GetAndPrintObjectsWithFallback(endpoints []string, r StringRea
 		if err := p.Print(objectIds); err != nil {
 			return err
 		}
+	} else if format.IsStreaming() {
+		for obj := range objectsCh {
+			if err := p.PrintObject(obj); err != nil {
+				return err
+			}
+		}
 	} else {
 		var objects []*vt.Object
 		for obj := range objectsCh {
@@ -201,50 +207,35 @@ func (p *Printer) GetAndPrintObjectsWithFallback(endpoints []string, r StringRea
 		}
 	}
 
-	for err := range errorsCh {
-		fmt.Fprintln(os.Stderr, err)
+	<-errsDone
+	for _, err := range errs {
+		fmt.Fprintln(p.opts.Stderr, err)
 	}
 
-	return nil
+	return <-retrieveErr
 }
 
 // PrintCollection prints a collection of objects retrieved from the collection
-// specified by the collection URL.
+// specified by the collection URL. If the --all flag is set the whole
+// collection is printed, regardless of --limit.
 func (p *Printer) PrintCollection(collection *url.URL) error {
-	it, err := p.client.Iterator(collection,
-		vt.IteratorLimit(viper.GetInt("limit")),
-		vt.IteratorCursor(viper.GetString("cursor")),
-		vt.IteratorFilter(viper.GetString("filter")))
+	it, err := p.client.Iterator(collection, p.opts.IteratorOptions()...)
 	if err != nil {
 		return err
 	}
 	return p.PrintIterator(it)
 }
 
-// PrintIterator prints the objects returned by an object iterator.
+// PrintIterator prints the objects returned by an object iterator. When the
+// output format is one of the line-oriented SIEM formats objects are printed
+// as they are returned by the iterator.
 func (p *Printer) PrintIterator(it *vt.Iterator) error {
-	var objs []*vt.Object
-	var ids []string
-	for it.Next() {
-		obj := it.Get()
-		if viper.GetBool("identifiers-only") {
-			ids = append(ids, obj.ID())
-		} else {
-			objs = append(objs, obj)
-		}
-	}
-	if err := it.Error(); err != nil {
+	vp, err := p.printer(true)
+	if err != nil {
 		return err
 	}
-
-	if viper.GetBool("identifiers-only") {
-		if err := p.Print(ids); err != nil {
-			return err
-		}
-	} else {
-		if err := p.PrintObjects(objs); err != nil {
-			return err
-		}
+	if err := vp.PrintIterator(it); err != nil {
+		return err
 	}
 	p.PrintCommandLineWithCursor(it)
 	return nil
@@ -0,0 +1,177 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// interestAttributes maps the interest types in a Threat Profile to the
+// collection attributes that contain the values for that interest.
+var interestAttributes = map[string]string{
+	"INTEREST_TYPE_TARGETED_INDUSTRY": "targeted_industries_tree",
+	"INTEREST_TYPE_TARGETED_REGION":   "targeted_regions_hierarchy",
+	"INTEREST_TYPE_SOURCE_REGION":     "source_regions_hierarchy",
+	"INTEREST_TYPE_MALWARE_ROLE":      "malware_roles",
+	"INTEREST_TYPE_ACTOR_MOTIVATION":  "motivations",
+}
+
+// InterestCategory returns a short name for an interest type, for example
+// "targeted_industry" for INTEREST_TYPE_TARGETED_INDUSTRY.
+func InterestCategory(interestType string) string {
+	return strings.ToLower(strings.TrimPrefix(interestType, "INTEREST_TYPE_"))
+}
+
+// ProfileInterests returns the interests of a Threat Profile as a map where
+// keys are interest types and values the list of values for each type.
+func ProfileInterests(profile *vt.Object) map[string][]string {
+	interests := make(map[string][]string)
+	v, _ := profile.Get("interests")
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return interests
+	}
+	for interestType, values := range m {
+		if l, ok := values.([]interface{}); ok {
+			for _, value := range l {
+				if s, ok := value.(string); ok {
+					interests[interestType] = append(interests[interestType], s)
+				}
+			}
+		}
+	}
+	return interests
+}
+
+// collectStrings puts in set all the strings contained in v, which can be a
+// string, or arbitrarily nested slices and maps. Strings are lowercased.
+func collectStrings(v interface{}, set map[string]struct{}) {
+	switch val := v.(type) {
+	case string:
+		set[strings.ToLower(val)] = struct{}{}
+	case []interface{}:
+		for _, item := range val {
+			collectStrings(item, set)
+		}
+	case map[string]interface{}:
+		for _, item := range val {
+			collectStrings(item, set)
+		}
+	}
+}
+
+// matchedCategories returns the interest categories of the profile that are
+// matched by the given collection.
+func matchedCategories(interests map[string][]string, collection *vt.Object) []string {
+	matched := make([]string, 0)
+	for interestType, values := range interests {
+		attr, ok := interestAttributes[interestType]
+		if !ok {
+			continue
+		}
+		v, _ := collection.Get(attr)
+		set := make(map[string]struct{})
+		collectStrings(v, set)
+		for _, value := range values {
+			if _, found := set[strings.ToLower(value)]; found {
+				matched = append(matched, InterestCategory(interestType))
+				break
+			}
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// Recommendation is an object recommended by a Threat Profile.
+type Recommendation struct {
+	ID   string
+	Name string
+	// Type is the collection type for collections, like threat-actor or
+	// malware-family, and the object type for other objects.
+	Type              string
+	MatchedCategories []string
+	LastSeen          int64
+}
+
+// ToMap returns the recommendation as a map suitable for being printed.
+func (r *Recommendation) ToMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"id":                 r.ID,
+		"name":               r.Name,
+		"matched_categories": r.MatchedCategories,
+	}
+	if r.LastSeen > 0 {
+		m["last_seen"] = r.LastSeen
+	}
+	return m
+}
+
+// RecommendationSet merges the objects recommended by a Threat Profile in
+// its different recommendation relationships.
+type RecommendationSet struct {
+	interests map[string][]string
+	seen      map[string]bool
+	recs      []Recommendation
+}
+
+// NewRecommendationSet returns an empty set of recommendations for the given
+// Threat Profile.
+func NewRecommendationSet(profile *vt.Object) *RecommendationSet {
+	return &RecommendationSet{
+		interests: ProfileInterests(profile),
+		seen:      make(map[string]bool),
+	}
+}
+
+// Add adds a recommended object to the set. Objects already in the set are
+// ignored, and Add returns false for them.
+func (s *RecommendationSet) Add(obj *vt.Object) bool {
+	if s.seen[obj.ID()] {
+		return false
+	}
+	s.seen[obj.ID()] = true
+	rec := Recommendation{
+		ID:                obj.ID(),
+		Type:              obj.Type(),
+		MatchedCategories: matchedCategories(s.interests, obj),
+	}
+	rec.Name, _ = obj.GetString("name")
+	if collectionType, err := obj.GetString("collection_type"); err == nil && collectionType != "" {
+		rec.Type = collectionType
+	}
+	if t, err := obj.GetInt64("last_seen"); err == nil {
+		rec.LastSeen = t
+	} else if t, err := obj.GetInt64("last_modification_date"); err == nil {
+		rec.LastSeen = t
+	}
+	s.recs = append(s.recs, rec)
+	return true
+}
+
+// Recommendations returns the recommendations sorted by type and, within
+// each type, from the most to the least recently seen.
+func (s *RecommendationSet) Recommendations() []Recommendation {
+	recs := append([]Recommendation{}, s.recs...)
+	sort.SliceStable(recs, func(i, j int) bool {
+		if recs[i].Type != recs[j].Type {
+			return recs[i].Type < recs[j].Type
+		}
+		return recs[i].LastSeen > recs[j].LastSeen
+	})
+	return recs
+}
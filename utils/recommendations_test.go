@@ -0,0 +1,88 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ProfileInterests(t *testing.T) {
+	profile := newObject(t, "threat_profile", "p", `{"interests": {
+		"INTEREST_TYPE_TARGETED_REGION": ["US", "ES", 3],
+		"INTEREST_TYPE_ACTOR_MOTIVATION": "not a list"
+	}}`)
+	assert.Equal(t, map[string][]string{
+		"INTEREST_TYPE_TARGETED_REGION": {"US", "ES"},
+	}, utils.ProfileInterests(profile))
+	assert.Empty(t, utils.ProfileInterests(newObject(t, "threat_profile", "p", `{}`)))
+	assert.Equal(t, "targeted_region", utils.InterestCategory("INTEREST_TYPE_TARGETED_REGION"))
+}
+
+func Test_RecommendationSet(t *testing.T) {
+	profile := newObject(t, "threat_profile", "p", `{"interests": {
+		"INTEREST_TYPE_TARGETED_REGION": ["US"],
+		"INTEREST_TYPE_TARGETED_INDUSTRY": ["Financial Services"],
+		"INTEREST_TYPE_ACTOR_MOTIVATION": ["Espionage"],
+		"INTEREST_TYPE_UNKNOWN": ["x"]
+	}}`)
+	s := utils.NewRecommendationSet(profile)
+
+	actor := newObject(t, "collection", "actor", `{
+		"name": "APT1",
+		"collection_type": "threat-actor",
+		"last_seen": 200,
+		"targeted_regions_hierarchy": [{"country_iso2": "us", "region": "Americas"}],
+		"targeted_industries_tree": [{"industry_group": "Financial Services"}],
+		"motivations": [{"value": "Financial Gain"}]
+	}`)
+	olderActor := newObject(t, "collection", "older_actor", `{
+		"name": "APT2",
+		"collection_type": "threat-actor",
+		"last_modification_date": 100
+	}`)
+	report := newObject(t, "collection", "report", `{
+		"name": "Report",
+		"collection_type": "report",
+		"motivations": [{"value": "espionage"}]
+	}`)
+	file := newObject(t, "file", "f", `{}`)
+
+	assert.True(t, s.Add(olderActor))
+	assert.True(t, s.Add(actor))
+	assert.True(t, s.Add(report))
+	assert.True(t, s.Add(file))
+	// The same object found in another relationship.
+	assert.False(t, s.Add(actor))
+
+	assert.Equal(t, []utils.Recommendation{
+		{ID: "f", Type: "file", MatchedCategories: []string{}},
+		{ID: "report", Name: "Report", Type: "report", MatchedCategories: []string{"actor_motivation"}},
+		{ID: "actor", Name: "APT1", Type: "threat-actor", LastSeen: 200,
+			MatchedCategories: []string{"targeted_industry", "targeted_region"}},
+		{ID: "older_actor", Name: "APT2", Type: "threat-actor", LastSeen: 100,
+			MatchedCategories: []string{}},
+	}, s.Recommendations())
+}
+
+func Test_RecommendationToMap(t *testing.T) {
+	r := utils.Recommendation{ID: "a", Name: "A", MatchedCategories: []string{"malware_role"}}
+	assert.Equal(t, map[string]interface{}{
+		"id": "a", "name": "A", "matched_categories": []string{"malware_role"},
+	}, r.ToMap())
+	r.LastSeen = 10
+	assert.Equal(t, int64(10), r.ToMap()["last_seen"])
+}
@@ -0,0 +1,127 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"sort"
+
+	"github.com/VirusTotal/vt-cli/hashes"
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// SimilarityMethod is a way of finding similar files: files that have the
+// same value, or a similar one for fuzzy hashes, in some attribute.
+type SimilarityMethod struct {
+	Name string
+	// Attribute is the file object attribute that holds the hash.
+	Attribute string
+	// Modifier is the VirusTotal Intelligence search modifier that finds
+	// files with a similar hash.
+	Modifier string
+}
+
+// SimilarityMethods are the similarity methods supported by
+// SimilarityRanker, from the most to the least specific.
+var SimilarityMethods = []SimilarityMethod{
+	{Name: "authentihash", Attribute: "authentihash", Modifier: "authentihash"},
+	{Name: "vhash", Attribute: "vhash", Modifier: "vhash"},
+	{Name: "imphash", Attribute: "pe_info.imphash", Modifier: "imphash"},
+	{Name: "ssdeep", Attribute: "ssdeep", Modifier: "ssdeep"},
+	{Name: "tlsh", Attribute: "tlsh", Modifier: "tlsh"},
+}
+
+// SimilarFile is a file found by one or more similarity methods.
+type SimilarFile struct {
+	Object *vt.Object
+	// Methods are the names of the methods that found the file, in the same
+	// order they have in SimilarityMethods.
+	Methods []string
+	// SSDeepScore is the similarity of the ssdeep hashes, from 0 to 100, or
+	// -1 if any of the files doesn't have one.
+	SSDeepScore int
+	// TLSHDistance is the distance between the TLSH hashes, lower is more
+	// similar, or -1 if any of the files doesn't have one.
+	TLSHDistance int
+}
+
+// SimilarityRanker merges the files found by different similarity methods
+// and ranks them.
+type SimilarityRanker struct {
+	source map[string]string
+	files  map[string]*SimilarFile
+}
+
+// NewSimilarityRanker returns a ranker for the files similar to a file with
+// the given hashes, which are indexed by method name.
+func NewSimilarityRanker(source map[string]string) *SimilarityRanker {
+	return &SimilarityRanker{source: source, files: make(map[string]*SimilarFile)}
+}
+
+// Add adds a file found by the given method.
+func (r *SimilarityRanker) Add(method string, obj *vt.Object) {
+	f, ok := r.files[obj.ID()]
+	if !ok {
+		f = &SimilarFile{Object: obj, SSDeepScore: -1, TLSHDistance: -1}
+		if h, err := obj.GetString("ssdeep"); err == nil && r.source["ssdeep"] != "" {
+			if score, err := hashes.CompareSSDeep(r.source["ssdeep"], h); err == nil {
+				f.SSDeepScore = score
+			}
+		}
+		if h, err := obj.GetString("tlsh"); err == nil && r.source["tlsh"] != "" {
+			if d, err := hashes.DiffTLSH(r.source["tlsh"], h); err == nil {
+				f.TLSHDistance = d
+			}
+		}
+		r.files[obj.ID()] = f
+	}
+	for _, m := range f.Methods {
+		if m == method {
+			return
+		}
+	}
+	f.Methods = append(f.Methods, method)
+	order := make(map[string]int)
+	for i, m := range SimilarityMethods {
+		order[m.Name] = i
+	}
+	sort.Slice(f.Methods, func(i, j int) bool { return order[f.Methods[i]] < order[f.Methods[j]] })
+}
+
+// Ranked returns the files sorted from the most to the least similar: first
+// the ones found by more methods, then the ones with higher ssdeep scores and
+// then the ones with lower TLSH distances.
+func (r *SimilarityRanker) Ranked() []*SimilarFile {
+	files := make([]*SimilarFile, 0, len(r.files))
+	for _, f := range r.files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		a, b := files[i], files[j]
+		if len(a.Methods) != len(b.Methods) {
+			return len(a.Methods) > len(b.Methods)
+		}
+		if a.SSDeepScore != b.SSDeepScore {
+			return a.SSDeepScore > b.SSDeepScore
+		}
+		if a.TLSHDistance != b.TLSHDistance {
+			// Unknown distances go last.
+			if a.TLSHDistance < 0 || b.TLSHDistance < 0 {
+				return b.TLSHDistance < 0
+			}
+			return a.TLSHDistance < b.TLSHDistance
+		}
+		return a.Object.ID() < b.Object.ID()
+	})
+	return files
+}
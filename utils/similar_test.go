@@ -0,0 +1,53 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SimilarityRanker(t *testing.T) {
+	r := utils.NewSimilarityRanker(map[string]string{
+		"ssdeep": "3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C",
+		"tlsh":   "T1A4A002B5A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2",
+	})
+	a := newObject(t, "file", "a", `{"ssdeep": "3:AXGBicFlgVNhBGcL6wCrFQEv:AXGHsNhxLsr2C"}`)
+	b := newObject(t, "file", "b", `{"ssdeep": "3:AXGBicFlIHBGcL6wCrFQEv:AXGH6xLsr2C"}`)
+	c := newObject(t, "file", "c", `{"tlsh": "T1A4A002B5A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2A0A2"}`)
+	d := newObject(t, "file", "d", `{}`)
+
+	r.Add("tlsh", c)
+	r.Add("ssdeep", b)
+	r.Add("imphash", d)
+	r.Add("ssdeep", a)
+	r.Add("vhash", a)
+	r.Add("ssdeep", a)
+
+	ranked := r.Ranked()
+	ids := make([]string, len(ranked))
+	for i, f := range ranked {
+		ids[i] = f.Object.ID()
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ids)
+
+	assert.Equal(t, []string{"vhash", "ssdeep"}, ranked[0].Methods)
+	assert.Equal(t, 100, ranked[0].SSDeepScore)
+	assert.Equal(t, -1, ranked[0].TLSHDistance)
+	assert.True(t, ranked[1].SSDeepScore > 0 && ranked[1].SSDeepScore < 100)
+	assert.Equal(t, 0, ranked[2].TLSHDistance)
+	assert.Equal(t, -1, ranked[3].SSDeepScore)
+}
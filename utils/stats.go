@@ -0,0 +1,257 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// MissingValue is the value under which objects that don't have the
+// aggregated field are counted.
+const MissingValue = "(none)"
+
+// Aggregation describes how the values of a field are grouped. Without
+// Bucket or Period every distinct value is a group, and fields containing
+// lists or dictionaries count once for each element or key. With Bucket the
+// field must be numeric and values are grouped in intervals of that width.
+// With Period the field must be a UNIX timestamp and values are grouped by
+// day, week, month or year.
+type Aggregation struct {
+	Field  string
+	Bucket float64
+	Period string
+}
+
+var periodLayouts = map[string]string{
+	"day":   "2006-01-02",
+	"month": "2006-01",
+	"year":  "2006",
+	// Weeks are formatted by periodKey, they don't have a layout.
+	"week": "",
+}
+
+// ParseAggregation parses an aggregation with the format field[:modifier],
+// where modifier is bucket=N or one of day, week, month and year.
+func ParseAggregation(spec string) (Aggregation, error) {
+	field, modifier, _ := strings.Cut(spec, ":")
+	agg := Aggregation{Field: strings.TrimSpace(field)}
+	if agg.Field == "" {
+		return agg, fmt.Errorf("missing field name in %q", spec)
+	}
+	switch modifier = strings.TrimSpace(modifier); {
+	case modifier == "":
+	case strings.HasPrefix(modifier, "bucket="):
+		b, err := strconv.ParseFloat(strings.TrimPrefix(modifier, "bucket="), 64)
+		if err != nil || b <= 0 || math.IsInf(b, 0) {
+			return agg, fmt.Errorf("invalid bucket size in %q", spec)
+		}
+		agg.Bucket = b
+	default:
+		if _, ok := periodLayouts[modifier]; !ok {
+			return agg, fmt.Errorf(
+				"unknown modifier %q in %q, use bucket=N, day, week, month or year",
+				modifier, spec)
+		}
+		agg.Period = modifier
+	}
+	return agg, nil
+}
+
+// String returns the aggregation in the format accepted by ParseAggregation.
+func (a Aggregation) String() string {
+	switch {
+	case a.Bucket > 0:
+		return fmt.Sprintf("%s:bucket=%g", a.Field, a.Bucket)
+	case a.Period != "":
+		return fmt.Sprintf("%s:%s", a.Field, a.Period)
+	}
+	return a.Field
+}
+
+// histogram returns true if the groups are intervals that should be sorted
+// by their value instead of by their count.
+func (a Aggregation) histogram() bool {
+	return a.Bucket > 0 || a.Period != ""
+}
+
+// bucketKey returns the interval of width a.Bucket that contains n, and the
+// lower bound of that interval, used for sorting.
+func (a Aggregation) bucketKey(n float64) (string, float64) {
+	low := math.Floor(n/a.Bucket) * a.Bucket
+	return fmt.Sprintf("%g-%g", low, low+a.Bucket), low
+}
+
+// periodKey returns the period that contains the UNIX timestamp ts, and the
+// timestamp at which the period starts, used for sorting.
+func (a Aggregation) periodKey(ts int64) (string, float64) {
+	t := time.Unix(ts, 0).UTC()
+	var start time.Time
+	switch a.Period {
+	case "day":
+		start = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case "week":
+		year, week := t.ISOWeek()
+		start = time.Date(t.Year(), t.Month(), t.Day()-(int(t.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+		return fmt.Sprintf("%d-W%02d", year, week), float64(start.Unix())
+	case "month":
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case "year":
+		start = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return start.Format(periodLayouts[a.Period]), float64(start.Unix())
+}
+
+// keys returns the groups in which an attribute value is counted, together
+// with the value used for sorting histogram groups.
+func (a Aggregation) keys(v interface{}) (keys []string, order []float64) {
+	add := func(k string, o float64) {
+		keys = append(keys, k)
+		order = append(order, o)
+	}
+	if a.histogram() {
+		n, ok := v.(json.Number)
+		if !ok {
+			add(MissingValue, math.Inf(1))
+			return
+		}
+		if a.Period != "" {
+			ts, err := n.Int64()
+			if err != nil {
+				add(MissingValue, math.Inf(1))
+				return
+			}
+			add(a.periodKey(ts))
+			return
+		}
+		f, err := n.Float64()
+		if err != nil {
+			add(MissingValue, math.Inf(1))
+			return
+		}
+		add(a.bucketKey(f))
+		return
+	}
+	switch v := v.(type) {
+	case nil:
+		add(MissingValue, 0)
+	case []interface{}:
+		for _, e := range v {
+			add(fmt.Sprint(e), 0)
+		}
+	case map[string]interface{}:
+		for k := range v {
+			add(k, 0)
+		}
+	default:
+		add(fmt.Sprint(v), 0)
+	}
+	if len(keys) == 0 {
+		add(MissingValue, 0)
+	}
+	return
+}
+
+// StatsRow is the number of objects in a group.
+type StatsRow struct {
+	Field   string  `json:"field" yaml:"field" csv:"field"`
+	Value   string  `json:"value" yaml:"value" csv:"value"`
+	Count   int     `json:"count" yaml:"count" csv:"count"`
+	Percent float64 `json:"percent" yaml:"percent" csv:"percent"`
+}
+
+type statsGroup struct {
+	count int
+	order float64
+}
+
+// Stats computes group-by counts and histograms over a set of objects.
+type Stats struct {
+	aggs   []Aggregation
+	groups []map[string]*statsGroup
+	// Count is the number of objects added.
+	Count int
+}
+
+// NewStats returns a Stats that computes the given aggregations.
+func NewStats(aggs []Aggregation) *Stats {
+	s := &Stats{aggs: aggs, groups: make([]map[string]*statsGroup, len(aggs))}
+	for i := range s.groups {
+		s.groups[i] = make(map[string]*statsGroup)
+	}
+	return s
+}
+
+// Add counts an object in each of the aggregations.
+func (s *Stats) Add(obj *vt.Object) {
+	s.Count++
+	for i, agg := range s.aggs {
+		v, _ := obj.Get(agg.Field)
+		keys, order := agg.keys(v)
+		seen := make(map[string]bool, len(keys))
+		for j, k := range keys {
+			// A list with repeated elements counts the object only once.
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			g, ok := s.groups[i][k]
+			if !ok {
+				g = &statsGroup{order: order[j]}
+				s.groups[i][k] = g
+			}
+			g.count++
+		}
+	}
+}
+
+// Rows returns the groups for each aggregation, in the order in which the
+// aggregations were passed to NewStats. Histogram groups are sorted by value,
+// other groups are sorted from the most to the least frequent. Percentages
+// are relative to the number of objects added.
+func (s *Stats) Rows() []StatsRow {
+	var rows []StatsRow
+	for i, agg := range s.aggs {
+		values := make([]string, 0, len(s.groups[i]))
+		for v := range s.groups[i] {
+			values = append(values, v)
+		}
+		groups := s.groups[i]
+		sort.Slice(values, func(a, b int) bool {
+			ga, gb := groups[values[a]], groups[values[b]]
+			if agg.histogram() && ga.order != gb.order {
+				return ga.order < gb.order
+			}
+			if !agg.histogram() && ga.count != gb.count {
+				return ga.count > gb.count
+			}
+			return values[a] < values[b]
+		})
+		for _, v := range values {
+			row := StatsRow{Field: agg.String(), Value: v, Count: groups[v].count}
+			if s.Count > 0 {
+				row.Percent = math.Round(float64(row.Count)*1000/float64(s.Count)) / 10
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
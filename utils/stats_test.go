@@ -0,0 +1,87 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseAggregation(t *testing.T) {
+	agg, err := utils.ParseAggregation("type_tag")
+	assert.NoError(t, err)
+	assert.Equal(t, utils.Aggregation{Field: "type_tag"}, agg)
+
+	agg, err = utils.ParseAggregation("last_analysis_stats.malicious:bucket=10")
+	assert.NoError(t, err)
+	assert.Equal(t, utils.Aggregation{Field: "last_analysis_stats.malicious", Bucket: 10}, agg)
+	assert.Equal(t, "last_analysis_stats.malicious:bucket=10", agg.String())
+
+	agg, err = utils.ParseAggregation("first_submission_date:month")
+	assert.NoError(t, err)
+	assert.Equal(t, utils.Aggregation{Field: "first_submission_date", Period: "month"}, agg)
+
+	_, err = utils.ParseAggregation("size:bucket=0")
+	assert.EqualError(t, err, `invalid bucket size in "size:bucket=0"`)
+	_, err = utils.ParseAggregation("size:fortnight")
+	assert.Error(t, err)
+	_, err = utils.ParseAggregation(":day")
+	assert.Error(t, err)
+}
+
+func Test_Stats(t *testing.T) {
+	aggs := []utils.Aggregation{
+		{Field: "tags"},
+		{Field: "last_analysis_stats.malicious", Bucket: 10},
+		{Field: "first_submission_date", Period: "month"},
+	}
+	stats := utils.NewStats(aggs)
+	stats.Add(newObject(t, "file", "x", `{
+		"tags": ["peexe", "signed"],
+		"last_analysis_stats": {"malicious": 5},
+		"first_submission_date": 1704067200}`))
+	stats.Add(newObject(t, "file", "x", `{
+		"tags": ["peexe", "peexe"],
+		"last_analysis_stats": {"malicious": 15},
+		"first_submission_date": 1701388800}`))
+	stats.Add(newObject(t, "file", "x", `{
+		"tags": [],
+		"last_analysis_stats": {"malicious": 9}}`))
+	stats.Add(newObject(t, "file", "x", `{"last_analysis_stats": {"malicious": 0}}`))
+
+	assert.Equal(t, 4, stats.Count)
+	assert.Equal(t, []utils.StatsRow{
+		{Field: "tags", Value: "(none)", Count: 2, Percent: 50},
+		{Field: "tags", Value: "peexe", Count: 2, Percent: 50},
+		{Field: "tags", Value: "signed", Count: 1, Percent: 25},
+		{Field: "last_analysis_stats.malicious:bucket=10", Value: "0-10", Count: 3, Percent: 75},
+		{Field: "last_analysis_stats.malicious:bucket=10", Value: "10-20", Count: 1, Percent: 25},
+		{Field: "first_submission_date:month", Value: "2023-12", Count: 1, Percent: 25},
+		{Field: "first_submission_date:month", Value: "2024-01", Count: 1, Percent: 25},
+		{Field: "first_submission_date:month", Value: "(none)", Count: 2, Percent: 50},
+	}, stats.Rows())
+}
+
+func Test_Stats_Week(t *testing.T) {
+	stats := utils.NewStats([]utils.Aggregation{{Field: "date", Period: "week"}})
+	// 2021-01-01 is a Friday in the last ISO week of 2020.
+	stats.Add(newObject(t, "file", "x", `{"date": 1609459200}`))
+	// 2021-01-04 is the Monday that starts the first ISO week of 2021.
+	stats.Add(newObject(t, "file", "x", `{"date": 1609718400}`))
+	rows := stats.Rows()
+	assert.Equal(t, "2020-W53", rows[0].Value)
+	assert.Equal(t, "2021-W01", rows[1].Value)
+}
@@ -15,10 +15,19 @@ package utils
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/maphash"
 	"io"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 // StringReader is the interface that wraps the ReadString method.
@@ -89,7 +98,8 @@ func NewFilteredStringReader(r StringReader, re *regexp.Regexp) *FilteredStringR
 
 // ReadString reads strings from the underlying StringReader and returns the
 // first one that matches the regular expression specified while creating the
-// FilteredStringReader. If no more strings can be read err is io.EOF.
+// FilteredStringReader. If no more strings can be read err is io.EOF, other
+// errors returned by the underlying StringReader are returned as is.
 func (f *FilteredStringReader) ReadString() (s string, err error) {
 	for s, err = f.r.ReadString(); s != "" || err == nil; s, err = f.r.ReadString() {
 		if f.re.MatchString(s) {
@@ -132,3 +142,310 @@ func StringReaderFromCmdArgs(args []string) StringReader {
 		return NewStringArrayReader(args)
 	}
 }
+
+// StringChannel sends the strings read from r to the returned channel, which
+// is closed once r returns an error or ctx is done. The returned function
+// returns the error that stopped the reading, or nil if it was io.EOF. It
+// must be called only after the channel is closed, for instance once all the
+// results of a vtcli.Client.BatchStream reading from the channel have been
+// received.
+func StringChannel(ctx context.Context, r StringReader) (<-chan string, func() error) {
+	ch := make(chan string)
+	var err error
+	go func() {
+		defer close(ch)
+		for {
+			var s string
+			s, err = r.ReadString()
+			if s == "" && err != nil {
+				return
+			}
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				err = ctx.Err()
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return ch, func() error {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+}
+
+// MalformedRecordError is the error returned by CSVColumnReader and
+// FieldStringReader for a CSV record or JSON Lines object that can't be
+// parsed. Reading can continue after it, starting with the next record.
+type MalformedRecordError struct {
+	Line int
+	Err  error
+}
+
+func (e *MalformedRecordError) Error() string {
+	return fmt.Sprintf("malformed record at line %d: %v", e.Line, e.Err)
+}
+
+func (e *MalformedRecordError) Unwrap() error {
+	return e.Err
+}
+
+// CSVColumnReader reads the strings in a given column of a CSV input.
+type CSVColumnReader struct {
+	r      *csv.Reader
+	column int
+}
+
+// newCSVReader returns a csv.Reader that accepts records with any number of
+// fields.
+func newCSVReader(r io.Reader) *csv.Reader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return cr
+}
+
+// NewCSVColumnReader creates a new CSVColumnReader that returns the strings
+// in the given column of the CSV records read from r. Columns are numbered
+// from 0, records without that column are skipped.
+func NewCSVColumnReader(r io.Reader, column int) *CSVColumnReader {
+	return &CSVColumnReader{r: newCSVReader(r), column: column}
+}
+
+// ReadString returns the string in the next CSV record that has a non-empty
+// value in the column. When all records have been read it returns an io.EOF
+// error. Records that can't be parsed are reported with a
+// MalformedRecordError.
+func (c *CSVColumnReader) ReadString() (string, error) {
+	for {
+		record, err := c.r.Read()
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			return "", &MalformedRecordError{Line: perr.StartLine, Err: perr.Err}
+		} else if err != nil {
+			return "", err
+		}
+		if c.column < len(record) {
+			if s := strings.TrimSpace(record[c.column]); s != "" {
+				return s, nil
+			}
+		}
+	}
+}
+
+// FieldStringReader reads the values of a given field from an input that can
+// be either JSON Lines or CSV with a header. The format is determined by the
+// first character in the input, JSON Lines inputs must start with "{".
+type FieldStringReader struct {
+	field   string
+	scanner *bufio.Scanner
+	line    int
+	csv     *CSVColumnReader
+	err     error
+}
+
+// NewFieldStringReader creates a new FieldStringReader that reads the values
+// of field from r.
+func NewFieldStringReader(r io.Reader, field string) *FieldStringReader {
+	br := bufio.NewReader(r)
+	f := &FieldStringReader{field: field}
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			f.err = err
+			return f
+		}
+		if !unicode.IsSpace(rune(b[0])) {
+			break
+		}
+		br.ReadByte()
+	}
+	if b, _ := br.Peek(1); b[0] == '{' {
+		f.scanner = bufio.NewScanner(br)
+		f.scanner.Buffer(nil, 16*1024*1024)
+		return f
+	}
+	cr := newCSVReader(br)
+	header, err := cr.Read()
+	if err != nil {
+		f.err = err
+		return f
+	}
+	for i, name := range header {
+		if strings.TrimSpace(name) == field {
+			f.csv = &CSVColumnReader{r: cr, column: i}
+			return f
+		}
+	}
+	f.err = fmt.Errorf("field %q not found in CSV header", field)
+	return f
+}
+
+// ReadString returns the next non-empty value of the field. JSON Lines
+// objects where the field is missing, or is not a string or a number, are
+// skipped. When all values have been read it returns an io.EOF error. Lines
+// that are not valid JSON objects, and CSV records that can't be parsed, are
+// reported with a MalformedRecordError.
+func (f *FieldStringReader) ReadString() (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	if f.csv != nil {
+		return f.csv.ReadString()
+	}
+	for f.scanner.Scan() {
+		f.line++
+		line := bytes.TrimSpace(f.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal(line, &obj); err != nil {
+			return "", &MalformedRecordError{Line: f.line, Err: err}
+		}
+		var s string
+		switch v := obj[f.field].(type) {
+		case string:
+			s = strings.TrimSpace(v)
+		case float64:
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		if s != "" {
+			return s, nil
+		}
+	}
+	if err := f.scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+// SkippingStringReader reads strings from a StringReader skipping the
+// records reported with a MalformedRecordError. The malformed records are
+// reported to a writer as they are found, and once all the strings have been
+// read a line with the number of skipped records is written too.
+type SkippingStringReader struct {
+	r       StringReader
+	w       io.Writer
+	skipped int
+	done    bool
+}
+
+// NewSkippingStringReader creates a new SkippingStringReader that reads
+// strings from r and reports the malformed records to w.
+func NewSkippingStringReader(r StringReader, w io.Writer) *SkippingStringReader {
+	return &SkippingStringReader{r: r, w: w}
+}
+
+// ReadString returns the next string read from the underlying StringReader,
+// skipping malformed records. Any other error is returned as is.
+func (sr *SkippingStringReader) ReadString() (string, error) {
+	for {
+		s, err := sr.r.ReadString()
+		var merr *MalformedRecordError
+		if errors.As(err, &merr) {
+			sr.skipped++
+			fmt.Fprintln(sr.w, err)
+			continue
+		}
+		if err == io.EOF && !sr.done && sr.skipped > 0 {
+			fmt.Fprintf(sr.w, "%d malformed records were skipped\n", sr.skipped)
+		}
+		if err != nil {
+			sr.done = true
+		}
+		return s, err
+	}
+}
+
+// DedupStringReader reads strings from a StringReader and returns only the
+// first occurrence of each of them. Instead of the strings themselves it
+// remembers a 128-bit hash of them, so the memory used per string is small
+// and doesn't depend on its length.
+type DedupStringReader struct {
+	r     StringReader
+	seeds [2]maphash.Seed
+	seen  map[[2]uint64]struct{}
+}
+
+// NewDedupStringReader creates a new DedupStringReader that reads strings
+// from r.
+func NewDedupStringReader(r StringReader) *DedupStringReader {
+	return &DedupStringReader{
+		r:     r,
+		seeds: [2]maphash.Seed{maphash.MakeSeed(), maphash.MakeSeed()},
+		seen:  make(map[[2]uint64]struct{}),
+	}
+}
+
+// ReadString returns the next string from the underlying StringReader that
+// hasn't been returned before.
+func (d *DedupStringReader) ReadString() (s string, err error) {
+	for s, err = d.r.ReadString(); s != "" || err == nil; s, err = d.r.ReadString() {
+		key := [2]uint64{
+			maphash.String(d.seeds[0], s),
+			maphash.String(d.seeds[1], s),
+		}
+		if _, ok := d.seen[key]; !ok {
+			d.seen[key] = struct{}{}
+			return s, err
+		}
+	}
+	return s, err
+}
+
+// NewInputReader returns a StringReader for the arguments passed in the
+// command line, configured according to the input options. If the arguments
+// consist in a single hyphen "-" they are read from opts.Stdin, either one per
+// line, or from the column or field specified with --input-column or
+// --input-csv-field. If --normalize is set the strings are transformed with
+// normalize, if not nil, and with --dedupe repeated strings are discarded
+// after normalization.
+//
+// If validate is not nil the strings rejected by it are reported to
+// opts.Stderr and skipped. With --strict all the strings are read and
+// validated before returning, and an error is returned if any of them is
+// invalid, so that the command fails before making any API call.
+//
+// Malformed CSV records and JSON Lines objects are reported to opts.Stderr
+// and skipped too. With --strict ReadString returns a MalformedRecordError
+// for them instead, and if validate is not nil NewInputReader fails.
+func NewInputReader(opts *Options, args []string, normalize func(string) string, validate Validator) (StringReader, error) {
+	var r StringReader
+	if len(args) == 1 && args[0] == "-" {
+		switch {
+		case opts.InputColumn != 0 && opts.InputField != "":
+			return nil, errors.New("--input-column and --input-csv-field can't be used together")
+		case opts.InputColumn < 0:
+			return nil, fmt.Errorf("invalid --input-column %d, columns are numbered from 1", opts.InputColumn)
+		case opts.InputColumn > 0:
+			r = NewCSVColumnReader(opts.Stdin, opts.InputColumn-1)
+		case opts.InputField != "":
+			r = NewFieldStringReader(opts.Stdin, opts.InputField)
+		default:
+			r = NewStringIOReader(opts.Stdin)
+		}
+		if !opts.Strict {
+			r = NewSkippingStringReader(r, opts.Stderr)
+		}
+	} else {
+		r = NewStringArrayReader(args)
+	}
+	if opts.Normalize && normalize != nil {
+		r = NewMappedStringReader(r, normalize)
+	}
+	if opts.Dedupe {
+		r = NewDedupStringReader(r)
+	}
+	if validate != nil {
+		if opts.Strict {
+			return validateAll(r, validate, opts.Stderr)
+		}
+		r = NewValidatingStringReader(r, validate, opts.Stderr)
+	}
+	return r, nil
+}
@@ -0,0 +1,214 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func readAll(t *testing.T, r utils.StringReader) []string {
+	var result []string
+	s, err := r.ReadString()
+	for ; err == nil; s, err = r.ReadString() {
+		result = append(result, s)
+	}
+	assert.Equal(t, "", s)
+	return result
+}
+
+func Test_CSVColumnReader(t *testing.T) {
+	r := utils.NewCSVColumnReader(strings.NewReader(
+		"a,44d88612fea8a8f36de82e1278abb02f\n"+
+			"b\n"+
+			"c,\" 76cdb2bad9582d23c1f6f4d868218d6c \",x\n"+
+			"d,\n"), 1)
+	assert.Equal(t, []string{
+		"44d88612fea8a8f36de82e1278abb02f",
+		"76cdb2bad9582d23c1f6f4d868218d6c",
+	}, readAll(t, r))
+}
+
+func Test_FieldStringReader(t *testing.T) {
+	r := utils.NewFieldStringReader(strings.NewReader(
+		"name,sha256\nfoo,aaaa\nbar,bbbb\n"), "sha256")
+	assert.Equal(t, []string{"aaaa", "bbbb"}, readAll(t, r))
+
+	r = utils.NewFieldStringReader(strings.NewReader(
+		"\n{\"sha256\": \"aaaa\"}\n{\"md5\": \"cccc\"}\n\n{\"sha256\": 1234}\n"), "sha256")
+	assert.Equal(t, []string{"aaaa", "1234"}, readAll(t, r))
+
+	r = utils.NewFieldStringReader(strings.NewReader("name,md5\nfoo,aaaa\n"), "sha256")
+	_, err := r.ReadString()
+	assert.EqualError(t, err, `field "sha256" not found in CSV header`)
+}
+
+func Test_MalformedRecords(t *testing.T) {
+	var merr *utils.MalformedRecordError
+
+	// Reading continues after a malformed record.
+	r := utils.NewCSVColumnReader(strings.NewReader("a,aaaa\nb,b\"b\nc,cccc\n"), 1)
+	s, err := r.ReadString()
+	assert.NoError(t, err)
+	assert.Equal(t, "aaaa", s)
+	_, err = r.ReadString()
+	if assert.True(t, errors.As(err, &merr)) {
+		assert.Equal(t, 2, merr.Line)
+	}
+	s, err = r.ReadString()
+	assert.NoError(t, err)
+	assert.Equal(t, "cccc", s)
+	_, err = r.ReadString()
+	assert.Equal(t, io.EOF, err)
+
+	fr := utils.NewFieldStringReader(strings.NewReader(
+		"{\"sha256\": \"aaaa\"}\n{\"sha256\": \n{\"sha256\": \"cccc\"}\n"), "sha256")
+	s, err = fr.ReadString()
+	assert.NoError(t, err)
+	assert.Equal(t, "aaaa", s)
+	_, err = fr.ReadString()
+	if assert.True(t, errors.As(err, &merr)) {
+		assert.Equal(t, 2, merr.Line)
+	}
+	s, err = fr.ReadString()
+	assert.NoError(t, err)
+	assert.Equal(t, "cccc", s)
+
+	// FilteredStringReader passes the error through.
+	fr = utils.NewFieldStringReader(strings.NewReader("{\"sha256\": \"aaaa\"}\nfoo\n"), "sha256")
+	f := utils.NewFilteredStringReader(fr, regexp.MustCompile("a+"))
+	s, err = f.ReadString()
+	assert.NoError(t, err)
+	assert.Equal(t, "aaaa", s)
+	_, err = f.ReadString()
+	assert.True(t, errors.As(err, &merr))
+}
+
+func Test_NewInputReader_Malformed(t *testing.T) {
+	input := "{\"sha256\": \"aaaa\"}\n{\"sha256\": \n{\"sha256\": \"cccc\"}\n"
+
+	// Without --strict malformed records are skipped with a warning.
+	var stderr bytes.Buffer
+	opts := &utils.Options{
+		Stdin:      strings.NewReader(input),
+		Stderr:     &stderr,
+		InputField: "sha256",
+	}
+	r, err := utils.NewInputReader(opts, []string{"-"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aaaa", "cccc"}, readAll(t, r))
+	assert.Contains(t, stderr.String(), "malformed record at line 2")
+	assert.Contains(t, stderr.String(), "1 malformed records were skipped")
+
+	// With --strict the error is returned.
+	opts = &utils.Options{
+		Stdin:      strings.NewReader(input),
+		Stderr:     &stderr,
+		InputField: "sha256",
+		Strict:     true,
+	}
+	r, err = utils.NewInputReader(opts, []string{"-"}, nil, nil)
+	assert.NoError(t, err)
+	s, err := r.ReadString()
+	assert.NoError(t, err)
+	assert.Equal(t, "aaaa", s)
+	_, err = r.ReadString()
+	assert.EqualError(t, err, "malformed record at line 2: unexpected end of JSON input")
+
+	// With --strict and a validator the reader can't be created.
+	opts.Stdin = strings.NewReader(input)
+	_, err = utils.NewInputReader(opts, []string{"-"}, nil, func(string) error { return nil })
+	assert.EqualError(t, err, "malformed record at line 2: unexpected end of JSON input")
+}
+
+func Test_DedupStringReader(t *testing.T) {
+	r := utils.NewDedupStringReader(utils.NewStringArrayReader(
+		[]string{"a", "b", "a", "c", "b"}))
+	assert.Equal(t, []string{"a", "b", "c"}, readAll(t, r))
+}
+
+// endlessReader is a StringReader that always returns "a".
+type endlessReader struct{}
+
+func (endlessReader) ReadString() (string, error) { return "a", nil }
+
+func Test_StringChannel(t *testing.T) {
+	ch, readErr := utils.StringChannel(context.Background(),
+		utils.NewStringArrayReader([]string{"a", "b", "c"}))
+	var got []string
+	for s := range ch {
+		got = append(got, s)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, got)
+	assert.NoError(t, readErr())
+
+	// Errors other than io.EOF are reported once the channel is closed.
+	r := utils.NewFieldStringReader(strings.NewReader("{\"sha256\": \"aaaa\"}\n{oops\n"), "sha256")
+	ch, readErr = utils.StringChannel(context.Background(), r)
+	got = nil
+	for s := range ch {
+		got = append(got, s)
+	}
+	assert.Equal(t, []string{"aaaa"}, got)
+	assert.Error(t, readErr())
+
+	// Reading stops when the context is cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, readErr = utils.StringChannel(ctx, endlessReader{})
+	assert.Equal(t, "a", <-ch)
+	cancel()
+	for range ch {
+	}
+	assert.ErrorIs(t, readErr(), context.Canceled)
+}
+
+func Test_NewInputReader(t *testing.T) {
+	opts := &utils.Options{
+		Stdin: strings.NewReader(
+			"Example.COM.\n" +
+				"example.com\n" +
+				"www.example.com\n"),
+		Normalize: true,
+		Dedupe:    true,
+	}
+	r, err := utils.NewInputReader(opts, []string{"-"}, utils.NormalizeDomain, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, readAll(t, r))
+
+	// Without --normalize the strings are deduplicated as they are.
+	opts = &utils.Options{Dedupe: true}
+	r, err = utils.NewInputReader(opts, []string{"A", "a", "A"}, utils.NormalizeHash, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A", "a"}, readAll(t, r))
+
+	opts = &utils.Options{
+		Stdin:       strings.NewReader("1,foo\n2,bar\n"),
+		InputColumn: 2,
+	}
+	r, err = utils.NewInputReader(opts, []string{"-"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, readAll(t, r))
+
+	opts = &utils.Options{InputColumn: 1, InputField: "foo"}
+	_, err = utils.NewInputReader(opts, []string{"-"}, nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// newObject returns an object with the given type, ID and attributes, which
+// are a JSON document.
+func newObject(t *testing.T, objType, id, attributes string) *vt.Object {
+	obj := &vt.Object{}
+	err := json.Unmarshal([]byte(
+		`{"type": "`+objType+`", "id": "`+id+`", "attributes": `+attributes+`}`), obj)
+	assert.NoError(t, err)
+	return obj
+}
@@ -0,0 +1,72 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CollectionTypeFilter returns a filter that matches the collections of the
+// given type, combined with the filter provided by the user, if any.
+func CollectionTypeFilter(collectionType, filter string) string {
+	f := fmt.Sprintf("collection_type:%s", collectionType)
+	if filter = strings.TrimSpace(filter); filter != "" {
+		f += " " + filter
+	}
+	return f
+}
+
+// TTP is a MITRE ATT&CK technique associated to a collection.
+type TTP struct {
+	ID      string
+	Name    string
+	Tactics []string
+}
+
+// TTPSummary groups techniques by tactic. A technique that belongs to more
+// than one tactic appears under each of them.
+func TTPSummary(ttps []TTP) map[string][]string {
+	summary := make(map[string][]string)
+	for _, t := range ttps {
+		for _, tactic := range t.Tactics {
+			summary[tactic] = append(summary[tactic], fmt.Sprintf("%s %s", t.ID, t.Name))
+		}
+	}
+	return summary
+}
+
+// TTPRow is a technique under one of its tactics.
+type TTPRow struct {
+	Tactic string
+	ID     string
+	Name   string
+}
+
+// TTPRows returns one row per technique and tactic, sorted by the order in
+// which tactics appear in the ATT&CK matrix. Within a tactic techniques keep
+// the order they have in ttps.
+func TTPRows(ttps []TTP) []TTPRow {
+	rows := make([]TTPRow, 0, len(ttps))
+	for _, t := range ttps {
+		for _, tactic := range t.Tactics {
+			rows = append(rows, TTPRow{tactic, t.ID, t.Name})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return AttackTacticOrder(rows[i].Tactic) < AttackTacticOrder(rows[j].Tactic)
+	})
+	return rows
+}
@@ -0,0 +1,113 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CollectionTypeFilter(t *testing.T) {
+	tests := []struct {
+		collectionType string
+		filter         string
+		want           string
+	}{
+		{"threat-actor", "", "collection_type:threat-actor"},
+		{"campaign", "   ", "collection_type:campaign"},
+		{"report", "targeted_region:US", "collection_type:report targeted_region:US"},
+		{"malware-family", "  name:emotet tag:loader ", "collection_type:malware-family name:emotet tag:loader"},
+	}
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			assert.Equal(t, test.want, utils.CollectionTypeFilter(test.collectionType, test.filter))
+		})
+	}
+}
+
+func Test_TTPSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		ttps []utils.TTP
+		want map[string][]string
+	}{
+		{
+			name: "empty",
+			ttps: nil,
+			want: map[string][]string{},
+		},
+		{
+			name: "technique in several tactics",
+			ttps: []utils.TTP{
+				{ID: "T1053", Name: "Scheduled Task/Job", Tactics: []string{"Execution", "Persistence"}},
+				{ID: "T1059", Name: "Command and Scripting Interpreter", Tactics: []string{"Execution"}},
+			},
+			want: map[string][]string{
+				"Execution":   {"T1053 Scheduled Task/Job", "T1059 Command and Scripting Interpreter"},
+				"Persistence": {"T1053 Scheduled Task/Job"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, utils.TTPSummary(test.ttps))
+		})
+	}
+}
+
+func Test_TTPRows(t *testing.T) {
+	tests := []struct {
+		name string
+		ttps []utils.TTP
+		want []utils.TTPRow
+	}{
+		{
+			name: "empty",
+			ttps: nil,
+			want: []utils.TTPRow{},
+		},
+		{
+			name: "sorted by tactic order, unknown tactics last",
+			ttps: []utils.TTP{
+				{ID: "T1003", Name: "OS Credential Dumping", Tactics: []string{"Credential Access"}},
+				{ID: "T1053", Name: "Scheduled Task/Job", Tactics: []string{"Persistence", "Execution"}},
+				{ID: "T1059", Name: "Command and Scripting Interpreter", Tactics: []string{"Execution"}},
+				{ID: "T9999", Name: "Mystery", Tactics: []string{"Unknown"}},
+				{ID: "T1566", Name: "Phishing", Tactics: []string{"Initial Access"}},
+			},
+			want: []utils.TTPRow{
+				{"Initial Access", "T1566", "Phishing"},
+				{"Execution", "T1053", "Scheduled Task/Job"},
+				{"Execution", "T1059", "Command and Scripting Interpreter"},
+				{"Persistence", "T1053", "Scheduled Task/Job"},
+				{"Credential Access", "T1003", "OS Credential Dumping"},
+				{"Unknown", "T9999", "Mystery"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, utils.TTPRows(test.ttps))
+		})
+	}
+}
+
+func Test_AttackTacticName(t *testing.T) {
+	assert.Equal(t, "Initial Access", utils.AttackTacticName("TA0001"))
+	assert.Equal(t, "TA9999", utils.AttackTacticName("TA9999"))
+	assert.Less(t, utils.AttackTacticOrder("Reconnaissance"), utils.AttackTacticOrder("Impact"))
+	assert.Equal(t, len(utils.AttackTactics), utils.AttackTacticOrder("Unknown"))
+}
@@ -0,0 +1,384 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/spf13/pflag"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// interestFlags maps the command-line flags accepted by "threatprofile create"
+// and "threatprofile update" to the interest types they set.
+var interestFlags = []struct {
+	flag         string
+	interestType string
+}{
+	{"targeted-industry", "INTEREST_TYPE_TARGETED_INDUSTRY"},
+	{"targeted-region", "INTEREST_TYPE_TARGETED_REGION"},
+	{"source-region", "INTEREST_TYPE_SOURCE_REGION"},
+	{"malware-role", "INTEREST_TYPE_MALWARE_ROLE"},
+	{"actor-motivation", "INTEREST_TYPE_ACTOR_MOTIVATION"},
+}
+
+// recommendationConfigFlags maps command-line flags to the keys they set in
+// the recommendation_config attribute of a Threat Profile.
+var recommendationConfigFlags = []struct {
+	flag string
+	key  string
+}{
+	{"max-recs-per-type", "max_recs_per_type"},
+	{"min-categories-matched", "min_categories_matched"},
+	{"max-days-since-last-seen", "max_days_since_last_seen"},
+}
+
+// countryCodes contains the ISO 3166-1 alpha-2 codes accepted as regions.
+var countryCodes = []string{
+	"AD", "AE", "AF", "AG", "AI", "AL", "AM", "AO", "AQ", "AR", "AS", "AT", "AU", "AW", "AX", "AZ",
+	"BA", "BB", "BD", "BE", "BF", "BG", "BH", "BI", "BJ", "BL", "BM", "BN", "BO", "BQ", "BR", "BS",
+	"BT", "BV", "BW", "BY", "BZ", "CA", "CC", "CD", "CF", "CG", "CH", "CI", "CK", "CL", "CM", "CN",
+	"CO", "CR", "CU", "CV", "CW", "CX", "CY", "CZ", "DE", "DJ", "DK", "DM", "DO", "DZ", "EC", "EE",
+	"EG", "EH", "ER", "ES", "ET", "FI", "FJ", "FK", "FM", "FO", "FR", "GA", "GB", "GD", "GE", "GF",
+	"GG", "GH", "GI", "GL", "GM", "GN", "GP", "GQ", "GR", "GS", "GT", "GU", "GW", "GY", "HK", "HM",
+	"HN", "HR", "HT", "HU", "ID", "IE", "IL", "IM", "IN", "IO", "IQ", "IR", "IS", "IT", "JE", "JM",
+	"JO", "JP", "KE", "KG", "KH", "KI", "KM", "KN", "KP", "KR", "KW", "KY", "KZ", "LA", "LB", "LC",
+	"LI", "LK", "LR", "LS", "LT", "LU", "LV", "LY", "MA", "MC", "MD", "ME", "MF", "MG", "MH", "MK",
+	"ML", "MM", "MN", "MO", "MP", "MQ", "MR", "MS", "MT", "MU", "MV", "MW", "MX", "MY", "MZ", "NA",
+	"NC", "NE", "NF", "NG", "NI", "NL", "NO", "NP", "NR", "NU", "NZ", "OM", "PA", "PE", "PF", "PG",
+	"PH", "PK", "PL", "PM", "PN", "PR", "PS", "PT", "PW", "PY", "QA", "RE", "RO", "RS", "RU", "RW",
+	"SA", "SB", "SC", "SD", "SE", "SG", "SH", "SI", "SJ", "SK", "SL", "SM", "SN", "SO", "SR", "SS",
+	"ST", "SV", "SX", "SY", "SZ", "TC", "TD", "TF", "TG", "TH", "TJ", "TK", "TL", "TM", "TN", "TO",
+	"TR", "TT", "TV", "TW", "TZ", "UA", "UG", "UM", "US", "UY", "UZ", "VA", "VC", "VE", "VG", "VI",
+	"VN", "VU", "WF", "WS", "YE", "YT", "ZA", "ZM", "ZW",
+}
+
+// bundledInterestValues contains the values accepted for each interest type.
+// It can be replaced with an up-to-date list using --interest-values.
+var bundledInterestValues = map[string][]string{
+	"INTEREST_TYPE_TARGETED_INDUSTRY": {
+		"Aerospace & Defense", "Agriculture", "Automotive",
+		"Chemicals & Materials", "Civil Society & Non-Profits",
+		"Construction & Engineering", "Education", "Energy & Utilities",
+		"Financial Services", "Government", "Healthcare", "Hospitality",
+		"Insurance", "Legal & Professional Services", "Manufacturing",
+		"Media & Entertainment", "Oil & Gas", "Pharmaceuticals", "Retail",
+		"Technology", "Telecommunications", "Transportation",
+	},
+	"INTEREST_TYPE_TARGETED_REGION": countryCodes,
+	"INTEREST_TYPE_SOURCE_REGION":   countryCodes,
+	"INTEREST_TYPE_MALWARE_ROLE": {
+		"Backdoor", "Botnet", "Bootkit", "Credential Stealer",
+		"Cryptocurrency Miner", "Data Miner", "Downloader", "Dropper",
+		"File Infector", "Infostealer", "Keylogger", "Launcher",
+		"Point-of-Sale Malware", "Ransomware", "Reconnaissance",
+		"Remote Access Tool", "Rootkit", "Screen Capture", "Spambot",
+		"Sniffer", "Tunneler", "Uploader", "Utility", "Web Shell", "Wiper",
+	},
+	"INTEREST_TYPE_ACTOR_MOTIVATION": {
+		"Attack / Destruction", "Cybercrime", "Espionage", "Financial Gain",
+		"Hacktivism", "Influence Operations", "Surveillance",
+	},
+}
+
+// ThreatProfileSpec is the representation of a Threat Profile used in spec
+// files. Interests are keyed by their short names (e.g: targeted_region)
+// but the full interest types (e.g: INTEREST_TYPE_TARGETED_REGION) are
+// accepted too.
+type ThreatProfileSpec struct {
+	Name                 string              `yaml:"name,omitempty" json:"name,omitempty"`
+	Interests            map[string][]string `yaml:"interests,omitempty" json:"interests,omitempty"`
+	RecommendationConfig map[string]int      `yaml:"recommendation_config,omitempty" json:"recommendation_config,omitempty"`
+}
+
+// interestType returns the full interest type for an interest name used in a
+// spec file.
+func interestType(name string) string {
+	name = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(name), "-", "_"))
+	if strings.HasPrefix(name, "INTEREST_TYPE_") {
+		return name
+	}
+	return "INTEREST_TYPE_" + name
+}
+
+// ParseThreatProfileSpec parses a Threat Profile spec in YAML or JSON.
+func ParseThreatProfileSpec(data []byte) (*ThreatProfileSpec, error) {
+	spec := &ThreatProfileSpec{}
+	// JSON is a subset of YAML, the same decoder works for both.
+	if err := yamlv3.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+	interests := make(map[string][]string, len(spec.Interests))
+	for name, values := range spec.Interests {
+		interests[interestType(name)] = values
+	}
+	spec.Interests = interests
+	return spec, nil
+}
+
+// ThreatProfileSpecFromObject returns the spec for an existing Threat Profile.
+func ThreatProfileSpecFromObject(obj *vt.Object) *ThreatProfileSpec {
+	spec := &ThreatProfileSpec{
+		Interests:            make(map[string][]string),
+		RecommendationConfig: make(map[string]int),
+	}
+	spec.Name, _ = obj.GetString("name")
+	for t, values := range ProfileInterests(obj) {
+		spec.Interests[InterestCategory(t)] = values
+	}
+	for _, f := range recommendationConfigFlags {
+		if v, err := obj.GetInt64("recommendation_config." + f.key); err == nil {
+			spec.RecommendationConfig[f.key] = int(v)
+		}
+	}
+	return spec
+}
+
+// MergeFlags overrides the values in the spec with the ones provided in the
+// command-line flags that were explicitly set by the user.
+func (s *ThreatProfileSpec) MergeFlags(flags *pflag.FlagSet) {
+	if s.Interests == nil {
+		s.Interests = make(map[string][]string)
+	}
+	if s.RecommendationConfig == nil {
+		s.RecommendationConfig = make(map[string]int)
+	}
+	if flags.Changed("name") {
+		s.Name, _ = flags.GetString("name")
+	}
+	for _, f := range interestFlags {
+		if flags.Changed(f.flag) {
+			s.Interests[f.interestType], _ = flags.GetStringSlice(f.flag)
+		}
+	}
+	for _, f := range recommendationConfigFlags {
+		if flags.Changed(f.flag) {
+			s.RecommendationConfig[f.key], _ = flags.GetInt(f.flag)
+		}
+	}
+}
+
+// IsEmpty returns true if the spec doesn't set any attribute.
+func (s *ThreatProfileSpec) IsEmpty() bool {
+	return s.Name == "" && len(s.Interests) == 0 && len(s.RecommendationConfig) == 0
+}
+
+// Apply sets the attributes defined by the spec in obj.
+func (s *ThreatProfileSpec) Apply(obj *vt.Object) {
+	if s.Name != "" {
+		obj.SetString("name", s.Name)
+	}
+	if len(s.Interests) > 0 {
+		interests := make(map[string]interface{}, len(s.Interests))
+		for t, values := range s.Interests {
+			interests[t] = values
+		}
+		obj.Set("interests", interests)
+	}
+	if len(s.RecommendationConfig) > 0 {
+		config := make(map[string]interface{}, len(s.RecommendationConfig))
+		for k, v := range s.RecommendationConfig {
+			config[k] = v
+		}
+		obj.Set("recommendation_config", config)
+	}
+}
+
+// Canonicalize replaces the interest values in the spec that match accepted
+// ones regardless of case with the accepted spelling. Other values are left
+// as they are.
+func (s *ThreatProfileSpec) Canonicalize(accepted map[string][]string) {
+	for t, values := range s.Interests {
+		for i, v := range values {
+			for _, a := range accepted[t] {
+				if strings.EqualFold(a, v) {
+					values[i] = a
+					break
+				}
+			}
+		}
+	}
+}
+
+// Validate checks the interest values in the spec against the accepted ones.
+// All the invalid values are reported in the returned error, together with
+// suggestions for values that look like typos.
+func (s *ThreatProfileSpec) Validate(accepted map[string][]string) error {
+	var problems []string
+	types := make([]string, 0, len(s.Interests))
+	for t := range s.Interests {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		values, known := accepted[t]
+		if !known {
+			problems = append(problems, fmt.Sprintf("unknown interest type %q", InterestCategory(t)))
+			continue
+		}
+		for _, v := range s.Interests[t] {
+			if containsFold(values, v) {
+				continue
+			}
+			msg := fmt.Sprintf("invalid %s %q", InterestCategory(t), v)
+			if suggestions := Suggest(v, values); len(suggestions) > 0 {
+				msg += fmt.Sprintf(", did you mean %s?", strings.Join(quoteAll(suggestions), " or "))
+			}
+			problems = append(problems, msg)
+		}
+	}
+	limits := map[string][2]int{
+		"max_recs_per_type":        {1, 20},
+		"min_categories_matched":   {1, 5},
+		"max_days_since_last_seen": {1, 365},
+	}
+	keys := make([]string, 0, len(s.RecommendationConfig))
+	for k := range s.RecommendationConfig {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := s.RecommendationConfig[k]
+		if l, ok := limits[k]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown recommendation_config key %q", k))
+		} else if v < l[0] || v > l[1] {
+			problems = append(problems, fmt.Sprintf("%s must be between %d and %d", k, l[0], l[1]))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// LoadInterestValues returns the accepted interest values. If source is empty
+// the bundled values are returned, if not, source is a file or HTTP(S) URL
+// with a YAML or JSON document that maps interest types to lists of values.
+// URLs are fetched with the HTTP client for the given options, and if source
+// is "-" the document is read from the options' Stdin.
+func LoadInterestValues(source string, opts *Options) (map[string][]string, error) {
+	if source == "" {
+		return bundledInterestValues, nil
+	}
+	var data []byte
+	var err error
+	switch {
+	case strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://"):
+		var client *http.Client
+		if client, err = opts.HTTPClient(); err != nil {
+			return nil, err
+		}
+		var resp *http.Response
+		if resp, err = client.Get(source); err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s: %s", source, resp.Status)
+		}
+		data, err = io.ReadAll(resp.Body)
+	case source == "-":
+		data, err = io.ReadAll(opts.Stdin)
+	default:
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m map[string][]string
+	if err := yamlv3.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", source, err)
+	}
+	values := make(map[string][]string, len(m))
+	for name, v := range m {
+		values[interestType(name)] = v
+	}
+	return values, nil
+}
+
+func containsFold(l []string, s string) bool {
+	for _, item := range l {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(l []string) []string {
+	quoted := make([]string, len(l))
+	for i, s := range l {
+		quoted[i] = fmt.Sprintf("%q", s)
+	}
+	return quoted
+}
+
+// Suggest returns the values in candidates that are similar to s, ignoring
+// case, at most three of them and sorted by similarity. It's used for
+// suggesting alternatives to values that look like typos.
+func Suggest(s string, candidates []string) []string {
+	s = strings.ToLower(s)
+	maxDistance := len(s) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+	type scored struct {
+		value    string
+		distance int
+	}
+	var matches []scored
+	for _, c := range candidates {
+		d := levenshtein(s, strings.ToLower(c))
+		if d <= maxDistance {
+			matches = append(matches, scored{c, d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+	var result []string
+	for i := 0; i < len(matches) && i < 3; i++ {
+		result = append(result, matches[i].value)
+	}
+	return result
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
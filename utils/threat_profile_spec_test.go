@@ -0,0 +1,306 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	vt "github.com/VirusTotal/vt-go"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseThreatProfileSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		spec *utils.ThreatProfileSpec
+		err  bool
+	}{
+		{
+			name: "yaml with short names",
+			data: `
+name: My profile
+interests:
+  targeted_region: [US, ES]
+  actor-motivation: [Espionage]
+recommendation_config:
+  max_recs_per_type: 5`,
+			spec: &utils.ThreatProfileSpec{
+				Name: "My profile",
+				Interests: map[string][]string{
+					"INTEREST_TYPE_TARGETED_REGION":  {"US", "ES"},
+					"INTEREST_TYPE_ACTOR_MOTIVATION": {"Espionage"},
+				},
+				RecommendationConfig: map[string]int{"max_recs_per_type": 5},
+			},
+		},
+		{
+			name: "json with full interest types",
+			data: `{"name": "P", "interests": {"INTEREST_TYPE_MALWARE_ROLE": ["Wiper"]}}`,
+			spec: &utils.ThreatProfileSpec{
+				Name:      "P",
+				Interests: map[string][]string{"INTEREST_TYPE_MALWARE_ROLE": {"Wiper"}},
+			},
+		},
+		{
+			name: "invalid",
+			data: `interests: [1, 2]`,
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec, err := utils.ParseThreatProfileSpec([]byte(test.data))
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.spec, spec)
+		})
+	}
+}
+
+func Test_ThreatProfileSpecMergeFlags(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("name", "", "")
+	flags.StringSlice("targeted-region", nil, "")
+	flags.StringSlice("malware-role", nil, "")
+	flags.Int("max-recs-per-type", 10, "")
+	flags.Int("min-categories-matched", 1, "")
+	assert.NoError(t, flags.Parse([]string{
+		"--targeted-region", "FR,DE", "--max-recs-per-type", "3"}))
+
+	spec := &utils.ThreatProfileSpec{
+		Name: "From file",
+		Interests: map[string][]string{
+			"INTEREST_TYPE_TARGETED_REGION": {"US"},
+			"INTEREST_TYPE_MALWARE_ROLE":    {"Wiper"},
+		},
+	}
+	spec.MergeFlags(flags)
+	// Only the flags set in the command line override the file.
+	assert.Equal(t, &utils.ThreatProfileSpec{
+		Name: "From file",
+		Interests: map[string][]string{
+			"INTEREST_TYPE_TARGETED_REGION": {"FR", "DE"},
+			"INTEREST_TYPE_MALWARE_ROLE":    {"Wiper"},
+		},
+		RecommendationConfig: map[string]int{"max_recs_per_type": 3},
+	}, spec)
+	assert.False(t, spec.IsEmpty())
+	assert.True(t, (&utils.ThreatProfileSpec{}).IsEmpty())
+}
+
+func Test_ThreatProfileSpecFromObject(t *testing.T) {
+	obj := newObject(t, "threat_profile", "p", `{
+		"name": "P",
+		"interests": {"INTEREST_TYPE_SOURCE_REGION": ["CN"]},
+		"recommendation_config": {"max_recs_per_type": 10, "min_categories_matched": 2}
+	}`)
+	spec := utils.ThreatProfileSpecFromObject(obj)
+	assert.Equal(t, &utils.ThreatProfileSpec{
+		Name:                 "P",
+		Interests:            map[string][]string{"source_region": {"CN"}},
+		RecommendationConfig: map[string]int{"max_recs_per_type": 10, "min_categories_matched": 2},
+	}, spec)
+
+	// Exported specs can be applied to new objects.
+	data := fmt.Sprintf("name: %s\ninterests:\n  source_region: [CN]\n", spec.Name)
+	parsed, err := utils.ParseThreatProfileSpec([]byte(data))
+	assert.NoError(t, err)
+	created := vt.NewObject("threat_profile")
+	parsed.Apply(created)
+	name, _ := created.GetString("name")
+	assert.Equal(t, "P", name)
+	assert.Equal(t, map[string][]string{"INTEREST_TYPE_SOURCE_REGION": {"CN"}}, utils.ProfileInterests(created))
+}
+
+func Test_ThreatProfileSpecValidate(t *testing.T) {
+	accepted := map[string][]string{
+		"INTEREST_TYPE_TARGETED_REGION":   {"US", "ES", "FR"},
+		"INTEREST_TYPE_TARGETED_INDUSTRY": {"Financial Services", "Healthcare", "Government"},
+	}
+	tests := []struct {
+		name string
+		spec utils.ThreatProfileSpec
+		err  string
+	}{
+		{
+			name: "valid, case insensitive",
+			spec: utils.ThreatProfileSpec{
+				Interests: map[string][]string{
+					"INTEREST_TYPE_TARGETED_REGION":   {"us"},
+					"INTEREST_TYPE_TARGETED_INDUSTRY": {"healthcare"},
+				},
+				RecommendationConfig: map[string]int{"max_recs_per_type": 20},
+			},
+		},
+		{
+			name: "typo with suggestion",
+			spec: utils.ThreatProfileSpec{Interests: map[string][]string{
+				"INTEREST_TYPE_TARGETED_INDUSTRY": {"Helthcare"},
+			}},
+			err: `invalid targeted_industry "Helthcare", did you mean "Healthcare"?`,
+		},
+		{
+			name: "invalid without suggestion",
+			spec: utils.ThreatProfileSpec{Interests: map[string][]string{
+				"INTEREST_TYPE_TARGETED_INDUSTRY": {"Space Mining"},
+			}},
+			err: `invalid targeted_industry "Space Mining"`,
+		},
+		{
+			name: "unknown interest type",
+			spec: utils.ThreatProfileSpec{Interests: map[string][]string{
+				"INTEREST_TYPE_FAVORITE_COLOR": {"blue"},
+			}},
+			err: `unknown interest type "favorite_color"`,
+		},
+		{
+			name: "all problems reported",
+			spec: utils.ThreatProfileSpec{
+				Interests: map[string][]string{
+					"INTEREST_TYPE_TARGETED_REGION": {"US", "XX"},
+				},
+				RecommendationConfig: map[string]int{
+					"min_categories_matched": 6,
+					"max_recs_per_type":      0,
+					"foo":                    1,
+				},
+			},
+			err: strings.Join([]string{
+				`invalid targeted_region "XX", did you mean "US" or "ES" or "FR"?`,
+				`unknown recommendation_config key "foo"`,
+				`max_recs_per_type must be between 1 and 20`,
+				`min_categories_matched must be between 1 and 5`,
+			}, "\n"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.spec.Validate(accepted)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func Test_ThreatProfileSpecCanonicalize(t *testing.T) {
+	accepted := map[string][]string{
+		"INTEREST_TYPE_MALWARE_ROLE":    {"Ransomware", "Remote Access Tool"},
+		"INTEREST_TYPE_TARGETED_REGION": {"US"},
+	}
+	spec := utils.ThreatProfileSpec{Interests: map[string][]string{
+		"INTEREST_TYPE_MALWARE_ROLE":     {"ransomware", "REMOTE access tool", "Infostealer"},
+		"INTEREST_TYPE_TARGETED_REGION":  {"us"},
+		"INTEREST_TYPE_ACTOR_MOTIVATION": {"espionage"},
+	}}
+	spec.Canonicalize(accepted)
+	assert.Equal(t, map[string][]string{
+		"INTEREST_TYPE_MALWARE_ROLE":     {"Ransomware", "Remote Access Tool", "Infostealer"},
+		"INTEREST_TYPE_TARGETED_REGION":  {"US"},
+		"INTEREST_TYPE_ACTOR_MOTIVATION": {"espionage"},
+	}, spec.Interests)
+}
+
+func Test_Suggest(t *testing.T) {
+	candidates := []string{"Backdoor", "Botnet", "Bootkit", "Ransomware", "Remote Access Tool"}
+	tests := []struct {
+		s           string
+		suggestions []string
+	}{
+		{"ransomware", []string{"Ransomware"}},
+		{"Ransomwre", []string{"Ransomware"}},
+		{"Botkit", []string{"Bootkit", "Botnet"}},
+		{"Remote Access Tol", []string{"Remote Access Tool"}},
+		{"Keylogger", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			assert.Equal(t, test.suggestions, utils.Suggest(test.s, candidates))
+		})
+	}
+}
+
+func Test_LoadInterestValues(t *testing.T) {
+	doc := "targeted_region: [US, ES]\nINTEREST_TYPE_MALWARE_ROLE: [Wiper]\n"
+	want := map[string][]string{
+		"INTEREST_TYPE_TARGETED_REGION": {"US", "ES"},
+		"INTEREST_TYPE_MALWARE_ROLE":    {"Wiper"},
+	}
+
+	file := filepath.Join(t.TempDir(), "values.yaml")
+	assert.NoError(t, os.WriteFile(file, []byte(doc), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/values.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, doc)
+	}))
+	defer server.Close()
+
+	// A proxy that serves the document for any URL, for checking that the
+	// proxy in the options is used.
+	proxied := 0
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied++
+		fmt.Fprint(w, doc)
+	}))
+	defer proxy.Close()
+
+	tests := []struct {
+		name   string
+		source string
+		opts   *utils.Options
+		want   map[string][]string
+		err    bool
+	}{
+		{name: "file", source: file, opts: &utils.Options{}, want: want},
+		{name: "stdin", source: "-", opts: &utils.Options{Stdin: strings.NewReader(doc)}, want: want},
+		{name: "url", source: server.URL + "/values.yaml", opts: &utils.Options{}, want: want},
+		{name: "url not found", source: server.URL + "/missing.yaml", opts: &utils.Options{}, err: true},
+		{name: "proxy", source: "http://values.invalid/values.yaml", opts: &utils.Options{Proxy: proxy.URL}, want: want},
+		{name: "missing file", source: filepath.Join(t.TempDir(), "missing"), opts: &utils.Options{}, err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := utils.LoadInterestValues(test.source, test.opts)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, values)
+		})
+	}
+	assert.Equal(t, 1, proxied)
+
+	bundled, err := utils.LoadInterestValues("", &utils.Options{})
+	assert.NoError(t, err)
+	assert.Contains(t, bundled["INTEREST_TYPE_TARGETED_REGION"], "US")
+}
@@ -0,0 +1,226 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	vt "github.com/VirusTotal/vt-go"
+)
+
+// TimelineDateLayout is the layout of the dates in timeline events, which are
+// always in UTC so they can be sorted as strings.
+const TimelineDateLayout = "2006-01-02T15:04:05Z"
+
+// TimelineEvent is something that happened to an IOC at some date.
+type TimelineEvent struct {
+	Date   string `json:"date" yaml:"date" csv:"date"`
+	IOC    string `json:"ioc" yaml:"ioc" csv:"ioc"`
+	Event  string `json:"event" yaml:"event" csv:"event"`
+	Detail string `json:"detail" yaml:"detail" csv:"detail"`
+}
+
+// TimelineRelationships are the relationships of each object type whose
+// objects are events in the timeline, see RelatedEvents.
+var TimelineRelationships = map[string][]string{
+	"file":       {"submissions", "analyses"},
+	"url":        {"submissions", "analyses"},
+	"domain":     {"resolutions", "historical_whois", "historical_ssl_certificates"},
+	"ip_address": {"resolutions", "historical_whois", "historical_ssl_certificates"},
+}
+
+// objectDateEvents are the date attributes of objects that are events in the
+// timeline. Objects of different types have different subsets of them.
+var objectDateEvents = []struct{ attr, event string }{
+	{"creation_date", "creation"},
+	{"first_submission_date", "first_submission"},
+	{"last_submission_date", "last_submission"},
+	{"first_seen_itw_date", "first_seen_in_the_wild"},
+	{"last_analysis_date", "last_analysis"},
+	{"last_modification_date", "last_modification"},
+	{"last_update_date", "whois_update"},
+	{"whois_date", "whois_retrieval"},
+	{"last_dns_records_date", "dns_records_retrieval"},
+	{"last_https_certificate_date", "https_certificate_retrieval"},
+}
+
+func newTimelineEvent(t time.Time, ioc, event, detail string) TimelineEvent {
+	return TimelineEvent{
+		Date:   t.UTC().Format(TimelineDateLayout),
+		IOC:    ioc,
+		Event:  event,
+		Detail: detail,
+	}
+}
+
+// analysisStats describes the analysis stats in attr, like the
+// last_analysis_stats of files.
+func analysisStats(obj *vt.Object, attr string) string {
+	malicious, err := obj.GetInt64(attr + ".malicious")
+	if err != nil {
+		return ""
+	}
+	suspicious, _ := obj.GetInt64(attr + ".suspicious")
+	return fmt.Sprintf("%d malicious, %d suspicious", malicious, suspicious)
+}
+
+// certificateEvents returns the start and the end of the validity of the
+// certificate in attr, which is "" for SSL certificate objects.
+func certificateEvents(obj *vt.Object, ioc, attr string) []TimelineEvent {
+	if attr != "" {
+		attr += "."
+	}
+	subject, _ := obj.GetString(attr + "subject.CN")
+	issuer, _ := obj.GetString(attr + "issuer.O")
+	if issuer == "" {
+		issuer, _ = obj.GetString(attr + "issuer.CN")
+	}
+	detail := "CN=" + subject
+	if issuer != "" {
+		detail += " issued by " + issuer
+	}
+	var events []TimelineEvent
+	for _, e := range []struct{ attr, event string }{
+		{"validity.not_before", "certificate_valid_from"},
+		{"validity.not_after", "certificate_valid_until"},
+	} {
+		s, err := obj.GetString(attr + e.attr)
+		if err != nil {
+			continue
+		}
+		if t, err := time.Parse("2006-01-02 15:04:05", s); err == nil {
+			events = append(events, newTimelineEvent(t, ioc, e.event, detail))
+		}
+	}
+	return events
+}
+
+// ObjectEvents returns the events in the attributes of the object for an IOC,
+// unsorted.
+func ObjectEvents(ioc string, obj *vt.Object) []TimelineEvent {
+	var events []TimelineEvent
+	for _, e := range objectDateEvents {
+		t, err := obj.GetTime(e.attr)
+		if err != nil || t.Unix() == 0 {
+			continue
+		}
+		var detail string
+		if e.attr == "last_analysis_date" {
+			detail = analysisStats(obj, "last_analysis_stats")
+		}
+		events = append(events, newTimelineEvent(t, ioc, e.event, detail))
+	}
+	return append(events, certificateEvents(obj, ioc, "last_https_certificate")...)
+}
+
+// RelatedEvents returns the events for an object related to an IOC through
+// one of the TimelineRelationships, unsorted.
+func RelatedEvents(ioc string, obj *vt.Object) []TimelineEvent {
+	get := func(attr string) string {
+		s, _ := obj.GetString(attr)
+		return s
+	}
+	var events []TimelineEvent
+	add := func(attr, event, detail string) {
+		if t, err := obj.GetTime(attr); err == nil && t.Unix() != 0 {
+			events = append(events, newTimelineEvent(t, ioc, event, detail))
+		}
+	}
+	switch obj.Type() {
+	case "submission":
+		var parts []string
+		for _, attr := range []string{"name", "interface", "country"} {
+			if s := get(attr); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		add("date", "submission", strings.Join(parts, ", "))
+	case "analysis":
+		add("date", "analysis", analysisStats(obj, "stats"))
+	case "resolution":
+		add("date", "dns_resolution", get("host_name")+" -> "+get("ip_address"))
+	case "whois":
+		add("last_updated", "whois_change", get("registrar_name"))
+	case "ssl_cert":
+		events = append(events, certificateEvents(obj, ioc, "")...)
+	}
+	return events
+}
+
+// SortTimeline sorts events chronologically. Events with the same date keep
+// their order.
+func SortTimeline(events []TimelineEvent) {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date < events[j].Date })
+}
+
+// FilterTimeline returns the events between from and to, both included. Zero
+// times are not bounds.
+func FilterTimeline(events []TimelineEvent, from, to time.Time) []TimelineEvent {
+	var filtered []TimelineEvent
+	for _, e := range events {
+		if !from.IsZero() && e.Date < from.UTC().Format(TimelineDateLayout) {
+			continue
+		}
+		if !to.IsZero() && e.Date > to.UTC().Format(TimelineDateLayout) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+var relativeTimeRe = regexp.MustCompile(`^(\d+)([hdw])$`)
+
+// ParseTimeBound parses a date given as YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS or in
+// RFC 3339 format, or relative to now, like 12h, 7d or 2w for 12 hours, 7
+// days or 2 weeks ago. Dates without time zone are in UTC.
+func ParseTimeBound(s string, now time.Time) (time.Time, error) {
+	if m := relativeTimeRe.FindStringSubmatch(s); m != nil {
+		var n int
+		fmt.Sscan(m[1], &n)
+		d := time.Duration(n) * time.Hour
+		switch m[2] {
+		case "d":
+			d *= 24
+		case "w":
+			d *= 24 * 7
+		}
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expecting YYYY-MM-DD, YYYY-MM-DDTHH:MM:SS or a relative date like 7d", s)
+}
+
+// WriteMarkdownTimeline writes the events as a Markdown table.
+func WriteMarkdownTimeline(w io.Writer, events []TimelineEvent) error {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+	var b strings.Builder
+	b.WriteString("| Date | IOC | Event | Detail |\n")
+	b.WriteString("|------|-----|-------|--------|\n")
+	for _, e := range events {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+			e.Date, escape.Replace(e.IOC), e.Event, escape.Replace(e.Detail))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
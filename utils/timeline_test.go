@@ -0,0 +1,108 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ObjectEvents(t *testing.T) {
+	obj := newObject(t, "domain", "example.com", `{
+		"creation_date": 946684800,
+		"last_analysis_date": 1704067200,
+		"last_analysis_stats": {"malicious": 3, "suspicious": 1, "harmless": 60},
+		"last_https_certificate": {
+			"subject": {"CN": "example.com"},
+			"issuer": {"O": "Let's Encrypt", "CN": "R3"},
+			"validity": {"not_before": "2023-12-01 00:00:00", "not_after": "2024-02-29 23:59:59"}
+		}
+	}`)
+	events := utils.ObjectEvents("example.com", obj)
+	utils.SortTimeline(events)
+	assert.Equal(t, []utils.TimelineEvent{
+		{Date: "2000-01-01T00:00:00Z", IOC: "example.com", Event: "creation"},
+		{Date: "2023-12-01T00:00:00Z", IOC: "example.com", Event: "certificate_valid_from", Detail: "CN=example.com issued by Let's Encrypt"},
+		{Date: "2024-01-01T00:00:00Z", IOC: "example.com", Event: "last_analysis", Detail: "3 malicious, 1 suspicious"},
+		{Date: "2024-02-29T23:59:59Z", IOC: "example.com", Event: "certificate_valid_until", Detail: "CN=example.com issued by Let's Encrypt"},
+	}, events)
+}
+
+func Test_RelatedEvents(t *testing.T) {
+	assert.Equal(t, []utils.TimelineEvent{
+		{Date: "2024-01-01T00:00:00Z", IOC: "1.2.3.4", Event: "dns_resolution", Detail: "example.com -> 1.2.3.4"},
+	}, utils.RelatedEvents("1.2.3.4", newObject(t, "resolution", "x",
+		`{"date": 1704067200, "host_name": "example.com", "ip_address": "1.2.3.4"}`)))
+
+	assert.Equal(t, []utils.TimelineEvent{
+		{Date: "2024-01-01T00:00:00Z", IOC: "h", Event: "submission", Detail: "a.exe, web, ES"},
+	}, utils.RelatedEvents("h", newObject(t, "submission", "x",
+		`{"date": 1704067200, "name": "a.exe", "interface": "web", "country": "ES"}`)))
+
+	assert.Equal(t, []utils.TimelineEvent{
+		{Date: "2024-01-01T00:00:00Z", IOC: "d", Event: "whois_change", Detail: "Registrar Inc."},
+	}, utils.RelatedEvents("d", newObject(t, "whois", "x",
+		`{"last_updated": 1704067200, "registrar_name": "Registrar Inc."}`)))
+
+	assert.Len(t, utils.RelatedEvents("d", newObject(t, "ssl_cert", "x",
+		`{"subject": {"CN": "d"}, "validity": {"not_before": "2023-12-01 00:00:00", "not_after": "2024-02-29 23:59:59"}}`)), 2)
+
+	assert.Empty(t, utils.RelatedEvents("d", newObject(t, "comment", "x", `{"date": 1704067200}`)))
+}
+
+func Test_FilterTimeline(t *testing.T) {
+	events := []utils.TimelineEvent{
+		{Date: "2023-12-31T23:59:59Z"},
+		{Date: "2024-01-01T00:00:00Z"},
+		{Date: "2024-01-15T00:00:00Z"},
+		{Date: "2024-02-01T00:00:00Z"},
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, events[1:3], utils.FilterTimeline(events, from, to))
+	assert.Equal(t, events[1:], utils.FilterTimeline(events, from, time.Time{}))
+	assert.Equal(t, events, utils.FilterTimeline(events, time.Time{}, time.Time{}))
+}
+
+func Test_ParseTimeBound(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for s, expected := range map[string]time.Time{
+		"2024-01-01":           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2024-01-01T10:30:00":  time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+		"2024-01-01T10:30:00Z": time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+		"12h":                  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		"7d":                   time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC),
+		"1w":                   time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC),
+	} {
+		bound, err := utils.ParseTimeBound(s, now)
+		assert.NoError(t, err, s)
+		assert.True(t, expected.Equal(bound), s)
+	}
+	_, err := utils.ParseTimeBound("yesterday", now)
+	assert.Error(t, err)
+}
+
+func Test_WriteMarkdownTimeline(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, utils.WriteMarkdownTimeline(&b, []utils.TimelineEvent{
+		{Date: "2024-01-01T00:00:00Z", IOC: "example.com", Event: "whois_change", Detail: "a|b"},
+	}))
+	assert.Equal(t, "| Date | IOC | Event | Detail |\n"+
+		"|------|-----|-------|--------|\n"+
+		"| 2024-01-01T00:00:00Z | example.com | whois_change | a\\|b |\n", b.String())
+}
@@ -0,0 +1,157 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+)
+
+// Validator checks whether a string is a valid input for a command. It
+// returns nil if the string is valid, or an error describing why it's not.
+type Validator func(string) error
+
+// hashLengths are the lengths of MD5, SHA-1 and SHA-256 hex digests.
+var hashLengths = map[int]bool{32: true, 40: true, 64: true}
+
+// ValidateHash accepts MD5, SHA-1 and SHA-256 hashes.
+func ValidateHash(s string) error {
+	if err := validateHex(s); err != nil {
+		return err
+	}
+	if !hashLengths[len(s)] {
+		return fmt.Errorf("wrong length %d, expecting 32 (MD5), 40 (SHA-1) or 64 (SHA-256)", len(s))
+	}
+	return nil
+}
+
+// ValidateSHA256 accepts SHA-256 hashes only.
+func ValidateSHA256(s string) error {
+	if err := validateHex(s); err != nil {
+		return err
+	}
+	if len(s) != 64 {
+		return fmt.Errorf("wrong length %d, expecting 64 (SHA-256)", len(s))
+	}
+	return nil
+}
+
+func validateHex(s string) error {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return fmt.Errorf("non-hex character %q", c)
+		}
+	}
+	return nil
+}
+
+// ValidateIP accepts IPv4 and IPv6 addresses.
+func ValidateIP(s string) error {
+	if net.ParseIP(s) == nil {
+		return errors.New("invalid IP address")
+	}
+	return nil
+}
+
+// ValidateRegexp returns a Validator that accepts the strings matching re.
+// Strings that don't match are rejected with the given reason.
+func ValidateRegexp(re *regexp.Regexp, reason string) Validator {
+	return func(s string) error {
+		if !re.MatchString(s) {
+			return errors.New(reason)
+		}
+		return nil
+	}
+}
+
+// InvalidInputError is the error returned for an input rejected by a
+// Validator.
+type InvalidInputError struct {
+	Input string
+	Err   error
+}
+
+func (e *InvalidInputError) Error() string {
+	return fmt.Sprintf("invalid input %q: %v", e.Input, e.Err)
+}
+
+func (e *InvalidInputError) Unwrap() error {
+	return e.Err
+}
+
+// ValidatingStringReader reads strings from a StringReader and returns only
+// the ones accepted by a Validator. The rejected strings are reported to a
+// writer as they are found, and once all the strings have been read a line
+// with the number of rejected ones is written too.
+type ValidatingStringReader struct {
+	r        StringReader
+	validate Validator
+	w        io.Writer
+	total    int
+	rejected int
+	done     bool
+}
+
+// NewValidatingStringReader creates a new ValidatingStringReader that reads
+// strings from r and reports the invalid ones to w.
+func NewValidatingStringReader(r StringReader, validate Validator, w io.Writer) *ValidatingStringReader {
+	return &ValidatingStringReader{r: r, validate: validate, w: w}
+}
+
+// ReadString returns the next valid string read from the underlying
+// StringReader.
+func (v *ValidatingStringReader) ReadString() (s string, err error) {
+	for s, err = v.r.ReadString(); s != "" || err == nil; s, err = v.r.ReadString() {
+		v.total++
+		if verr := v.validate(s); verr != nil {
+			v.rejected++
+			fmt.Fprintln(v.w, &InvalidInputError{Input: s, Err: verr})
+			continue
+		}
+		return s, err
+	}
+	if !v.done && v.rejected > 0 {
+		fmt.Fprintf(v.w, "%d of %d inputs were invalid and skipped\n", v.rejected, v.total)
+	}
+	v.done = true
+	return s, err
+}
+
+// validateAll reads all the strings from r and returns a reader for them if
+// all of them are valid. Otherwise the invalid ones are reported to w and an
+// error is returned. Errors returned by r, other than io.EOF, are returned
+// too.
+func validateAll(r StringReader, validate Validator, w io.Writer) (StringReader, error) {
+	var valid []string
+	invalid := 0
+	s, err := r.ReadString()
+	for ; s != "" || err == nil; s, err = r.ReadString() {
+		if verr := validate(s); verr != nil {
+			invalid++
+			fmt.Fprintln(w, &InvalidInputError{Input: s, Err: verr})
+		} else {
+			valid = append(valid, s)
+		}
+	}
+	if err != io.EOF {
+		return nil, err
+	}
+	if invalid > 0 {
+		return nil, fmt.Errorf("%d of %d inputs are invalid", invalid, invalid+len(valid))
+	}
+	return NewStringArrayReader(valid), nil
+}
@@ -0,0 +1,67 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateHash(t *testing.T) {
+	assert.NoError(t, utils.ValidateHash("44d88612fea8a8f36de82e1278abb02f"))
+	assert.NoError(t, utils.ValidateHash("3395856CE81F2B7382DEE72602F798B642F14140"))
+	assert.EqualError(t,
+		utils.ValidateHash("44d88612fea8a8f36de82e1278abb02"),
+		"wrong length 31, expecting 32 (MD5), 40 (SHA-1) or 64 (SHA-256)")
+	assert.EqualError(t,
+		utils.ValidateHash("44d88612fea8a8f36de82e1278abb0zf"),
+		`non-hex character 'z'`)
+	assert.Error(t, utils.ValidateSHA256("44d88612fea8a8f36de82e1278abb02f"))
+}
+
+func Test_ValidateIP(t *testing.T) {
+	assert.NoError(t, utils.ValidateIP("8.8.8.8"))
+	assert.NoError(t, utils.ValidateIP("2001:db8::1"))
+	assert.EqualError(t, utils.ValidateIP("8.8.8.256"), "invalid IP address")
+}
+
+func Test_ValidatingStringReader(t *testing.T) {
+	var stderr bytes.Buffer
+	validate := utils.ValidateRegexp(regexp.MustCompile(`^\d+$`), "not a number")
+	r := utils.NewValidatingStringReader(
+		utils.NewStringArrayReader([]string{"1", "a", "2", "b"}), validate, &stderr)
+	assert.Equal(t, []string{"1", "2"}, readAll(t, r))
+	assert.Equal(t,
+		"invalid input \"a\": not a number\n"+
+			"invalid input \"b\": not a number\n"+
+			"2 of 4 inputs were invalid and skipped\n",
+		stderr.String())
+}
+
+func Test_NewInputReader_Strict(t *testing.T) {
+	var stderr bytes.Buffer
+	opts := &utils.Options{Strict: true, Stderr: &stderr}
+	_, err := utils.NewInputReader(opts,
+		[]string{"8.8.8.8", "8.8.8", "1.1.1.1"}, nil, utils.ValidateIP)
+	assert.EqualError(t, err, "1 of 3 inputs are invalid")
+	assert.Equal(t, "invalid input \"8.8.8\": invalid IP address\n", stderr.String())
+
+	r, err := utils.NewInputReader(opts, []string{"8.8.8.8", "1.1.1.1"}, nil, utils.ValidateIP)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"8.8.8.8", "1.1.1.1"}, readAll(t, r))
+}
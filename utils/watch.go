@@ -0,0 +1,109 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WatchState is what a watched search remembers between runs: the objects
+// that were already reported, and the collection where they are added.
+type WatchState struct {
+	Query string `json:"query"`
+	// Collection is the ID of the collection created for the search, if any.
+	Collection string `json:"collection,omitempty"`
+	// Seen maps the identifiers of the objects already reported to the last
+	// time they matched the search, as a UNIX timestamp.
+	Seen map[string]int64 `json:"seen"`
+}
+
+// LoadWatchState reads the state of a watched search from a file. If the file
+// doesn't exist a new state is returned. It's an error if the file belongs to
+// a different query.
+func LoadWatchState(path, query string) (*WatchState, error) {
+	s := &WatchState{Query: query, Seen: make(map[string]int64)}
+	if path == "" {
+		return s, nil
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if s.Query != query {
+		return nil, fmt.Errorf("%s is the state of a different query: %s", path, s.Query)
+	}
+	if s.Seen == nil {
+		s.Seen = make(map[string]int64)
+	}
+	return s, nil
+}
+
+// Save writes the state to a file. The file is replaced atomically, so it's
+// never left half-written if the program is interrupted.
+func (s *WatchState) Save(path string) error {
+	if path == "" {
+		return nil
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Known returns true if the object with the given ID was already reported.
+func (s *WatchState) Known(id string) bool {
+	_, ok := s.Seen[id]
+	return ok
+}
+
+// Mark records that the object with the given ID matched the search at time
+// t.
+func (s *WatchState) Mark(id string, t time.Time) {
+	s.Seen[id] = t.Unix()
+}
+
+// Forget removes the objects that didn't match the search since the given
+// time. If they match again they are reported as new.
+func (s *WatchState) Forget(before time.Time) {
+	for id, last := range s.Seen {
+		if last < before.Unix() {
+			delete(s.Seen, id)
+		}
+	}
+}
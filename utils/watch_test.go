@@ -0,0 +1,54 @@
+// Copyright © 2026 The VirusTotal CLI authors. All Rights Reserved.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/VirusTotal/vt-cli/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WatchState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watch.json")
+	t0 := time.Unix(1700000000, 0)
+
+	s, err := utils.LoadWatchState(path, "p:5+")
+	assert.NoError(t, err)
+	assert.False(t, s.Known("a"))
+	s.Mark("a", t0)
+	s.Mark("b", t0)
+	s.Collection = "c1"
+	assert.NoError(t, s.Save(path))
+
+	s, err = utils.LoadWatchState(path, "p:5+")
+	assert.NoError(t, err)
+	assert.Equal(t, "c1", s.Collection)
+	assert.True(t, s.Known("a"))
+	assert.False(t, s.Known("c"))
+	s.Mark("a", t0.Add(time.Hour))
+	s.Mark("c", t0.Add(time.Hour))
+
+	// b didn't match in the last run, it's forgotten.
+	s.Forget(t0.Add(time.Minute))
+	assert.Equal(t, map[string]int64{
+		"a": t0.Add(time.Hour).Unix(),
+		"c": t0.Add(time.Hour).Unix(),
+	}, s.Seen)
+
+	_, err = utils.LoadWatchState(path, "p:10+")
+	assert.EqualError(t, err, path+" is the state of a different query: p:5+")
+}